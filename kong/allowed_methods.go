@@ -0,0 +1,47 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// AllowedMethods returns the HTTP methods supported by the Admin API at
+// path, as listed by the Allow header of the response to an OPTIONS
+// request. Methods are upper-cased and returned in the order of the
+// header, without duplicates.
+// Generic tooling can use it to avoid requests which an endpoint doesn't
+// support, e.g. a PATCH on an endpoint only supporting PUT.
+func (c *Client) AllowedMethods(ctx context.Context, path string) ([]string, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path cannot be empty for AllowedMethods operation")
+	}
+	req, err := c.NewRequest(http.MethodOptions, path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(ctx, req, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return parseAllowHeader(resp.Header.Values("Allow")), nil
+}
+
+// parseAllowHeader parses the comma-separated methods of Allow headers.
+func parseAllowHeader(values []string) []string {
+	var methods []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, method := range strings.Split(value, ",") {
+			method = strings.ToUpper(strings.TrimSpace(method))
+			if method == "" || seen[method] {
+				continue
+			}
+			seen[method] = true
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
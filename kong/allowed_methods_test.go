@@ -0,0 +1,42 @@
+package kong
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllowedMethods(t *testing.T) {
+	client := newFakeKongClient(t, newFakeKong(t, map[string]fakeResponse{
+		"OPTIONS /services": {
+			status: http.StatusNoContent,
+			header: http.Header{"Allow": {"GET, HEAD, OPTIONS, POST"}},
+		},
+		"OPTIONS /services/svc1": {
+			status: http.StatusNoContent,
+			header: http.Header{"Allow": {"GET, head, DELETE,", "PATCH, PUT, GET"}},
+		},
+		"OPTIONS /status": {status: http.StatusNoContent},
+		"*":               {status: http.StatusNotFound, body: `{"message": "Not found"}`},
+	}))
+
+	methods, err := client.AllowedMethods(defaultCtx, "/services")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET", "HEAD", "OPTIONS", "POST"}, methods)
+
+	methods, err = client.AllowedMethods(defaultCtx, "/services/svc1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GET", "HEAD", "DELETE", "PATCH", "PUT"}, methods)
+
+	methods, err = client.AllowedMethods(defaultCtx, "/status")
+	require.NoError(t, err)
+	assert.Empty(t, methods)
+
+	_, err = client.AllowedMethods(defaultCtx, "/missing")
+	assert.True(t, IsNotFoundErr(err))
+
+	_, err = client.AllowedMethods(defaultCtx, "")
+	assert.Error(t, err)
+}
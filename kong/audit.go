@@ -0,0 +1,38 @@
+package kong
+
+// AuditRequest represents an entry of the audit log of the requests made
+// to the Admin API of Kong Enterprise.
+// Read https://docs.konghq.com/gateway/latest/kong-enterprise/audit-log/
+// +k8s:deepcopy-gen=true
+type AuditRequest struct {
+	RequestID        *string `json:"request_id,omitempty" yaml:"request_id,omitempty"`
+	RequestTimestamp *int    `json:"request_timestamp,omitempty" yaml:"request_timestamp,omitempty"`
+	ClientIP         *string `json:"client_ip,omitempty" yaml:"client_ip,omitempty"`
+	Method           *string `json:"method,omitempty" yaml:"method,omitempty"`
+	Path             *string `json:"path,omitempty" yaml:"path,omitempty"`
+	Payload          *string `json:"payload,omitempty" yaml:"payload,omitempty"`
+	Status           *int    `json:"status,omitempty" yaml:"status,omitempty"`
+	RBACUserID       *string `json:"rbac_user_id,omitempty" yaml:"rbac_user_id,omitempty"`
+	RBACUserName     *string `json:"rbac_user_name,omitempty" yaml:"rbac_user_name,omitempty"`
+	RequestSource    *string `json:"request_source,omitempty" yaml:"request_source,omitempty"`
+	Workspace        *string `json:"workspace,omitempty" yaml:"workspace,omitempty"`
+	Signature        *string `json:"signature,omitempty" yaml:"signature,omitempty"`
+	TTL              *int    `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+}
+
+// AuditObject represents an entry of the audit log of the changes made to
+// the entities of Kong Enterprise through the Admin API.
+// Read https://docs.konghq.com/gateway/latest/kong-enterprise/audit-log/
+// +k8s:deepcopy-gen=true
+type AuditObject struct {
+	ID               *string `json:"id,omitempty" yaml:"id,omitempty"`
+	RequestID        *string `json:"request_id,omitempty" yaml:"request_id,omitempty"`
+	RequestTimestamp *int    `json:"request_timestamp,omitempty" yaml:"request_timestamp,omitempty"`
+	DAOName          *string `json:"dao_name,omitempty" yaml:"dao_name,omitempty"`
+	Operation        *string `json:"operation,omitempty" yaml:"operation,omitempty"`
+	EntityKey        *string `json:"entity_key,omitempty" yaml:"entity_key,omitempty"`
+	Entity           *string `json:"entity,omitempty" yaml:"entity,omitempty"`
+	RBACUserID       *string `json:"rbac_user_id,omitempty" yaml:"rbac_user_id,omitempty"`
+	Signature        *string `json:"signature,omitempty" yaml:"signature,omitempty"`
+	TTL              *int    `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+}
@@ -0,0 +1,139 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// AbstractAuditService handles the audit logs of Kong Enterprise.
+type AbstractAuditService interface {
+	// ListRequests fetches a list of audited Admin API requests.
+	ListRequests(ctx context.Context, opt *ListOpt) ([]*AuditRequest, *ListOpt, error)
+	// ListAllRequests fetches all audited Admin API requests.
+	ListAllRequests(ctx context.Context) ([]*AuditRequest, error)
+	// ListObjects fetches a list of audited changes of entities.
+	ListObjects(ctx context.Context, opt *ListOpt) ([]*AuditObject, *ListOpt, error)
+	// ListAllObjects fetches all audited changes of entities.
+	ListAllObjects(ctx context.Context) ([]*AuditObject, error)
+}
+
+// AuditService handles the audit logs of Kong Enterprise.
+// The audit logs are only available on Kong Enterprise, with the
+// audit_log configuration property enabled.
+type AuditService service
+
+// ListRequests fetches a list of audited Admin API requests.
+// opt can be used to control pagination.
+func (s *AuditService) ListRequests(ctx context.Context,
+	opt *ListOpt,
+) ([]*AuditRequest, *ListOpt, error) {
+	if err := s.checkEnterprise(ctx); err != nil {
+		return nil, nil, err
+	}
+	return s.listRequests(ctx, opt)
+}
+
+func (s *AuditService) listRequests(ctx context.Context,
+	opt *ListOpt,
+) ([]*AuditRequest, *ListOpt, error) {
+	data, next, err := s.client.list(ctx, "/audit/requests", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	var requests []*AuditRequest
+	for _, object := range data {
+		var request AuditRequest
+		if err := json.Unmarshal(object, &request); err != nil {
+			return nil, nil, err
+		}
+		requests = append(requests, &request)
+	}
+	return requests, next, nil
+}
+
+// ListAllRequests fetches all audited Admin API requests.
+// Kong is only checked to be Kong Enterprise once, before the first page.
+func (s *AuditService) ListAllRequests(ctx context.Context) ([]*AuditRequest, error) {
+	if err := s.checkEnterprise(ctx); err != nil {
+		return nil, err
+	}
+	var requests, data []*AuditRequest
+	var err error
+	opt := &ListOpt{Size: pageSize}
+
+	for opt != nil {
+		data, opt, err = s.listRequests(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		requests = append(requests, data...)
+	}
+	return requests, nil
+}
+
+// ListObjects fetches a list of audited changes of entities.
+// opt can be used to control pagination.
+func (s *AuditService) ListObjects(ctx context.Context,
+	opt *ListOpt,
+) ([]*AuditObject, *ListOpt, error) {
+	if err := s.checkEnterprise(ctx); err != nil {
+		return nil, nil, err
+	}
+	return s.listObjects(ctx, opt)
+}
+
+func (s *AuditService) listObjects(ctx context.Context,
+	opt *ListOpt,
+) ([]*AuditObject, *ListOpt, error) {
+	data, next, err := s.client.list(ctx, "/audit/objects", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	var objects []*AuditObject
+	for _, raw := range data {
+		var object AuditObject
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return nil, nil, err
+		}
+		objects = append(objects, &object)
+	}
+	return objects, next, nil
+}
+
+// ListAllObjects fetches all audited changes of entities.
+// Kong is only checked to be Kong Enterprise once, before the first page.
+func (s *AuditService) ListAllObjects(ctx context.Context) ([]*AuditObject, error) {
+	if err := s.checkEnterprise(ctx); err != nil {
+		return nil, err
+	}
+	var objects, data []*AuditObject
+	var err error
+	opt := &ListOpt{Size: pageSize}
+
+	for opt != nil {
+		data, opt, err = s.listObjects(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, data...)
+	}
+	return objects, nil
+}
+
+// checkEnterprise returns an error if Kong isn't Kong Enterprise, which
+// the audit logs require.
+func (s *AuditService) checkEnterprise(ctx context.Context) error {
+	info, err := s.client.Root(ctx)
+	if err != nil {
+		return err
+	}
+	version, err := ParseSemanticVersion(VersionFromInfo(info))
+	if err != nil {
+		return err
+	}
+	if !version.IsKongGatewayEnterprise() {
+		return fmt.Errorf("audit logs require Kong Gateway Enterprise, got %s", version)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAuditServer returns a fake Kong serving two pages of audited requests
+// and a page of audited objects.
+func newAuditServer(t *testing.T, rootResponse string) *fakeKong {
+	return newFakeKong(t, map[string]fakeResponse{
+		"/": {body: rootResponse},
+		"/audit/requests": {body: `{
+			"data": [{
+				"client_ip": "127.0.0.1",
+				"method": "POST",
+				"path": "/services",
+				"payload": "{\"name\":\"svc1\",\"host\":\"example.com\"}",
+				"rbac_user_id": "2e959b45-0053-41cc-9c2c-5458d0964331",
+				"rbac_user_name": "alice",
+				"request_id": "OjOcUBvt6q6XJlX3dd6BSpy1uUkTyctC",
+				"request_source": "kong-manager",
+				"request_timestamp": 1581617463,
+				"signature": null,
+				"status": 201,
+				"ttl": 2591995,
+				"workspace": "default"
+			}],
+			"offset": "WyIyMDIwLTAyLTEzIl0"
+		}`},
+		"/audit/requests?offset=WyIyMDIwLTAyLTEzIl0": {body: `{
+			"data": [{
+				"method": "DELETE",
+				"path": "/services/svc1",
+				"request_id": "eYBAfSqLNSkyGCOucHNps7UZmybcK35b",
+				"request_timestamp": 1581617471,
+				"status": 204
+			}]
+		}`},
+		"/audit/objects": {body: `{
+			"data": [{
+				"dao_name": "services",
+				"entity": "{\"name\":\"svc1\",\"host\":\"example.com\"}",
+				"entity_key": "7dc653c7-f15c-4b53-9a0d-c2ae41b11ed6",
+				"expire": 1584209463,
+				"id": "7ebabee7-2b09-445d-bc1f-2092c4ddc4be",
+				"operation": "create",
+				"request_id": "OjOcUBvt6q6XJlX3dd6BSpy1uUkTyctC",
+				"request_timestamp": 1581617463,
+				"ttl": 2591995
+			}]
+		}`},
+	})
+}
+
+func TestAuditService(t *testing.T) {
+	srv := newAuditServer(t, enterpriseRootResponse)
+	client := newFakeKongClient(t, srv)
+
+	requests, next, err := client.Audit.ListRequests(defaultCtx, nil)
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	require.Len(t, requests, 1)
+	assert.Equal(t, &AuditRequest{
+		ClientIP:         String("127.0.0.1"),
+		Method:           String("POST"),
+		Path:             String("/services"),
+		Payload:          String(`{"name":"svc1","host":"example.com"}`),
+		RBACUserID:       String("2e959b45-0053-41cc-9c2c-5458d0964331"),
+		RBACUserName:     String("alice"),
+		RequestID:        String("OjOcUBvt6q6XJlX3dd6BSpy1uUkTyctC"),
+		RequestSource:    String("kong-manager"),
+		RequestTimestamp: Int(1581617463),
+		Status:           Int(201),
+		TTL:              Int(2591995),
+		Workspace:        String("default"),
+	}, requests[0])
+
+	srv.reset()
+	requests, err = client.Audit.ListAllRequests(defaultCtx)
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Equal(t, 1, srv.count("/"), "Kong is only checked once for all pages")
+	assert.Equal(t, "DELETE", *requests[1].Method)
+	assert.Equal(t, 204, *requests[1].Status)
+
+	objects, err := client.Audit.ListAllObjects(defaultCtx)
+	require.NoError(t, err)
+	require.Len(t, objects, 1)
+	assert.Equal(t, "services", *objects[0].DAOName)
+	assert.Equal(t, "create", *objects[0].Operation)
+	assert.Equal(t, "7dc653c7-f15c-4b53-9a0d-c2ae41b11ed6", *objects[0].EntityKey)
+	assert.Equal(t, "OjOcUBvt6q6XJlX3dd6BSpy1uUkTyctC", *objects[0].RequestID)
+}
+
+func TestAuditServiceOSS(t *testing.T) {
+	client := newFakeKongClient(t, newAuditServer(t, ossRootResponse))
+
+	_, err := client.Audit.ListAllRequests(defaultCtx)
+	assert.ErrorContains(t, err, "audit logs require Kong Gateway Enterprise")
+	_, _, err = client.Audit.ListObjects(defaultCtx, nil)
+	assert.Error(t, err)
+}
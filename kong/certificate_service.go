@@ -20,6 +20,12 @@ type AbstractCertificateService interface {
 	List(ctx context.Context, opt *ListOpt) ([]*Certificate, *ListOpt, error)
 	// ListAll fetches all Certificates in Kong.
 	ListAll(ctx context.Context) ([]*Certificate, error)
+	// ListAllByTags fetches all Certificates in Kong matching tags.
+	ListAllByTags(ctx context.Context, tags []*string, matchAllTags bool) ([]*Certificate, error)
+	// Dependents fetches all SNIs in Kong which use a Certificate.
+	Dependents(ctx context.Context, certificateID *string) ([]*SNI, error)
+	// CanDelete checks whether a Certificate can be deleted in Kong.
+	CanDelete(ctx context.Context, certificateID *string) (bool, []string, error)
 }
 
 // CertificateService handles Certificates in Kong.
@@ -158,3 +164,65 @@ func (s *CertificateService) ListAll(ctx context.Context) ([]*Certificate,
 	}
 	return certificates, nil
 }
+
+// Dependents fetches all SNIs in Kong which use the Certificate
+// identified by certificateID. A Certificate can't be deleted as long
+// as it has dependents.
+func (s *CertificateService) Dependents(ctx context.Context,
+	certificateID *string,
+) ([]*SNI, error) {
+	if isEmptyString(certificateID) {
+		return nil, fmt.Errorf("certificateID cannot be nil")
+	}
+
+	var snis, data []*SNI
+	var err error
+	opt := &ListOpt{Size: pageSize}
+
+	for opt != nil {
+		data, opt, err = s.client.SNIs.ListForCertificate(ctx, certificateID, opt)
+		if err != nil {
+			return nil, err
+		}
+		snis = append(snis, data...)
+	}
+	return snis, nil
+}
+
+// CanDelete checks whether the Certificate identified by certificateID
+// can be deleted in Kong, i.e. whether no SNI depends on it.
+// If it can't, the reasons are returned, one per dependent SNI.
+func (s *CertificateService) CanDelete(ctx context.Context,
+	certificateID *string,
+) (bool, []string, error) {
+	snis, err := s.Dependents(ctx, certificateID)
+	if err != nil {
+		return false, nil, err
+	}
+	var reasons []string
+	for _, sni := range snis {
+		reasons = append(reasons,
+			fmt.Sprintf("certificate is used by SNI '%s'", sni.FriendlyName()))
+	}
+	return len(reasons) == 0, reasons, nil
+}
+
+// ListAllByTags fetches all Certificates in Kong matching tags.
+// If matchAllTags is true, only Certificates having every tag are
+// returned, otherwise Certificates having at least one of the tags are.
+func (s *CertificateService) ListAllByTags(ctx context.Context,
+	tags []*string, matchAllTags bool,
+) ([]*Certificate, error) {
+	var certificates, data []*Certificate
+	var err error
+	opt := &ListOpt{Size: pageSize, Tags: tags, MatchAllTags: matchAllTags}
+
+	for opt != nil {
+		data, opt, err = s.List(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, data...)
+	}
+	return certificates, nil
+}
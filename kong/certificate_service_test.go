@@ -412,6 +412,53 @@ func TestCertificateListEndpoint(T *testing.T) {
 	}
 }
 
+func TestCertificateDependents(T *testing.T) {
+	RunWhenDBMode(T, "postgres")
+
+	assert := assert.New(T)
+	require := require.New(T)
+
+	client, err := NewTestClient(nil, nil)
+	require.NoError(err)
+	require.NotNil(client)
+
+	certificate, err := client.Certificates.Create(defaultCtx, &Certificate{
+		Key:  String(key1),
+		Cert: String(cert1),
+	})
+	require.NoError(err)
+	require.NotNil(certificate)
+
+	for _, name := range []string{"foo.example.com", "bar.example.com"} {
+		sni, err := client.SNIs.Create(defaultCtx, &SNI{
+			Name:        String(name),
+			Certificate: certificate,
+		})
+		require.NoError(err)
+		require.NotNil(sni)
+	}
+
+	dependents, err := client.Certificates.Dependents(defaultCtx, certificate.ID)
+	require.NoError(err)
+	assert.Len(dependents, 2)
+
+	ok, reasons, err := client.Certificates.CanDelete(defaultCtx, certificate.ID)
+	require.NoError(err)
+	assert.False(ok)
+	assert.Len(reasons, 2)
+
+	for _, sni := range dependents {
+		assert.NoError(client.SNIs.Delete(defaultCtx, sni.ID))
+	}
+
+	ok, reasons, err = client.Certificates.CanDelete(defaultCtx, certificate.ID)
+	require.NoError(err)
+	assert.True(ok)
+	assert.Empty(reasons)
+
+	assert.NoError(client.Certificates.Delete(defaultCtx, certificate.ID))
+}
+
 func compareCertificates(T *testing.T, expected, actual []*Certificate) bool {
 	var expectedUsernames, actualUsernames []string
 	for _, certificate := range expected {
@@ -427,3 +474,27 @@ func compareCertificates(T *testing.T, expected, actual []*Certificate) bool {
 
 	return (compareSlices(expectedUsernames, actualUsernames))
 }
+
+func TestCertificatesListAllByTags(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/certificates":           {body: `{"data": [{"id": "1"}], "offset": "o1"}`},
+		"/certificates?offset=o1": fakeList(`[{"id": "2"}]`),
+	})
+	client := newFakeKongClient(t, srv)
+
+	t.Run("any tag", func(t *testing.T) {
+		srv.reset()
+		res, err := client.Certificates.ListAllByTags(defaultCtx, StringSlice("foo", "bar"), false)
+		require.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, []string{"foo/bar", "foo/bar"}, srv.queries("tags"))
+	})
+
+	t.Run("all tags", func(t *testing.T) {
+		srv.reset()
+		res, err := client.Certificates.ListAllByTags(defaultCtx, StringSlice("foo", "bar"), true)
+		require.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, []string{"foo,bar", "foo,bar"}, srv.queries("tags"))
+	})
+}
@@ -0,0 +1,34 @@
+package kong
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// EntityChecksum returns a checksum of entity which only changes when the
+// configuration of entity changes: the fields managed by Kong (id,
+// created_at and updated_at) are excluded and the remaining fields are
+// hashed in their canonical JSON representation, with sorted keys.
+// Two entities of the same type holding the same configuration have the
+// same checksum, which makes it usable as a cache key between runs.
+func EntityChecksum(entity interface{}) (string, error) {
+	if entity == nil {
+		return "", fmt.Errorf("entity cannot be nil")
+	}
+	obj, err := toJSONObject(entity)
+	if err != nil {
+		return "", fmt.Errorf("encoding entity: %w", err)
+	}
+	for _, field := range planManagedFields {
+		delete(obj, field)
+	}
+	// maps are encoded with sorted keys
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
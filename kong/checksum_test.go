@@ -0,0 +1,55 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityChecksum(t *testing.T) {
+	plugin := &Plugin{
+		ID:        String("p1"),
+		CreatedAt: Int(1),
+		Name:      String("rate-limiting"),
+		Config:    Configuration{"minute": 10, "policy": "local"},
+		Protocols: StringSlice("http", "https"),
+	}
+	checksum, err := EntityChecksum(plugin)
+	require.NoError(t, err)
+	assert.Len(t, checksum, 64)
+
+	// fields managed by Kong and the order of config keys are ignored
+	same, err := EntityChecksum(&Plugin{
+		ID:        String("p2"),
+		CreatedAt: Int(2),
+		Name:      String("rate-limiting"),
+		Config:    Configuration{"policy": "local", "minute": 10},
+		Protocols: StringSlice("http", "https"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, checksum, same)
+
+	changed := plugin.DeepCopy()
+	changed.Config["minute"] = 20
+	other, err := EntityChecksum(changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, checksum, other)
+
+	changed = plugin.DeepCopy()
+	changed.Protocols = StringSlice("https", "http")
+	other, err = EntityChecksum(changed)
+	require.NoError(t, err)
+	assert.NotEqual(t, checksum, other)
+
+	service, err := EntityChecksum(&Service{Name: String("svc1"), UpdatedAt: Int(1)})
+	require.NoError(t, err)
+	other, err = EntityChecksum(&Service{Name: String("svc1")})
+	require.NoError(t, err)
+	assert.Equal(t, service, other)
+
+	_, err = EntityChecksum(nil)
+	assert.Error(t, err)
+	_, err = EntityChecksum(make(chan int))
+	assert.Error(t, err)
+}
@@ -1,8 +1,10 @@
 package kong
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net"
@@ -10,6 +12,10 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,6 +27,14 @@ const (
 	// DefaultTimeout is the timeout used for network connections and requests
 	// including TCP, TLS and HTTP layers.
 	DefaultTimeout = 60 * time.Second
+	// DefaultAccept is the Accept header sent in requests, see WithAccept.
+	DefaultAccept = "application/json"
+	// DefaultMaxPageSize is the largest page size requested by default,
+	// see SetMaxPageSize.
+	DefaultMaxPageSize = 1000
+	// DefaultCorrelationHeader is the header carrying the correlation ID
+	// of requests, see WithCorrelationID and WithCorrelationHeader.
+	DefaultCorrelationHeader = "X-Correlation-ID"
 )
 
 var pageSize = 1000
@@ -38,6 +52,8 @@ type Client struct {
 	baseRootURL             string
 	workspace               string       // Do not access directly. Use Workspace()/SetWorkspace().
 	workspaceLock           sync.RWMutex // Synchronizes access to workspace.
+	maxPageSize             int          // Do not access directly. Use MaxPageSize()/SetMaxPageSize().
+	maxPageSizeLock         sync.RWMutex // Synchronizes access to maxPageSize.
 	common                  service
 	ConsumerGroupConsumers  AbstractConsumerGroupConsumerService
 	ConsumerGroups          AbstractConsumerGroupService
@@ -63,6 +79,9 @@ type Client struct {
 	Keys                    AbstractKeyService
 	KeySets                 AbstractKeySetService
 	Licenses                AbstractLicenseService
+	Audit                   AbstractAuditService
+	Clustering              AbstractClusteringService
+	Debug                   AbstractDebugService
 
 	credentials       abstractCredentialService
 	KeyAuths          AbstractKeyAuthService
@@ -78,11 +97,26 @@ type Client struct {
 	GraphqlRateLimitingCostDecorations AbstractGraphqlRateLimitingCostDecorationService
 	DegraphqlRoutes                    AbstractDegraphqlRouteService
 
-	Schemas AbstractSchemaService
-
-	logger         io.Writer
-	debug          bool
-	CustomEntities AbstractCustomEntityService
+	Schemas     AbstractSchemaService
+	schemaCache schemaCache
+
+	logger             io.Writer
+	debug              bool
+	retry              RetryConfig
+	retryNonIdempotent bool
+	retryClock         retryClock
+	accept             string
+	idempotentDeletes  bool
+	validateNames      bool
+	resolver           HostResolver
+	correlationHeader  string
+	fieldAliases       *fieldAliases
+	warningHandler     WarningHandler
+	warningsLock       sync.RWMutex // Synchronizes access to lastWarnings.
+	lastWarnings       []string
+	fieldSelectionLock sync.Mutex // Synchronizes access to fieldSelection.
+	fieldSelection     *bool
+	CustomEntities     AbstractCustomEntityService
 
 	custom.Registry
 }
@@ -107,19 +141,12 @@ type Status struct {
 // NewClient returns a Client which talks to Admin API of Kong
 func NewClient(baseURL *string, client *http.Client) (*Client, error) {
 	if client == nil {
-		transport := &http.Transport{
-			DialContext: (&net.Dialer{
-				Timeout: DefaultTimeout,
-			}).DialContext,
-			TLSHandshakeTimeout: DefaultTimeout,
-		}
-		client = &http.Client{
-			Timeout:   DefaultTimeout,
-			Transport: transport,
-		}
+		client = defaultHTTPClient(DefaultTimeout)
 	}
 	kong := new(Client)
 	kong.client = client
+	kong.accept = DefaultAccept
+	kong.correlationHeader = DefaultCorrelationHeader
 	var rootURL string
 	if baseURL != nil {
 		rootURL = *baseURL
@@ -159,6 +186,9 @@ func NewClient(baseURL *string, client *http.Client) (*Client, error) {
 	kong.Keys = (*KeyService)(&kong.common)
 	kong.KeySets = (*KeySetService)(&kong.common)
 	kong.Licenses = (*LicenseService)(&kong.common)
+	kong.Audit = (*AuditService)(&kong.common)
+	kong.Clustering = (*ClusteringService)(&kong.common)
+	kong.Debug = (*DebugService)(&kong.common)
 
 	kong.credentials = (*credentialService)(&kong.common)
 	kong.KeyAuths = (*KeyAuthService)(&kong.common)
@@ -188,11 +218,36 @@ func NewClient(baseURL *string, client *http.Client) (*Client, error) {
 		}
 	}
 	kong.logger = os.Stderr
+	kong.retryClock = systemRetryClock{}
 	return kong, nil
 }
 
+// defaultHTTPClient returns the http.Client used when none is provided,
+// using timeout for the TCP, TLS and HTTP layers.
+func defaultHTTPClient(timeout time.Duration) *http.Client {
+	transport := &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: timeout,
+		}).DialContext,
+		TLSHandshakeTimeout: timeout,
+	}
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+	}
+}
+
 // SetWorkspace sets the Kong Enteprise workspace in the client.
 // Calling this function with an empty string resets the workspace to default workspace.
+//
+// Once set, the path of every request made through the client's services is
+// prefixed with the workspace, including sub-resources such as the plugins
+// of a service or the credentials of a consumer. Without a workspace, paths
+// aren't prefixed and Kong serves them from its default workspace, see
+// DefaultWorkspace.
+// This includes schema requests (/schemas): schemas are the same in every
+// workspace, so the workspace doesn't change SchemaService results, but it
+// must exist and be accessible.
 func (c *Client) SetWorkspace(workspace string) {
 	c.workspaceLock.Lock()
 	defer c.workspaceLock.Unlock()
@@ -206,6 +261,103 @@ func (c *Client) Workspace() string {
 	return c.workspace
 }
 
+// SetMaxPageSize sets the largest page size requested by list
+// operations. Kong rejects list requests with a size above its own limit,
+// 1000 for most versions: larger sizes requested in a ListOpt are lowered
+// to n, and listing all entities takes more requests instead.
+// Calling this function with n <= 0 resets it to DefaultMaxPageSize.
+func (c *Client) SetMaxPageSize(n int) {
+	c.maxPageSizeLock.Lock()
+	defer c.maxPageSizeLock.Unlock()
+	c.maxPageSize = n
+}
+
+// MaxPageSize returns the largest page size requested by list operations.
+func (c *Client) MaxPageSize() int {
+	c.maxPageSizeLock.RLock()
+	defer c.maxPageSizeLock.RUnlock()
+	if c.maxPageSize <= 0 {
+		return DefaultMaxPageSize
+	}
+	return c.maxPageSize
+}
+
+// DefaultWorkspaceName is the name of the default workspace of Kong.
+const DefaultWorkspaceName = "default"
+
+// DefaultWorkspace returns the name of the workspace from which Kong serves
+// the requests made without a workspace, i.e. by a client whose workspace
+// isn't set with SetWorkspace or WithWorkspace. The workspace of the client
+// is ignored.
+// Kong Gateway Enterprise is queried for its default workspace, whose name
+// is DefaultWorkspaceName as Kong doesn't allow renaming it: an error is
+// returned if it isn't found or isn't visible with the credentials of the
+// client. Kong Gateway OSS, which has no workspaces endpoint, serves every
+// request from its default workspace: DefaultWorkspaceName is returned.
+func (c *Client) DefaultWorkspace(ctx context.Context) (string, error) {
+	req, err := c.NewRequestRaw("GET", c.baseRootURL, "/workspaces/"+DefaultWorkspaceName, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	var ws Workspace
+	_, err = c.Do(ctx, req, &ws)
+	if IsNotFoundErr(err) {
+		info, rootErr := c.Root(ctx)
+		if rootErr != nil {
+			return "", rootErr
+		}
+		version, versionErr := ParseSemanticVersion(VersionFromInfo(info))
+		if versionErr == nil && !version.IsKongGatewayEnterprise() {
+			return DefaultWorkspaceName, nil
+		}
+		return "", fmt.Errorf("fetching the default workspace: %w", err)
+	}
+	if err != nil {
+		return "", err
+	}
+	if ws.Name == nil {
+		return "", fmt.Errorf("the default workspace has no name")
+	}
+	return *ws.Name, nil
+}
+
+// CurrentWorkspace returns the workspace the client operates in: the
+// workspace set with SetWorkspace or, if none is set, the only workspace
+// the RBAC token of the client has permissions for, as reported by the
+// /userinfo endpoint of Kong Enterprise.
+// An empty string is returned if the token isn't scoped to a single
+// workspace, or if Kong doesn't support introspecting tokens.
+func (c *Client) CurrentWorkspace(ctx context.Context) (string, error) {
+	if ws := c.Workspace(); ws != "" {
+		return ws, nil
+	}
+	req, err := c.NewRequest("GET", "/userinfo", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	var userInfo struct {
+		Permissions struct {
+			Endpoints map[string]json.RawMessage `json:"endpoints"`
+		} `json:"permissions"`
+	}
+	_, err = c.Do(ctx, req, &userInfo)
+	if IsNotFoundErr(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	var workspaces []string
+	for ws := range userInfo.Permissions.Endpoints {
+		workspaces = append(workspaces, ws)
+	}
+	// "*" grants permissions on every workspace
+	if len(workspaces) != 1 || workspaces[0] == "*" {
+		return "", nil
+	}
+	return workspaces[0], nil
+}
+
 // baseURL build the base URL from the rootURL and the workspace
 func (c *Client) workspacedBaseURL(workspace string) string {
 	if len(workspace) > 0 {
@@ -224,18 +376,46 @@ func (c *Client) DoRAW(ctx context.Context, req *http.Request) (*http.Response,
 	if ctx != nil {
 		req = req.WithContext(ctx)
 	}
-
-	// log the request
-	err = c.logRequest(req)
-	if err != nil {
-		return nil, err
+	if id, ok := CorrelationID(req.Context()); ok {
+		if req.Header.Get(c.correlationHeader) != id {
+			// don't modify the headers of the request of the caller
+			req = req.Clone(req.Context())
+			req.Header.Set(c.correlationHeader, id)
+		}
 	}
 
-	// Make the request
-	resp, err := c.client.Do(req)
+	var resp *http.Response
+	start := c.retryClock.Now()
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err = rewindBody(req); err != nil {
+				return nil, err
+			}
+		}
+
+		// log the request
+		err = c.logRequest(req)
+		if err != nil {
+			return nil, err
+		}
+
+		// Make the request
+		resp, err = c.client.Do(req)
+		if attempt >= c.retry.Retries || !canRetry(req, resp, err, c.retry.ShouldRetry, c.retryNonIdempotent) {
+			break
+		}
+		delay := retryBackoff(resp, attempt, c.retry.Jitter)
+		if exceedsBudget(c.retryClock.Now().Sub(start), delay, c.retry.MaxElapsedTime) {
+			break
+		}
+		if err := waitForRetry(req.Context(), c.retryClock, resp, delay); err != nil {
+			return nil, wrapRequestContextErr(err)
+		}
+	}
 	if err != nil {
-		return nil, fmt.Errorf("making HTTP request: %w", err)
+		return nil, fmt.Errorf("making HTTP request: %w", wrapRequestContextErr(err))
 	}
+	c.collectWarnings(req, resp)
 
 	return resp, err
 }
@@ -260,6 +440,21 @@ func (c *Client) Do(ctx context.Context, req *http.Request,
 
 	// check for API errors
 	if err = hasError(resp); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			if apiErr.httpCode == http.StatusNotAcceptable {
+				apiErr.message = fmt.Sprintf("content negotiation failed: "+
+					"Kong or a proxy in front of it can't respond with Accept %q: %s",
+					req.Header.Get("Accept"), apiErr.message)
+			}
+			if c.debug {
+				apiErr.setRequest(req.Method, req.URL.String(), requestBody(req))
+			}
+			if c.idempotentDeletes && req.Method == http.MethodDelete &&
+				apiErr.httpCode == http.StatusNotFound {
+				return response, nil
+			}
+		}
 		return response, err
 	}
 
@@ -271,15 +466,65 @@ func (c *Client) Do(ctx context.Context, req *http.Request,
 				return nil, err
 			}
 		} else {
-			err = json.NewDecoder(resp.Body).Decode(v)
-			if err != nil {
+			err = c.decodeBody(resp.Body, v)
+			if err != nil && !(errors.Is(err, io.EOF) && resp.StatusCode == http.StatusCreated) {
 				return nil, err
 			}
+			err = nil
+			if resp.StatusCode == http.StatusCreated {
+				setIDFromLocation(v, resp.Header.Get("Location"))
+			}
 		}
 	}
 	return response, err
 }
 
+// decodeBody decodes the JSON of body into v, renaming aliased fields if
+// WithFieldAliases was used.
+func (c *Client) decodeBody(body io.Reader, v interface{}) error {
+	if c.fieldAliases == nil {
+		return json.NewDecoder(body).Decode(v)
+	}
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(b)) == 0 {
+		return io.EOF
+	}
+	if b, err = c.fieldAliases.decode(b); err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// setIDFromLocation sets the ID of entity, a pointer to an entity struct
+// such as *Service, to the last segment of location, the URL of a created
+// entity, if the ID isn't set. Some proxies strip the body of create
+// responses, in which case the ID of the created entity is only known from
+// the Location header.
+func setIDFromLocation(entity interface{}, location string) {
+	if location == "" {
+		return
+	}
+	v := reflect.ValueOf(entity)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	field := v.Elem().FieldByName("ID")
+	if !field.IsValid() || !field.CanSet() || field.Type() != reflect.TypeOf((*string)(nil)) ||
+		!field.IsNil() {
+		return
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return
+	}
+	if id := path.Base(strings.TrimRight(u.Path, "/")); id != "" && id != "." && id != "/" {
+		field.Set(reflect.ValueOf(String(id)))
+	}
+}
+
 // ErrorOrResponseError helps to handle the case where
 // there might not be a "hard" (connection) error but the
 // response itself represents an error.
@@ -312,6 +557,24 @@ func (c *Client) logRequest(r *http.Request) error {
 	return err
 }
 
+// requestBody returns a copy of the body of req, or nil if the body
+// can't be read again.
+func requestBody(req *http.Request) []byte {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil
+	}
+	defer body.Close()
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
 func (c *Client) logResponse(r *http.Response) error {
 	if !c.debug {
 		return nil
@@ -366,6 +629,71 @@ func (c *Client) Root(ctx context.Context) (map[string]interface{}, error) {
 	return info, nil
 }
 
+// RouterFlavor returns the router flavor Kong runs with, as reported
+// by the router_flavor configuration property of the root endpoint.
+// Kong versions predating router flavors only support the traditional
+// router, so RouterFlavorTraditional is returned when the property is absent.
+func (c *Client) RouterFlavor(ctx context.Context) (string, error) {
+	info, err := c.Root(ctx)
+	if err != nil {
+		return "", err
+	}
+	return routerFlavorFromInfo(info)
+}
+
+// AvailableVaults returns the sorted names of the vault backends loaded by
+// Kong, e.g. "env" or "aws", as reported by the loaded_vaults configuration
+// property of the root endpoint. Only enabled backends are returned.
+// A nil slice is returned when Kong doesn't report its vault backends,
+// e.g. when the root endpoint has no configuration.
+func (c *Client) AvailableVaults(ctx context.Context) ([]string, error) {
+	info, err := c.Root(ctx)
+	if err != nil {
+		return nil, err
+	}
+	configuration, ok := info["configuration"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	loaded, ok := configuration["loaded_vaults"].(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+	vaults := make([]string, 0, len(loaded))
+	for name, enabled := range loaded {
+		if enabled, ok := enabled.(bool); ok && enabled {
+			vaults = append(vaults, name)
+		}
+	}
+	sort.Strings(vaults)
+	return vaults, nil
+}
+
+// availablePluginsFromInfo retrieves the plugins available on the server,
+// keyed by name, from the response of root or /kong endpoints.
+func availablePluginsFromInfo(info map[string]interface{}) map[string]interface{} {
+	plugins, ok := info["plugins"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	available, _ := plugins["available_on_server"].(map[string]interface{})
+	return available
+}
+
+// routerFlavorFromInfo retrieves the router flavor from the response of
+// root or /kong endpoints.
+func routerFlavorFromInfo(info map[string]interface{}) (string, error) {
+	configuration, ok := info["configuration"].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("no configuration found in Kong's root endpoint")
+	}
+	flavor, ok := configuration["router_flavor"].(string)
+	if !ok || flavor == "" {
+		return RouterFlavorTraditional, nil
+	}
+	return flavor, nil
+}
+
 // RootJSON returns the response of GET request on the root of the Admin API
 // (GET / or /kong with a workspace) returning the raw JSON response data.
 func (c *Client) RootJSON(ctx context.Context) ([]byte, error) {
@@ -0,0 +1,228 @@
+package kong
+
+import (
+	"io"
+	"net/http"
+	"time"
+)
+
+// ClientOption configures a Client created with NewClientWithOptions.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	httpClient         *http.Client
+	headers            http.Header
+	retry              RetryConfig
+	retryNonIdempotent bool
+	timeout            time.Duration
+	logger             io.Writer
+	workspace          string
+	userAgent          string
+	accept             string
+	idempotentDeletes  bool
+	validateNames      bool
+	resolver           HostResolver
+	correlationHeader  string
+	fieldAliases       map[string]string
+	warningHandler     WarningHandler
+}
+
+// WithHTTPClient sets the http.Client used to talk to Kong.
+// The provided client is not modified; options touching the HTTP layer
+// (headers, user-agent, timeout) are applied to a copy of it.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(o *clientOptions) {
+		o.httpClient = client
+	}
+}
+
+// WithHeaders sets headers which are injected in every request.
+// Calling it multiple times merges the headers.
+func WithHeaders(headers http.Header) ClientOption {
+	return func(o *clientOptions) {
+		if o.headers == nil {
+			o.headers = http.Header{}
+		}
+		for k, values := range headers {
+			for _, v := range values {
+				o.headers.Add(k, v)
+			}
+		}
+	}
+}
+
+// WithRetries sets the number of times a request is retried when it fails
+// with a connection error or with a 429, 502, 503 or 504 response.
+// Only GET, HEAD, PUT, DELETE and OPTIONS requests are retried, unless
+// WithRetryNonIdempotent is used too. Requests are not retried by default.
+// It is a shorthand for setting RetryConfig.Retries with WithRetryConfig.
+func WithRetries(retries int) ClientOption {
+	return func(o *clientOptions) {
+		o.retry.Retries = retries
+	}
+}
+
+// WithRetryConfig sets how requests are retried, replacing the settings of
+// earlier WithRetries and WithRetryConfig options.
+func WithRetryConfig(config RetryConfig) ClientOption {
+	return func(o *clientOptions) {
+		o.retry = config
+	}
+}
+
+// WithRetryNonIdempotent sets whether POST and PATCH requests are retried
+// too. They aren't by default: a request which failed with a connection
+// error or a 502, 503 or 504 response may have been applied by Kong
+// already, in which case retrying it creates duplicate entities, e.g.
+// Plugins, Targets or credentials. It has no effect unless WithRetries is
+// used too.
+func WithRetryNonIdempotent(retryNonIdempotent bool) ClientOption {
+	return func(o *clientOptions) {
+		o.retryNonIdempotent = retryNonIdempotent
+	}
+}
+
+// WithTimeout sets the timeout of requests made by the client.
+// It defaults to DefaultTimeout.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger used in debug mode, see SetLogger.
+func WithLogger(w io.Writer) ClientOption {
+	return func(o *clientOptions) {
+		o.logger = w
+	}
+}
+
+// WithWorkspace sets the Kong Enterprise workspace used by the client,
+// see SetWorkspace.
+func WithWorkspace(workspace string) ClientOption {
+	return func(o *clientOptions) {
+		o.workspace = workspace
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent in every request.
+func WithUserAgent(userAgent string) ClientOption {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithAccept sets the Accept header sent in every request.
+// It defaults to DefaultAccept.
+func WithAccept(accept string) ClientOption {
+	return func(o *clientOptions) {
+		o.accept = accept
+	}
+}
+
+// WithIdempotentDeletes makes delete operations succeed when the entity
+// to delete doesn't exist, i.e. when Kong responds with a 404, instead of
+// returning a not found error. This is disabled by default.
+func WithIdempotentDeletes(enabled bool) ClientOption {
+	return func(o *clientOptions) {
+		o.idempotentDeletes = enabled
+	}
+}
+
+// WithEntityNameValidation makes the Create and Update operations of
+// Services and Routes validate their name with ValidateEntityName before
+// sending the request. Other entities, such as Upstreams whose names are
+// hostnames, follow other rules and aren't checked.
+// This is disabled by default.
+func WithEntityNameValidation(enabled bool) ClientOption {
+	return func(o *clientOptions) {
+		o.validateNames = enabled
+	}
+}
+
+// WithResolver sets the resolver used by TargetService.ValidateDNS.
+// It defaults to net.DefaultResolver.
+func WithResolver(resolver HostResolver) ClientOption {
+	return func(o *clientOptions) {
+		o.resolver = resolver
+	}
+}
+
+// WithWarningHandler sets a handler called with the warnings sent by Kong,
+// such as deprecation notices, in the response to each request.
+// See Client.LastWarnings.
+func WithWarningHandler(handler WarningHandler) ClientOption {
+	return func(o *clientOptions) {
+		o.warningHandler = handler
+	}
+}
+
+// WithCorrelationHeader sets the header carrying the correlation ID set
+// with WithCorrelationID. It defaults to DefaultCorrelationHeader.
+func WithCorrelationHeader(header string) ClientOption {
+	return func(o *clientOptions) {
+		o.correlationHeader = header
+	}
+}
+
+// NewClientWithOptions returns a Client which talks to the Admin API of Kong
+// at baseURL, configured using opts.
+// If baseURL is empty, the KONG_ADMIN_URL environment variable or the
+// default URL are used, as with NewClient.
+func NewClientWithOptions(baseURL string, opts ...ClientOption) (*Client, error) {
+	var o clientOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var httpClient *http.Client
+	if o.httpClient != nil {
+		c := *o.httpClient
+		httpClient = &c
+		if o.timeout > 0 {
+			httpClient.Timeout = o.timeout
+		}
+	} else {
+		timeout := DefaultTimeout
+		if o.timeout > 0 {
+			timeout = o.timeout
+		}
+		httpClient = defaultHTTPClient(timeout)
+	}
+
+	headers := o.headers.Clone()
+	if o.userAgent != "" {
+		if headers == nil {
+			headers = http.Header{}
+		}
+		headers.Set("User-Agent", o.userAgent)
+	}
+	if len(headers) > 0 {
+		httpClient = HTTPClientWithHeaders(httpClient, headers)
+	}
+
+	var url *string
+	if baseURL != "" {
+		url = &baseURL
+	}
+	client, err := NewClient(url, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	client.retry = o.retry
+	client.retryNonIdempotent = o.retryNonIdempotent
+	if o.accept != "" {
+		client.accept = o.accept
+	}
+	client.idempotentDeletes = o.idempotentDeletes
+	client.validateNames = o.validateNames
+	client.resolver = o.resolver
+	if o.correlationHeader != "" {
+		client.correlationHeader = o.correlationHeader
+	}
+	client.fieldAliases = newFieldAliases(o.fieldAliases)
+	client.warningHandler = o.warningHandler
+	client.SetWorkspace(o.workspace)
+	client.SetLogger(o.logger)
+	return client, nil
+}
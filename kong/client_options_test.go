@@ -0,0 +1,468 @@
+package kong
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientWithOptions(t *testing.T) {
+	var lastRequest *http.Request
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastRequest = r
+		_, _ = w.Write([]byte(`{"version": "3.3.0"}`))
+	}))
+	defer srv.Close()
+
+	t.Run("defaults", func(t *testing.T) {
+		client, err := NewClientWithOptions(srv.URL)
+		require.NoError(t, err)
+		assert.Equal(t, srv.URL, client.BaseRootURL())
+		assert.Equal(t, DefaultTimeout, client.client.Timeout)
+		assert.Equal(t, 0, client.retry.Retries)
+		assert.Equal(t, "", client.Workspace())
+	})
+
+	t.Run("invalid URL", func(t *testing.T) {
+		_, err := NewClientWithOptions("foo/bar")
+		assert.Error(t, err)
+	})
+
+	t.Run("WithHTTPClient", func(t *testing.T) {
+		httpClient := &http.Client{Timeout: time.Second}
+		client, err := NewClientWithOptions(srv.URL, WithHTTPClient(httpClient))
+		require.NoError(t, err)
+		assert.Equal(t, time.Second, client.client.Timeout)
+	})
+
+	t.Run("WithTimeout", func(t *testing.T) {
+		client, err := NewClientWithOptions(srv.URL, WithTimeout(3*time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, 3*time.Second, client.client.Timeout)
+
+		httpClient := &http.Client{Timeout: time.Second}
+		client, err = NewClientWithOptions(srv.URL, WithHTTPClient(httpClient), WithTimeout(3*time.Second))
+		require.NoError(t, err)
+		assert.Equal(t, 3*time.Second, client.client.Timeout)
+		assert.Equal(t, time.Second, httpClient.Timeout, "provided client must not be modified")
+	})
+
+	t.Run("WithHeaders and WithUserAgent", func(t *testing.T) {
+		client, err := NewClientWithOptions(srv.URL,
+			WithHeaders(http.Header{"Kong-Admin-Token": []string{"secret"}}),
+			WithHeaders(http.Header{"X-Foo": []string{"bar"}}),
+			WithUserAgent("my-agent/1.0"),
+		)
+		require.NoError(t, err)
+		_, err = client.Root(defaultCtx)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", lastRequest.Header.Get("Kong-Admin-Token"))
+		assert.Equal(t, "bar", lastRequest.Header.Get("X-Foo"))
+		assert.Equal(t, "my-agent/1.0", lastRequest.Header.Get("User-Agent"))
+	})
+
+	t.Run("WithWorkspace", func(t *testing.T) {
+		client, err := NewClientWithOptions(srv.URL, WithWorkspace("ws1"))
+		require.NoError(t, err)
+		assert.Equal(t, "ws1", client.Workspace())
+		_, err = client.Root(defaultCtx)
+		require.NoError(t, err)
+		assert.Equal(t, "/ws1/kong", lastRequest.URL.Path)
+	})
+
+	t.Run("WithLogger", func(t *testing.T) {
+		var buf bytes.Buffer
+		client, err := NewClientWithOptions(srv.URL, WithLogger(&buf))
+		require.NoError(t, err)
+		client.SetDebugMode(true)
+		_, err = client.Root(defaultCtx)
+		require.NoError(t, err)
+		assert.Contains(t, buf.String(), "GET / HTTP/1.1")
+	})
+
+	t.Run("WithRetries", func(t *testing.T) {
+		client, err := NewClientWithOptions(srv.URL, WithRetries(3))
+		require.NoError(t, err)
+		assert.Equal(t, 3, client.retry.Retries)
+	})
+
+	t.Run("WithRetryConfig", func(t *testing.T) {
+		client, err := NewClientWithOptions(srv.URL, WithRetries(3), WithRetryConfig(RetryConfig{Retries: 2}))
+		require.NoError(t, err)
+		assert.Equal(t, 2, client.retry.Retries)
+		assert.Nil(t, client.retry.ShouldRetry)
+	})
+
+	t.Run("WithAccept", func(t *testing.T) {
+		client, err := NewClientWithOptions(srv.URL)
+		require.NoError(t, err)
+		_, err = client.Root(defaultCtx)
+		require.NoError(t, err)
+		assert.Equal(t, "application/json", lastRequest.Header.Get("Accept"))
+
+		client, err = NewClientWithOptions(srv.URL, WithAccept("application/json; charset=utf-8"))
+		require.NoError(t, err)
+		_, err = client.Root(defaultCtx)
+		require.NoError(t, err)
+		assert.Equal(t, "application/json; charset=utf-8", lastRequest.Header.Get("Accept"))
+	})
+}
+
+func TestClientNotAcceptable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusNotAcceptable)
+		_, _ = w.Write([]byte("<html><body>406 Not Acceptable</body></html>"))
+	}))
+	defer srv.Close()
+	client, err := NewClientWithOptions(srv.URL)
+	require.NoError(t, err)
+
+	_, err = client.Services.Get(defaultCtx, String("foo"))
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotAcceptable, apiErr.Code())
+	assert.Equal(t, "content negotiation failed: Kong or a proxy in front of it can't respond with "+
+		`Accept "application/json": <html><body>406 Not Acceptable</body></html>`, apiErr.Message())
+}
+
+func TestClientRetries(t *testing.T) {
+	var attempts int
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"id": "s1", "name": "foo"}`))
+	}))
+	defer srv.Close()
+
+	t.Run("retries until success and resends the body", func(t *testing.T) {
+		attempts, bodies = 0, nil
+		client, err := NewClientWithOptions(srv.URL, WithRetries(2))
+		require.NoError(t, err)
+		clock := &fakeRetryClock{}
+		client.retryClock = clock
+		service, err := client.Services.Create(defaultCtx, &Service{ID: String("s1"), Name: String("foo")})
+		require.NoError(t, err)
+		assert.Equal(t, "s1", *service.ID)
+		assert.Equal(t, 3, attempts)
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, clock.delays)
+		for _, b := range bodies {
+			assert.JSONEq(t, `{"id": "s1", "name": "foo"}`, b)
+		}
+	})
+
+	t.Run("gives up after the configured retries", func(t *testing.T) {
+		attempts, bodies = 0, nil
+		client, err := NewClientWithOptions(srv.URL, WithRetries(1))
+		require.NoError(t, err)
+		client.retryClock = &fakeRetryClock{}
+		_, err = client.Services.Get(defaultCtx, String("foo"))
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.Code())
+		assert.Equal(t, 2, attempts)
+	})
+
+	t.Run("no retries by default", func(t *testing.T) {
+		attempts, bodies = 0, nil
+		client, err := NewClientWithOptions(srv.URL)
+		require.NoError(t, err)
+		client.retryClock = &fakeRetryClock{}
+		_, err = client.Services.Get(defaultCtx, String("foo"))
+		assert.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("POST is not retried by default", func(t *testing.T) {
+		attempts, bodies = 0, nil
+		client, err := NewClientWithOptions(srv.URL, WithRetries(2))
+		require.NoError(t, err)
+		client.retryClock = &fakeRetryClock{}
+		_, err = client.Services.Create(defaultCtx, &Service{Name: String("foo")})
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.Code())
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("POST is retried when opted in", func(t *testing.T) {
+		attempts, bodies = 0, nil
+		client, err := NewClientWithOptions(srv.URL, WithRetries(2), WithRetryNonIdempotent(true))
+		require.NoError(t, err)
+		client.retryClock = &fakeRetryClock{}
+		service, err := client.Services.Create(defaultCtx, &Service{Name: String("foo")})
+		require.NoError(t, err)
+		assert.Equal(t, "s1", *service.ID)
+		assert.Equal(t, 3, attempts)
+		for _, b := range bodies {
+			assert.JSONEq(t, `{"name": "foo"}`, b)
+		}
+	})
+}
+
+func TestRetryJitter(t *testing.T) {
+	const backoff = 800 * time.Millisecond
+	rnd := rand.New(rand.NewSource(42)) //nolint:gosec // seeded for reproducible delays
+	sample := func(jitter RetryJitter) (time.Duration, time.Duration) {
+		minDelay, maxDelay := time.Duration(math.MaxInt64), time.Duration(0)
+		for i := 0; i < 1000; i++ {
+			delay := withJitter(backoff, jitter, rnd.Int63n)
+			if delay < minDelay {
+				minDelay = delay
+			}
+			if delay > maxDelay {
+				maxDelay = delay
+			}
+		}
+		return minDelay, maxDelay
+	}
+
+	minDelay, maxDelay := sample(RetryJitterNone)
+	assert.Equal(t, backoff, minDelay)
+	assert.Equal(t, backoff, maxDelay)
+
+	minDelay, maxDelay = sample(RetryJitterFull)
+	assert.GreaterOrEqual(t, minDelay, time.Duration(0))
+	assert.Less(t, minDelay, backoff/10)
+	assert.LessOrEqual(t, maxDelay, backoff)
+	assert.Greater(t, maxDelay, backoff*9/10)
+
+	minDelay, maxDelay = sample(RetryJitterEqual)
+	assert.GreaterOrEqual(t, minDelay, backoff/2)
+	assert.Less(t, minDelay, backoff*6/10)
+	assert.LessOrEqual(t, maxDelay, backoff)
+	assert.Greater(t, maxDelay, backoff*9/10)
+
+	// Retry-After is never jittered
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"2"}}}
+	assert.Equal(t, 2*time.Second, retryBackoff(resp, 0, RetryJitterFull))
+	assert.LessOrEqual(t, retryBackoff(nil, 10, RetryJitterEqual), retryMaxBackoff)
+
+	client, err := NewClientWithOptions("", WithRetryConfig(RetryConfig{Retries: 1, Jitter: RetryJitterEqual}))
+	require.NoError(t, err)
+	assert.Equal(t, RetryJitterEqual, client.retry.Jitter)
+}
+
+// fakeRetryClock is a retryClock whose time only advances when waiting,
+// recording the delays waited for.
+type fakeRetryClock struct {
+	now    time.Time
+	delays []time.Duration
+	// onWait, if set, is called before waiting.
+	onWait func()
+}
+
+func (c *fakeRetryClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeRetryClock) Wait(ctx context.Context, delay time.Duration) error {
+	if c.onWait != nil {
+		c.onWait()
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.delays = append(c.delays, delay)
+	c.now = c.now.Add(delay)
+	return nil
+}
+
+func TestClientRetryMaxElapsedTime(t *testing.T) {
+	var attempts, failures int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"id": "s1", "name": "foo"}`))
+	}))
+	defer srv.Close()
+
+	t.Run("stops retrying at the budget", func(t *testing.T) {
+		attempts, failures = 0, 100
+		client, err := NewClientWithOptions(srv.URL, WithRetryConfig(RetryConfig{
+			Retries:        10,
+			MaxElapsedTime: 350 * time.Millisecond,
+		}))
+		require.NoError(t, err)
+		clock := &fakeRetryClock{}
+		client.retryClock = clock
+		_, err = client.Services.Get(defaultCtx, String("foo"))
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusServiceUnavailable, apiErr.Code())
+		// waiting 400ms more after 100ms and 200ms would exceed the budget
+		assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, clock.delays)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("recovers within the budget", func(t *testing.T) {
+		attempts, failures = 0, 2
+		client, err := NewClientWithOptions(srv.URL, WithRetryConfig(RetryConfig{
+			Retries:        10,
+			MaxElapsedTime: 5 * time.Second,
+		}))
+		require.NoError(t, err)
+		clock := &fakeRetryClock{}
+		client.retryClock = clock
+		service, err := client.Services.Get(defaultCtx, String("foo"))
+		require.NoError(t, err)
+		assert.Equal(t, "s1", *service.ID)
+		assert.Equal(t, 3, attempts)
+		assert.Len(t, clock.delays, 2)
+	})
+
+	t.Run("respects context cancellation", func(t *testing.T) {
+		attempts, failures = 0, 100
+		client, err := NewClientWithOptions(srv.URL, WithRetryConfig(RetryConfig{
+			Retries:        10,
+			MaxElapsedTime: 5 * time.Second,
+		}))
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(defaultCtx, time.Minute)
+		defer cancel()
+		clock := &fakeRetryClock{}
+		clock.onWait = func() {
+			if len(clock.delays) == 1 {
+				cancel()
+			}
+		}
+		client.retryClock = clock
+		_, err = client.Services.Get(ctx, String("foo"))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 2, attempts)
+	})
+}
+
+func TestClientRetryPredicate(t *testing.T) {
+	var attempts int
+	var failure string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		switch {
+		case failure == "foreign key" && attempts < 3:
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code": 4, "name": "foreign key violation",
+				"message": "the foreign key '{id=\"s1\"}' does not reference an existing 'services' entity."}`))
+		case failure == "unique":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"code": 5, "name": "unique constraint violation",
+				"message": "UNIQUE violation detected on '{name=\"foo\"}'"}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": "r1", "name": "foo"}`))
+		}
+	}))
+	defer srv.Close()
+
+	client, err := NewClientWithOptions(srv.URL,
+		WithRetryConfig(RetryConfig{
+			Retries: 3,
+			ShouldRetry: func(err *APIError) bool {
+				return err.ErrorCode() == ErrorCodeForeignKeyViolation
+			},
+		}),
+		WithRetryNonIdempotent(true),
+	)
+	require.NoError(t, err)
+	client.retryClock = &fakeRetryClock{}
+	route := &Route{Name: String("foo"), Service: &Service{ID: String("s1")}}
+
+	t.Run("a foreign key violation is retried", func(t *testing.T) {
+		attempts, failure = 0, "foreign key"
+		created, err := client.Routes.Create(defaultCtx, route)
+		require.NoError(t, err)
+		assert.Equal(t, "r1", *created.ID)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("a unique violation is not retried", func(t *testing.T) {
+		attempts, failure = 0, "unique"
+		_, err := client.Routes.Create(defaultCtx, route)
+		var apiErr *APIError
+		require.ErrorAs(t, err, &apiErr)
+		assert.Equal(t, http.StatusConflict, apiErr.Code())
+		assert.Equal(t, ErrorCodeUniqueViolation, apiErr.ErrorCode())
+		assert.Equal(t, "unique constraint violation", apiErr.ErrorName())
+		assert.Contains(t, apiErr.Error(), "UNIQUE violation detected")
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func TestClientEntityNameValidation(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte(`{"id": "id1"}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClientWithOptions(srv.URL)
+	require.NoError(t, err)
+	_, err = client.Services.Create(defaultCtx, &Service{Name: String("my service")})
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	client, err = NewClientWithOptions(srv.URL, WithEntityNameValidation(true))
+	require.NoError(t, err)
+	_, err = client.Services.Create(defaultCtx, &Service{Name: String("my service")})
+	assert.ErrorContains(t, err, "invalid name 'my service'")
+	_, err = client.Services.Update(defaultCtx, &Service{ID: String("id1"), Name: String("a/b")})
+	assert.Error(t, err)
+	_, err = client.Routes.Create(defaultCtx, &Route{Name: String("my route")})
+	assert.Error(t, err)
+	_, err = client.Routes.Update(defaultCtx, &Route{ID: String("id1"), Name: String("my route")})
+	assert.Error(t, err)
+	assert.Equal(t, 1, requests, "invalid names are not sent to Kong")
+
+	_, err = client.Services.Create(defaultCtx, &Service{Name: String("my-service.v1")})
+	require.NoError(t, err)
+	// Kong doesn't restrict the names of other entities this way
+	_, err = client.Upstreams.Create(defaultCtx, &Upstream{Name: String("example.com:8080")})
+	require.NoError(t, err)
+	_, err = client.Consumers.Create(defaultCtx, &Consumer{Username: String("alice@example.com")})
+	require.NoError(t, err)
+	_, err = client.Consumers.Update(defaultCtx, &Consumer{ID: String("id1"), Username: String("John Doe")})
+	require.NoError(t, err)
+	assert.Equal(t, 5, requests)
+}
+
+func TestClientIdempotentDeletes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message": "Not found"}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClientWithOptions(srv.URL)
+	require.NoError(t, err)
+	err = client.Services.Delete(defaultCtx, String("foo"))
+	assert.True(t, IsNotFoundErr(err))
+
+	client, err = NewClientWithOptions(srv.URL, WithIdempotentDeletes(true))
+	require.NoError(t, err)
+	assert.NoError(t, client.Services.Delete(defaultCtx, String("foo")))
+	assert.NoError(t, client.Consumers.Delete(defaultCtx, String("foo")))
+	assert.NoError(t, client.KeyAuths.Delete(defaultCtx, String("consumer"), String("key")))
+	// other requests still fail
+	_, err = client.Services.Get(defaultCtx, String("foo"))
+	assert.True(t, IsNotFoundErr(err))
+}
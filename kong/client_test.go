@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"net/http"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -317,3 +318,191 @@ func TestReloadDeclarativeRawConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestWorkspacedRequests(T *testing.T) {
+	srv := newFakeKong(T, map[string]fakeResponse{
+		"*": {status: http.StatusCreated, body: `{"id": "id"}`},
+	})
+	client := newFakeKongClient(T, srv)
+	client.SetWorkspace("teamA")
+
+	_, err := client.Services.Create(defaultCtx, &Service{Name: String("s1")})
+	require.NoError(T, err)
+	_, err = client.Routes.CreateInService(defaultCtx, String("s1"), &Route{Name: String("r1")})
+	require.NoError(T, err)
+	_, err = client.Plugins.CreateForService(defaultCtx, String("s1"), &Plugin{Name: String("cors")})
+	require.NoError(T, err)
+	_, err = client.Plugins.CreateForRoute(defaultCtx, String("r1"), &Plugin{Name: String("cors")})
+	require.NoError(T, err)
+	_, err = client.Consumers.Create(defaultCtx, &Consumer{Username: String("c1")})
+	require.NoError(T, err)
+	_, err = client.KeyAuths.Create(defaultCtx, String("c1"), &KeyAuth{Key: String("secret")})
+	require.NoError(T, err)
+	_, err = client.Certificates.Create(defaultCtx, &Certificate{Cert: String("cert"), Key: String("key")})
+	require.NoError(T, err)
+	_, err = client.SNIs.Create(defaultCtx, &SNI{Name: String("example.com")})
+	require.NoError(T, err)
+	_, err = client.Upstreams.Create(defaultCtx, &Upstream{Name: String("u1")})
+	require.NoError(T, err)
+	_, err = client.Targets.Create(defaultCtx, String("u1"), &Target{Target: String("10.0.0.1:80")})
+	require.NoError(T, err)
+	// schema requests are prefixed too, see SetWorkspace
+	_, err = client.Schemas.Get(defaultCtx, "services")
+	require.NoError(T, err)
+
+	paths := srv.paths()
+	require.Len(T, paths, 11)
+	for _, path := range paths {
+		assert.True(T, strings.HasPrefix(path, "/teamA/"), "path %s is not prefixed with the workspace", path)
+	}
+}
+
+func TestDefaultWorkspace(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/":                   {body: `{"version": "3.4.0.0-enterprise-edition"}`},
+		"/workspaces/default": {body: `{"id": "ws0", "name": "default"}`},
+		"/services/s1":        {body: `{"id": "s1", "name": "s1"}`},
+		"/teamA/services/s1":  {body: `{"id": "s1", "name": "s1"}`},
+		"*":                   {status: http.StatusNotFound, body: `{"message": "Not found"}`},
+	})
+	client := newFakeKongClient(t, srv)
+
+	// without a workspace, requests target the unprefixed paths served
+	// from the default workspace
+	ws, err := client.DefaultWorkspace(defaultCtx)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultWorkspaceName, ws)
+	_, err = client.Services.Get(defaultCtx, String("s1"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/workspaces/default", "/services/s1"}, srv.paths())
+
+	// a workspace overrides it, but not the default workspace itself
+	srv.reset()
+	client.SetWorkspace("teamA")
+	ws, err = client.DefaultWorkspace(defaultCtx)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultWorkspaceName, ws)
+	_, err = client.Services.Get(defaultCtx, String("s1"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/workspaces/default", "/teamA/services/s1"}, srv.paths())
+
+	srv.reset()
+	client.SetWorkspace("")
+	_, err = client.Services.Get(defaultCtx, String("s1"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/services/s1"}, srv.paths())
+
+	// the default workspace of Kong Gateway Enterprise can be hidden by RBAC
+	srv.remove("/workspaces/default")
+	_, err = client.DefaultWorkspace(defaultCtx)
+	assert.True(t, IsNotFoundErr(err))
+
+	// Kong Gateway OSS has no workspaces endpoint
+	srv.set("/", fakeResponse{body: `{"version": "3.4.0"}`})
+	ws, err = client.DefaultWorkspace(defaultCtx)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultWorkspaceName, ws)
+}
+
+func TestRouterFlavor(T *testing.T) {
+	srv := newFakeKong(T, map[string]fakeResponse{
+		"/": {body: `{"version": "3.2.0", "configuration": {"router_flavor": "expressions"}}`},
+	})
+	client := newFakeKongClient(T, srv)
+
+	flavor, err := client.RouterFlavor(defaultCtx)
+	require.NoError(T, err)
+	assert.Equal(T, RouterFlavorExpressions, flavor)
+
+	srv.set("/", fakeResponse{body: `{"version": "3.2.0", "configuration": {"router_flavor": "traditional_compatible"}}`})
+	flavor, err = client.RouterFlavor(defaultCtx)
+	require.NoError(T, err)
+	assert.Equal(T, RouterFlavorTraditionalCompatible, flavor)
+
+	srv.set("/", fakeResponse{body: `{"version": "2.8.0", "configuration": {}}`})
+	flavor, err = client.RouterFlavor(defaultCtx)
+	require.NoError(T, err)
+	assert.Equal(T, RouterFlavorTraditional, flavor)
+
+	srv.set("/", fakeResponse{body: `{"version": "2.8.0"}`})
+	_, err = client.RouterFlavor(defaultCtx)
+	assert.Error(T, err)
+}
+
+func TestDoCreatedLocation(t *testing.T) {
+	client := newFakeKongClient(t, newFakeKong(t, map[string]fakeResponse{
+		"/services": {
+			status: http.StatusCreated,
+			header: http.Header{"Location": {"http://kong:8001/services/0c61e164-6171-4837-8836-8f5298726d53"}},
+		},
+		"/routes": {
+			status: http.StatusCreated,
+			header: http.Header{"Location": {"/routes/r2"}},
+			body:   `{"name": "route2"}`,
+		},
+		"/consumers": {
+			status: http.StatusCreated,
+			header: http.Header{"Location": {"/consumers/other"}},
+			body:   `{"id": "c1", "username": "foo"}`,
+		},
+		"*": {},
+	}))
+
+	service, err := client.Services.Create(defaultCtx, &Service{Name: String("svc1")})
+	require.NoError(t, err)
+	assert.Equal(t, "0c61e164-6171-4837-8836-8f5298726d53", *service.ID)
+
+	route, err := client.Routes.Create(defaultCtx, &Route{Name: String("route2")})
+	require.NoError(t, err)
+	assert.Equal(t, "r2", *route.ID)
+	assert.Equal(t, "route2", *route.Name)
+
+	// the ID of the body wins
+	consumer, err := client.Consumers.Create(defaultCtx, &Consumer{Username: String("foo")})
+	require.NoError(t, err)
+	assert.Equal(t, "c1", *consumer.ID)
+
+	// empty bodies are only accepted for created entities
+	_, err = client.Services.Get(defaultCtx, String("foo"))
+	assert.Error(t, err)
+}
+
+func TestCurrentWorkspace(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"*": {status: http.StatusNotFound, body: `{"message": "Not found"}`},
+	})
+	client := newFakeKongClient(t, srv)
+
+	// introspection isn't supported
+	ws, err := client.CurrentWorkspace(defaultCtx)
+	require.NoError(t, err)
+	assert.Empty(t, ws)
+
+	srv.set("/userinfo", fakeResponse{body: `{
+		"admin": {"username": "team-a-admin", "rbac_token_enabled": true},
+		"groups": [],
+		"permissions": {
+			"endpoints": {"team-a": {"*": {"actions": ["read", "create"], "negative": false}}},
+			"entities": {}
+		}
+	}`})
+	ws, err = client.CurrentWorkspace(defaultCtx)
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", ws)
+
+	// unscoped tokens
+	srv.set("/userinfo", fakeResponse{body: `{"permissions": {"endpoints": {"*": {"*": {"actions": ["read"]}}}}}`})
+	ws, err = client.CurrentWorkspace(defaultCtx)
+	require.NoError(t, err)
+	assert.Empty(t, ws)
+	srv.set("/userinfo", fakeResponse{body: `{"permissions": {"endpoints": {"team-a": {}, "team-b": {}}}}`})
+	ws, err = client.CurrentWorkspace(defaultCtx)
+	require.NoError(t, err)
+	assert.Empty(t, ws)
+
+	// the workspace set explicitly wins
+	client.SetWorkspace("team-b")
+	ws, err = client.CurrentWorkspace(defaultCtx)
+	require.NoError(t, err)
+	assert.Equal(t, "team-b", ws)
+}
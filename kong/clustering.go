@@ -0,0 +1,27 @@
+package kong
+
+// DataPlane represents a data plane node connected to a Kong control plane
+// in hybrid mode.
+// Read https://docs.konghq.com/gateway/latest/production/deployment-topologies/hybrid-mode/
+// +k8s:deepcopy-gen=true
+type DataPlane struct {
+	ID         *string `json:"id,omitempty" yaml:"id,omitempty"`
+	Hostname   *string `json:"hostname,omitempty" yaml:"hostname,omitempty"`
+	IP         *string `json:"ip,omitempty" yaml:"ip,omitempty"`
+	Version    *string `json:"version,omitempty" yaml:"version,omitempty"`
+	SyncStatus *string `json:"sync_status,omitempty" yaml:"sync_status,omitempty"`
+	ConfigHash *string `json:"config_hash,omitempty" yaml:"config_hash,omitempty"`
+	LastSeen   *int64  `json:"last_seen,omitempty" yaml:"last_seen,omitempty"`
+	TTL        *int64  `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+}
+
+// FriendlyName returns the endpoint key hostname or ID.
+func (d *DataPlane) FriendlyName() string {
+	if d.Hostname != nil {
+		return *d.Hostname
+	}
+	if d.ID != nil {
+		return *d.ID
+	}
+	return ""
+}
@@ -0,0 +1,83 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// AbstractClusteringService handles the clustering of Kong nodes in
+// hybrid mode.
+type AbstractClusteringService interface {
+	// ListDataPlanes fetches a list of DataPlanes connected to the control plane.
+	ListDataPlanes(ctx context.Context, opt *ListOpt) ([]*DataPlane, *ListOpt, error)
+	// ListAllDataPlanes fetches all DataPlanes connected to the control plane.
+	ListAllDataPlanes(ctx context.Context) ([]*DataPlane, error)
+	// DriftedDataPlanes fetches all DataPlanes whose config hash differs
+	// from expectedHash.
+	DriftedDataPlanes(ctx context.Context, expectedHash string) ([]*DataPlane, error)
+}
+
+// ClusteringService handles the clustering of Kong nodes in hybrid mode.
+type ClusteringService service
+
+// ListDataPlanes fetches a list of DataPlanes connected to the control plane.
+// opt can be used to control pagination.
+func (s *ClusteringService) ListDataPlanes(ctx context.Context,
+	opt *ListOpt,
+) ([]*DataPlane, *ListOpt, error) {
+	data, next, err := s.client.list(ctx, "/clustering/data-planes", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	var dataPlanes []*DataPlane
+	for _, object := range data {
+		b, err := object.MarshalJSON()
+		if err != nil {
+			return nil, nil, err
+		}
+		var dataPlane DataPlane
+		err = json.Unmarshal(b, &dataPlane)
+		if err != nil {
+			return nil, nil, err
+		}
+		dataPlanes = append(dataPlanes, &dataPlane)
+	}
+
+	return dataPlanes, next, nil
+}
+
+// ListAllDataPlanes fetches all DataPlanes connected to the control plane.
+func (s *ClusteringService) ListAllDataPlanes(ctx context.Context) ([]*DataPlane, error) {
+	var dataPlanes, data []*DataPlane
+	var err error
+	opt := &ListOpt{Size: pageSize}
+
+	for opt != nil {
+		data, opt, err = s.ListDataPlanes(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		dataPlanes = append(dataPlanes, data...)
+	}
+	return dataPlanes, nil
+}
+
+// DriftedDataPlanes fetches all DataPlanes connected to the control plane
+// whose config hash differs from expectedHash, i.e. which didn't converge
+// to the expected configuration. expectedHash is typically the
+// ConfigurationHash reported by the control plane's Status.
+func (s *ClusteringService) DriftedDataPlanes(ctx context.Context,
+	expectedHash string,
+) ([]*DataPlane, error) {
+	dataPlanes, err := s.ListAllDataPlanes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var drifted []*DataPlane
+	for _, dataPlane := range dataPlanes {
+		if dataPlane.ConfigHash == nil || *dataPlane.ConfigHash != expectedHash {
+			drifted = append(drifted, dataPlane)
+		}
+	}
+	return drifted, nil
+}
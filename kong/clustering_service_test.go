@@ -0,0 +1,36 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusteringDriftedDataPlanes(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/clustering/data-planes": {body: `{"data": [
+			{"id": "dp1", "hostname": "dp1.example.com", "config_hash": "aaaa"},
+			{"id": "dp2", "hostname": "dp2.example.com", "config_hash": "bbbb"}
+		], "offset": "page2"}`},
+		"/clustering/data-planes?offset=page2": {body: `{"data": [
+			{"id": "dp3", "hostname": "dp3.example.com", "config_hash": "aaaa"},
+			{"id": "dp4", "hostname": "dp4.example.com"}
+		], "offset": null}`},
+	})
+	client := newFakeKongClient(t, srv)
+
+	dataPlanes, err := client.Clustering.ListAllDataPlanes(defaultCtx)
+	require.NoError(t, err)
+	assert.Len(t, dataPlanes, 4)
+
+	drifted, err := client.Clustering.DriftedDataPlanes(defaultCtx, "aaaa")
+	require.NoError(t, err)
+	require.Len(t, drifted, 2)
+	assert.Equal(t, "dp2.example.com", drifted[0].FriendlyName())
+	assert.Equal(t, "dp4.example.com", drifted[1].FriendlyName())
+
+	drifted, err = client.Clustering.DriftedDataPlanes(defaultCtx, "bbbb")
+	require.NoError(t, err)
+	assert.Len(t, drifted, 3)
+}
@@ -0,0 +1,116 @@
+package kong
+
+import (
+	"encoding/json"
+
+	"github.com/tidwall/gjson"
+)
+
+// ConfigField describes a field of a plugin's config, as declared in the
+// plugin's schema. It carries the metadata needed to render a form
+// for the field.
+type ConfigField struct {
+	// Name is the dotted path of the field within the config,
+	// e.g. "redis.host". It is empty for the Elements of a field.
+	Name string
+	// Type is the schema type of the field, e.g. "string", "integer",
+	// "array" or "record".
+	Type string
+	// Required is true if the field must be set.
+	Required bool
+	// Default is the default value of the field, or nil if it has none.
+	Default interface{}
+	// OneOf lists the values accepted by the field, if restricted.
+	OneOf []interface{}
+	// Elements describes the elements of "array" and "set" fields.
+	Elements *ConfigField
+	// Fields describes the fields of a record, for record Elements.
+	// Fields of records which are not elements of an array or set are
+	// flattened in the result of PluginConfigFields instead.
+	Fields []ConfigField
+}
+
+// PluginConfigFields returns the description of every field of the config
+// of a plugin, given the full schema of the plugin as returned by
+// PluginService.GetFullSchema.
+// Fields are returned in schema order. Nested records are flattened: a
+// record field is followed by its own fields, named using a dotted path.
+func PluginConfigFields(schema Schema) ([]ConfigField, error) {
+	jsonb, err := json.Marshal(&schema)
+	if err != nil {
+		return nil, err
+	}
+	configSchema, err := getConfigSchema(gjson.ParseBytes(jsonb))
+	if err != nil {
+		return nil, err
+	}
+	return configRecordFields(configSchema, "", true), nil
+}
+
+// PluginConfigFieldOrder returns the names of the top-level fields of the
+// config of a plugin in the order they are declared in its schema, given
+// the full schema of the plugin as returned by PluginService.GetFullSchema.
+// It can be used to render plugin configs in a stable and readable order.
+// nil is returned if schema has no config.
+func PluginConfigFieldOrder(schema Schema) []string {
+	jsonb, err := json.Marshal(&schema)
+	if err != nil {
+		return nil
+	}
+	configSchema, err := getConfigSchema(gjson.ParseBytes(jsonb))
+	if err != nil {
+		return nil
+	}
+	var res []string
+	for _, field := range configRecordFields(configSchema, "", false) {
+		res = append(res, field.Name)
+	}
+	return res
+}
+
+// configRecordFields describes the fields of a record schema.
+// If flatten is true, the fields of nested records are appended to the
+// result, prefixed by the name of the record.
+func configRecordFields(schema gjson.Result, prefix string, flatten bool) []ConfigField {
+	var res []ConfigField
+	schema.Get("fields").ForEach(func(_, value gjson.Result) bool {
+		fname := ""
+		for k := range value.Map() {
+			fname = k
+			break
+		}
+		field := value.Get(fname)
+		desc := configFieldFromSchema(field)
+		desc.Name = prefix + fname
+		if desc.Type == "record" && !flatten {
+			desc.Fields = configRecordFields(field, "", false)
+		}
+		res = append(res, desc)
+		if desc.Type == "record" && flatten {
+			res = append(res, configRecordFields(field, desc.Name+".", true)...)
+		}
+		return true
+	})
+	return res
+}
+
+func configFieldFromSchema(field gjson.Result) ConfigField {
+	desc := ConfigField{
+		Type:     field.Get("type").String(),
+		Required: field.Get("required").Bool(),
+	}
+	if def := field.Get("default"); def.Exists() {
+		desc.Default = def.Value()
+	}
+	for _, v := range field.Get("one_of").Array() {
+		desc.OneOf = append(desc.OneOf, v.Value())
+	}
+	if elements := field.Get("elements"); elements.Exists() {
+		e := configFieldFromSchema(elements)
+		if e.Type == "record" {
+			e.Fields = configRecordFields(elements, "", false)
+		}
+		desc.Elements = &e
+	}
+	return desc
+}
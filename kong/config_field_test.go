@@ -0,0 +1,126 @@
+package kong
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const RequestTransformerSchema = `{
+	"fields": [
+		{ "protocols": { "type": "set", "required": true, "default": ["grpc", "grpcs", "http", "https"],
+			"elements": { "type": "string", "one_of": ["grpc", "grpcs", "http", "https"] } } },
+		{
+			"config": {
+				"type": "record",
+				"required": true,
+				"fields": [
+					{ "http_method": { "type": "string", "match": "^%u+$" } },
+					{
+						"remove": {
+							"type": "record",
+							"required": true,
+							"fields": [
+								{ "body": { "type": "array", "default": [], "required": true,
+									"elements": { "type": "string" } } },
+								{ "headers": { "type": "array", "default": [], "required": true,
+									"elements": { "type": "string" } } }
+							]
+						}
+					},
+					{
+						"replace": {
+							"type": "record",
+							"required": true,
+							"fields": [
+								{ "headers": { "type": "array", "default": [], "required": true,
+									"elements": { "type": "string" } } },
+								{ "uri": { "type": "string" } }
+							]
+						}
+					}
+				]
+			}
+		}
+	]
+}`
+
+func schemaFromJSON(t *testing.T, s string) Schema {
+	var schema Schema
+	require.NoError(t, json.Unmarshal([]byte(s), &schema))
+	return schema
+}
+
+func TestPluginConfigFieldsRequestTransformer(t *testing.T) {
+	fields, err := PluginConfigFields(schemaFromJSON(t, RequestTransformerSchema))
+	require.NoError(t, err)
+
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{
+		"http_method",
+		"remove", "remove.body", "remove.headers",
+		"replace", "replace.headers", "replace.uri",
+	}, names)
+
+	assert.Equal(t, ConfigField{Name: "http_method", Type: "string"}, fields[0])
+	assert.Equal(t, ConfigField{Name: "remove", Type: "record", Required: true}, fields[1])
+	assert.Equal(t, ConfigField{
+		Name:     "remove.headers",
+		Type:     "array",
+		Required: true,
+		Default:  []interface{}{},
+		Elements: &ConfigField{Type: "string"},
+	}, fields[3])
+	assert.Equal(t, ConfigField{Name: "replace.uri", Type: "string"}, fields[6])
+}
+
+func TestPluginConfigFieldsStatsD(t *testing.T) {
+	fields, err := PluginConfigFields(schemaFromJSON(t, StatsDSchema))
+	require.NoError(t, err)
+	require.Len(t, fields, 4)
+
+	assert.Equal(t, ConfigField{Name: "host", Type: "string", Default: "localhost"}, fields[0])
+	assert.Equal(t, ConfigField{Name: "port", Type: "integer", Default: float64(8125)}, fields[1])
+
+	metrics := fields[3]
+	assert.Equal(t, "metrics", metrics.Name)
+	assert.Equal(t, "array", metrics.Type)
+	assert.False(t, metrics.Required)
+	assert.Len(t, metrics.Default, 10)
+	require.NotNil(t, metrics.Elements)
+	assert.Equal(t, "record", metrics.Elements.Type)
+	require.Len(t, metrics.Elements.Fields, 4)
+
+	name := metrics.Elements.Fields[0]
+	assert.Equal(t, "name", name.Name)
+	assert.True(t, name.Required)
+	assert.Len(t, name.OneOf, 10)
+	assert.Contains(t, name.OneOf, "unique_users")
+
+	assert.Equal(t, ConfigField{
+		Name:  "consumer_identifier",
+		Type:  "string",
+		OneOf: []interface{}{"consumer_id", "custom_id", "username"},
+	}, metrics.Elements.Fields[3])
+}
+
+func TestPluginConfigFieldsNoConfig(t *testing.T) {
+	_, err := PluginConfigFields(schemaFromJSON(t, `{"fields": [{"name": {"type": "string"}}]}`))
+	assert.Error(t, err)
+}
+
+func TestPluginConfigFieldOrder(t *testing.T) {
+	assert.Equal(t, []string{"host", "port", "prefix", "metrics"},
+		PluginConfigFieldOrder(schemaFromJSON(t, StatsDSchema)))
+
+	// nested records are not flattened
+	assert.Equal(t, []string{"http_method", "remove", "replace"},
+		PluginConfigFieldOrder(schemaFromJSON(t, RequestTransformerSchema)))
+
+	assert.Nil(t, PluginConfigFieldOrder(Schema{"fields": []interface{}{}}))
+}
@@ -0,0 +1,33 @@
+package kong
+
+import (
+	"context"
+	"errors"
+)
+
+// emptyConfigHash is the configuration hash reported by a Kong node which
+// hasn't loaded a configuration yet.
+const emptyConfigHash = "00000000000000000000000000000000"
+
+// ErrConfigHashUnavailable is returned by ConfigHash when the Kong node
+// doesn't report the hash of its configuration.
+var ErrConfigHashUnavailable = errors.New("configuration hash is unavailable")
+
+// ConfigHash returns the hash of the configuration currently running on
+// the Kong node, as reported by its status endpoint.
+// Kong only reports this hash in DB-less mode and on data planes, it
+// returns ErrConfigHashUnavailable otherwise, or if the node hasn't loaded
+// a configuration yet.
+// Comparing the hashes read before and after applying a configuration
+// tells whether the running configuration changed: equal hashes mean the
+// apply was a no-op.
+func (c *Client) ConfigHash(ctx context.Context) (string, error) {
+	status, err := c.Status(ctx)
+	if err != nil {
+		return "", err
+	}
+	if status.ConfigurationHash == "" || status.ConfigurationHash == emptyConfigHash {
+		return "", ErrConfigHashUnavailable
+	}
+	return status.ConfigurationHash, nil
+}
@@ -0,0 +1,42 @@
+package kong
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigHash(t *testing.T) {
+	srv := newFakeKong(t, nil)
+	client := newFakeKongClient(t, srv)
+	setHash := func(hash string) {
+		srv.set("/status", fakeResponse{
+			body: fmt.Sprintf(`{"database": {"reachable": true}, "configuration_hash": %q}`, hash),
+		})
+	}
+
+	setHash("")
+	_, err := client.ConfigHash(defaultCtx)
+	assert.ErrorIs(t, err, ErrConfigHashUnavailable)
+	setHash(emptyConfigHash)
+	_, err = client.ConfigHash(defaultCtx)
+	assert.ErrorIs(t, err, ErrConfigHashUnavailable)
+
+	setHash("a2d7bd1b8c6d4f6ed2cd2587dcd4e5f1")
+	before, err := client.ConfigHash(defaultCtx)
+	require.NoError(t, err)
+	assert.Equal(t, "a2d7bd1b8c6d4f6ed2cd2587dcd4e5f1", before)
+
+	// a no-op apply keeps the hash
+	after, err := client.ConfigHash(defaultCtx)
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+
+	// a change updates it
+	setHash("0f8d5c8b8dfd25da5fd4dd8d0a5f99b6")
+	after, err = client.ConfigHash(defaultCtx)
+	require.NoError(t, err)
+	assert.NotEqual(t, before, after)
+}
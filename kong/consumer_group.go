@@ -43,6 +43,31 @@ type ConsumerGroupPlugin struct {
 	ConsumerGroup *ConsumerGroup `json:"consumer_group,omitempty" yaml:"consumer_group,omitempty"`
 }
 
+// RateLimitSummary summarizes the rate limits applied to the consumers of
+// a ConsumerGroup by its rate-limiting-advanced override.
+type RateLimitSummary struct {
+	// ConsumerGroup is the name, or ID, of the ConsumerGroup.
+	ConsumerGroup string
+	// Windows holds the limits, by increasing window size.
+	Windows []RateLimitWindow
+	// WindowType is "sliding" or "fixed".
+	WindowType string
+	// Namespace is the namespace of the counters, empty if it is
+	// generated by Kong.
+	Namespace string
+	// RetryAfterJitterMax is the maximum jitter added to the Retry-After
+	// header of rate-limited responses, in seconds.
+	RetryAfterJitterMax int
+}
+
+// RateLimitWindow is a number of requests allowed per window.
+type RateLimitWindow struct {
+	// Limit is the number of requests allowed per window.
+	Limit int
+	// WindowSize is the size of the window, in seconds.
+	WindowSize int
+}
+
 // FriendlyName returns the endpoint key name or ID.
 func (s *ConsumerGroup) FriendlyName() string {
 	if s.Name != nil {
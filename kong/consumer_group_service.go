@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"sort"
 )
 
 // AbstractConsumerGroupService handles ConsumerGroups in Kong.
@@ -25,6 +27,21 @@ type AbstractConsumerGroupService interface {
 	UpdateRateLimitingAdvancedPlugin(
 		ctx context.Context, nameOrID *string, config map[string]Configuration,
 	) (*ConsumerGroupRLA, error)
+
+	// SetPlugin upserts a plugin override for a ConsumerGroup in Kong.
+	SetPlugin(
+		ctx context.Context, nameOrID *string, plugin *ConsumerGroupPlugin,
+	) (*ConsumerGroupPlugin, error)
+	// GetPlugin fetches a plugin override of a ConsumerGroup from Kong.
+	GetPlugin(ctx context.Context, nameOrID *string, pluginName *string) (*ConsumerGroupPlugin, error)
+	// DeletePlugin deletes a plugin override of a ConsumerGroup in Kong.
+	DeletePlugin(ctx context.Context, nameOrID *string, pluginName *string) error
+	// DetectNamespaceCollisions reports the rate-limiting-advanced namespaces
+	// shared by the overrides of multiple ConsumerGroups.
+	DetectNamespaceCollisions(ctx context.Context) (map[string][]string, error)
+	// EffectiveRateLimits summarizes the rate-limiting-advanced override
+	// of a ConsumerGroup.
+	EffectiveRateLimits(ctx context.Context, nameOrID *string) (*RateLimitSummary, error)
 }
 
 // ConsumerGroupService handles ConsumerGroup in Kong.
@@ -183,3 +200,200 @@ func (s *ConsumerGroupService) UpdateRateLimitingAdvancedPlugin(
 	}
 	return &rla, nil
 }
+
+// SetPlugin upserts a plugin override for ConsumerGroups in Kong.
+// Defaults from the consumer_group_plugins schema are filled in
+// before the override is sent.
+func (s *ConsumerGroupService) SetPlugin(
+	ctx context.Context, nameOrID *string, plugin *ConsumerGroupPlugin,
+) (*ConsumerGroupPlugin, error) {
+	if isEmptyString(nameOrID) {
+		return nil, fmt.Errorf("nameOrID cannot be nil")
+	}
+	if plugin == nil || isEmptyString(plugin.Name) {
+		return nil, fmt.Errorf("plugin name cannot be nil")
+	}
+
+	schema, err := s.client.Schemas.Get(ctx, "consumer_group_plugins")
+	if err != nil {
+		return nil, err
+	}
+	if err := FillEntityDefaults(plugin, schema); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf(
+		"/consumer_groups/%v/overrides/plugins/%v", *nameOrID, *plugin.Name,
+	)
+	body := map[string]Configuration{"config": plugin.Config}
+	req, err := s.client.NewRequest("PUT", endpoint, nil, body)
+	if err != nil {
+		return nil, err
+	}
+
+	var rla ConsumerGroupRLA
+	_, err = s.client.Do(ctx, req, &rla)
+	if err != nil {
+		return nil, err
+	}
+	res := &ConsumerGroupPlugin{
+		ID:            plugin.ID,
+		Name:          rla.Plugin,
+		Config:        rla.Config,
+		ConsumerGroup: plugin.ConsumerGroup,
+	}
+	if res.ConsumerGroup == nil && rla.ConsumerGroup != nil {
+		res.ConsumerGroup = &ConsumerGroup{Name: rla.ConsumerGroup}
+	}
+	return res, nil
+}
+
+// GetPlugin fetches a plugin override of a ConsumerGroup from Kong.
+// A 404 APIError is returned if the ConsumerGroup has no override
+// for pluginName.
+func (s *ConsumerGroupService) GetPlugin(ctx context.Context,
+	nameOrID *string, pluginName *string,
+) (*ConsumerGroupPlugin, error) {
+	if isEmptyString(pluginName) {
+		return nil, fmt.Errorf("pluginName cannot be nil")
+	}
+
+	cg, err := s.Get(ctx, nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	for _, plugin := range cg.Plugins {
+		if plugin.Name != nil && *plugin.Name == *pluginName {
+			return plugin, nil
+		}
+	}
+	return nil, NewAPIError(http.StatusNotFound,
+		fmt.Sprintf("no %s override found for consumer group %s", *pluginName, *nameOrID))
+}
+
+// DeletePlugin deletes a plugin override of a ConsumerGroup in Kong.
+func (s *ConsumerGroupService) DeletePlugin(ctx context.Context,
+	nameOrID *string, pluginName *string,
+) error {
+	if isEmptyString(nameOrID) {
+		return fmt.Errorf("nameOrID cannot be nil for Delete operation")
+	}
+	if isEmptyString(pluginName) {
+		return fmt.Errorf("pluginName cannot be nil for Delete operation")
+	}
+
+	endpoint := fmt.Sprintf(
+		"/consumer_groups/%v/overrides/plugins/%v", *nameOrID, *pluginName,
+	)
+	req, err := s.client.NewRequest("DELETE", endpoint, nil, nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// DetectNamespaceCollisions fetches the rate-limiting-advanced overrides of
+// all ConsumerGroups and reports the namespaces used by more than one
+// ConsumerGroup. The result maps each such namespace to the names (or IDs)
+// of the ConsumerGroups using it, in listing order.
+// Overrides sharing a namespace share their counters, which is usually
+// not intended.
+func (s *ConsumerGroupService) DetectNamespaceCollisions(
+	ctx context.Context,
+) (map[string][]string, error) {
+	groups, err := s.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make(map[string][]string)
+	for _, group := range groups {
+		cg, err := s.Get(ctx, group.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, plugin := range cg.Plugins {
+			if plugin.Name == nil || *plugin.Name != "rate-limiting-advanced" {
+				continue
+			}
+			namespace, ok := plugin.Config["namespace"].(string)
+			if !ok || namespace == "" {
+				continue
+			}
+			usage[namespace] = append(usage[namespace], group.FriendlyName())
+		}
+	}
+
+	collisions := make(map[string][]string)
+	for namespace, names := range usage {
+		if len(names) > 1 {
+			collisions[namespace] = names
+		}
+	}
+	return collisions, nil
+}
+
+// EffectiveRateLimits fetches the rate-limiting-advanced override of the
+// ConsumerGroup identified by nameOrID and summarizes it. The defaults of
+// the consumer_group_plugins schema apply to the fields missing from the
+// override: a sliding window and no jitter.
+// A 404 APIError is returned if the ConsumerGroup has no override.
+func (s *ConsumerGroupService) EffectiveRateLimits(ctx context.Context,
+	nameOrID *string,
+) (*RateLimitSummary, error) {
+	if isEmptyString(nameOrID) {
+		return nil, fmt.Errorf("nameOrID cannot be nil for EffectiveRateLimits operation")
+	}
+	plugin, err := s.GetPlugin(ctx, nameOrID, String("rate-limiting-advanced"))
+	if err != nil {
+		return nil, err
+	}
+
+	res := &RateLimitSummary{
+		ConsumerGroup: *nameOrID,
+		WindowType:    "sliding",
+	}
+	if plugin.ConsumerGroup != nil {
+		res.ConsumerGroup = plugin.ConsumerGroup.FriendlyName()
+	}
+	if windowType, ok := plugin.Config["window_type"].(string); ok && windowType != "" {
+		res.WindowType = windowType
+	}
+	if namespace, ok := plugin.Config["namespace"].(string); ok {
+		res.Namespace = namespace
+	}
+	if jitter, ok := plugin.Config["retry_after_jitter_max"].(float64); ok {
+		res.RetryAfterJitterMax = int(jitter)
+	}
+
+	limits := configNumbers(plugin.Config["limit"])
+	sizes := configNumbers(plugin.Config["window_size"])
+	if len(limits) != len(sizes) {
+		return nil, fmt.Errorf("consumer group %s: %d limits for %d window sizes",
+			res.ConsumerGroup, len(limits), len(sizes))
+	}
+	for i := range limits {
+		res.Windows = append(res.Windows, RateLimitWindow{
+			Limit:      int(limits[i]),
+			WindowSize: int(sizes[i]),
+		})
+	}
+	sort.SliceStable(res.Windows, func(i, j int) bool {
+		return res.Windows[i].WindowSize < res.Windows[j].WindowSize
+	})
+	return res, nil
+}
+
+// configNumbers returns the numbers of v, an array of a Configuration.
+func configNumbers(v interface{}) []float64 {
+	values, _ := v.([]interface{})
+	var res []float64
+	for _, value := range values {
+		if n, ok := value.(float64); ok {
+			res = append(res, n)
+		}
+	}
+	return res
+}
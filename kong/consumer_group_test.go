@@ -230,6 +230,121 @@ func TestConsumerGroupsRLAService(t *testing.T) {
 	assert.NoError(client.Plugins.Delete(defaultCtx, createdPlugin.ID))
 }
 
+func TestConsumerGroupsPluginOverrides(t *testing.T) {
+	RunWhenEnterprise(t, ">=2.7.0", RequiredFeatures{})
+	require := require.New(t)
+	assert := assert.New(t)
+
+	client, err := NewTestClient(nil, nil)
+	require.NoError(err)
+	require.NotNil(client)
+
+	cg, err := client.ConsumerGroups.Create(defaultCtx, &ConsumerGroup{
+		Name: String("foo"),
+	})
+	require.NoError(err)
+	t.Cleanup(func() {
+		assert.NoError(client.ConsumerGroups.Delete(defaultCtx, cg.ID))
+	})
+
+	rlaPlugin, err := client.Plugins.Create(defaultCtx, &Plugin{
+		Name: String("rate-limiting-advanced"),
+		Config: Configuration{
+			"limit":                   []interface{}{5},
+			"window_size":             []interface{}{30},
+			"enforce_consumer_groups": true,
+			"consumer_groups":         []string{"foo"},
+			"sync_rate":               float64(1),
+			"strategy":                "cluster",
+		},
+	})
+	require.NoError(err)
+	t.Cleanup(func() {
+		assert.NoError(client.Plugins.Delete(defaultCtx, rlaPlugin.ID))
+	})
+
+	override, err := client.ConsumerGroups.SetPlugin(defaultCtx, cg.Name, &ConsumerGroupPlugin{
+		Name: String("rate-limiting-advanced"),
+		Config: Configuration{
+			"limit":       []interface{}{float64(10)},
+			"window_size": []interface{}{float64(10)},
+		},
+	})
+	require.NoError(err)
+	require.NotNil(override)
+	assert.Equal("rate-limiting-advanced", *override.Name)
+	// defaults are filled from the consumer_group_plugins schema
+	assert.Equal("sliding", override.Config["window_type"])
+	assert.Equal(float64(0), override.Config["retry_after_jitter_max"])
+
+	fetched, err := client.ConsumerGroups.GetPlugin(defaultCtx, cg.ID, String("rate-limiting-advanced"))
+	require.NoError(err)
+	require.NotNil(fetched)
+	assert.Equal([]interface{}{float64(10)}, fetched.Config["limit"])
+	assert.Equal([]interface{}{float64(10)}, fetched.Config["window_size"])
+
+	require.NoError(client.ConsumerGroups.DeletePlugin(defaultCtx, cg.Name, String("rate-limiting-advanced")))
+	_, err = client.ConsumerGroups.GetPlugin(defaultCtx, cg.ID, String("rate-limiting-advanced"))
+	assert.True(IsNotFoundErr(err))
+}
+
+func TestConsumerGroupsEffectiveRateLimitsLive(t *testing.T) {
+	RunWhenEnterprise(t, ">=2.7.0", RequiredFeatures{})
+	require := require.New(t)
+	assert := assert.New(t)
+
+	client, err := NewTestClient(nil, nil)
+	require.NoError(err)
+	require.NotNil(client)
+
+	cg, err := client.ConsumerGroups.Create(defaultCtx, &ConsumerGroup{
+		Name: String("gold"),
+	})
+	require.NoError(err)
+	t.Cleanup(func() {
+		assert.NoError(client.ConsumerGroups.Delete(defaultCtx, cg.ID))
+	})
+
+	rlaPlugin, err := client.Plugins.Create(defaultCtx, &Plugin{
+		Name: String("rate-limiting-advanced"),
+		Config: Configuration{
+			"limit":                   []interface{}{5},
+			"window_size":             []interface{}{30},
+			"enforce_consumer_groups": true,
+			"consumer_groups":         []string{"gold"},
+			"sync_rate":               float64(1),
+			"strategy":                "cluster",
+		},
+	})
+	require.NoError(err)
+	t.Cleanup(func() {
+		assert.NoError(client.Plugins.Delete(defaultCtx, rlaPlugin.ID))
+	})
+
+	// no override yet
+	_, err = client.ConsumerGroups.EffectiveRateLimits(defaultCtx, cg.Name)
+	assert.True(IsNotFoundErr(err))
+
+	_, err = client.ConsumerGroups.SetPlugin(defaultCtx, cg.Name, &ConsumerGroupPlugin{
+		Name: String("rate-limiting-advanced"),
+		Config: Configuration{
+			"limit":       []interface{}{float64(100), float64(10)},
+			"window_size": []interface{}{float64(3600), float64(60)},
+		},
+	})
+	require.NoError(err)
+
+	summary, err := client.ConsumerGroups.EffectiveRateLimits(defaultCtx, cg.Name)
+	require.NoError(err)
+	require.NotNil(summary)
+	assert.Equal("sliding", summary.WindowType)
+	assert.Equal(0, summary.RetryAfterJitterMax)
+	assert.Equal([]RateLimitWindow{
+		{Limit: 10, WindowSize: 60},
+		{Limit: 100, WindowSize: 3600},
+	}, summary.Windows)
+}
+
 func compareConsumerGroups(expected, actual []*ConsumerGroup) bool {
 	var expectedNames, actualNames []string
 	for _, cg := range expected {
@@ -242,3 +357,77 @@ func compareConsumerGroups(expected, actual []*ConsumerGroup) bool {
 
 	return (compareSlices(expectedNames, actualNames))
 }
+
+func TestConsumerGroupsDetectNamespaceCollisions(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/consumer_groups": fakeList(`[
+			{"id": "g1", "name": "gold"},
+			{"id": "g2", "name": "silver"},
+			{"id": "g3", "name": "bronze"}
+		]`),
+		"/consumer_groups/g1": {body: `{"consumer_group": {"id": "g1", "name": "gold"}, "plugins": [
+			{"name": "rate-limiting-advanced", "config": {"namespace": "shared", "limit": [10]}}
+		]}`},
+		"/consumer_groups/g2": {body: `{"consumer_group": {"id": "g2", "name": "silver"}, "plugins": [
+			{"name": "rate-limiting-advanced", "config": {"namespace": "shared", "limit": [5]}}
+		]}`},
+		"/consumer_groups/g3": {body: `{"consumer_group": {"id": "g3", "name": "bronze"}, "plugins": [
+			{"name": "rate-limiting-advanced", "config": {"namespace": "bronze", "limit": [1]}}
+		]}`},
+	})
+	client := newFakeKongClient(t, srv)
+
+	collisions, err := client.ConsumerGroups.DetectNamespaceCollisions(defaultCtx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"shared": {"gold", "silver"}}, collisions)
+}
+
+func TestConsumerGroupsEffectiveRateLimits(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/consumer_groups/gold": {body: `{"consumer_group": {"id": "g1", "name": "gold"}, "plugins": [
+			{"name": "rate-limiting-advanced", "config": {
+				"namespace": "gold", "limit": [1000, 10], "window_size": [3600, 60]
+			}}
+		]}`},
+		"/consumer_groups/silver": {body: `{"consumer_group": {"id": "g2", "name": "silver"}, "plugins": [
+			{"name": "rate-limiting-advanced", "config": {
+				"limit": [5], "window_size": [60], "window_type": "fixed", "retry_after_jitter_max": 2
+			}}
+		]}`},
+		"/consumer_groups/bronze": {body: `{"consumer_group": {"id": "g3", "name": "bronze"}, "plugins": [
+			{"name": "rate-limiting-advanced", "config": {"limit": [5, 10], "window_size": [60]}}
+		]}`},
+		"/consumer_groups/none": {body: `{"consumer_group": {"id": "g4", "name": "none"}}`},
+	})
+	client := newFakeKongClient(t, srv)
+
+	summary, err := client.ConsumerGroups.EffectiveRateLimits(defaultCtx, String("gold"))
+	require.NoError(t, err)
+	assert.Equal(t, &RateLimitSummary{
+		ConsumerGroup: "gold",
+		Windows: []RateLimitWindow{
+			{Limit: 10, WindowSize: 60},
+			{Limit: 1000, WindowSize: 3600},
+		},
+		WindowType: "sliding",
+		Namespace:  "gold",
+	}, summary)
+
+	summary, err = client.ConsumerGroups.EffectiveRateLimits(defaultCtx, String("silver"))
+	require.NoError(t, err)
+	assert.Equal(t, &RateLimitSummary{
+		ConsumerGroup:       "silver",
+		Windows:             []RateLimitWindow{{Limit: 5, WindowSize: 60}},
+		WindowType:          "fixed",
+		RetryAfterJitterMax: 2,
+	}, summary)
+
+	_, err = client.ConsumerGroups.EffectiveRateLimits(defaultCtx, String("bronze"))
+	assert.Error(t, err)
+
+	_, err = client.ConsumerGroups.EffectiveRateLimits(defaultCtx, String("none"))
+	assert.True(t, IsNotFoundErr(err))
+
+	_, err = client.ConsumerGroups.EffectiveRateLimits(defaultCtx, nil)
+	assert.Error(t, err)
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sync"
 )
 
 // AbstractConsumerService handles Consumers in Kong.
@@ -23,6 +24,13 @@ type AbstractConsumerService interface {
 	List(ctx context.Context, opt *ListOpt) ([]*Consumer, *ListOpt, error)
 	// ListAll fetches all Consumers in Kong.
 	ListAll(ctx context.Context) ([]*Consumer, error)
+	// ListGroups fetches all ConsumerGroups a Consumer belongs to.
+	ListGroups(ctx context.Context, usernameOrID *string) ([]*ConsumerGroup, error)
+	// ExportCredentials fetches all credentials of a Consumer.
+	ExportCredentials(ctx context.Context, usernameOrID *string) (*ConsumerCredentials, error)
+	// ImportCredentials creates credentials of a Consumer concurrently.
+	ImportCredentials(ctx context.Context, usernameOrID *string, creds []interface{},
+		workers int) (*BatchResult, error)
 }
 
 // ConsumerService handles Consumers in Kong.
@@ -194,3 +202,117 @@ func (s *ConsumerService) ListAll(ctx context.Context) ([]*Consumer, error) {
 	}
 	return consumers, nil
 }
+
+// ListGroups fetches all ConsumerGroups a Consumer belongs to.
+// An empty list is returned if the Consumer doesn't exist.
+func (s *ConsumerService) ListGroups(ctx context.Context,
+	usernameOrID *string,
+) ([]*ConsumerGroup, error) {
+	if isEmptyString(usernameOrID) {
+		return nil, fmt.Errorf("usernameOrID cannot be nil for ListGroups operation")
+	}
+
+	endpoint := fmt.Sprintf("/consumers/%v/consumer_groups", *usernameOrID)
+	var groups []*ConsumerGroup
+	opt := &ListOpt{Size: pageSize}
+	for opt != nil {
+		var data []json.RawMessage
+		var err error
+		data, opt, err = s.client.list(ctx, endpoint, opt)
+		if err != nil {
+			if IsNotFoundErr(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		for _, object := range data {
+			var group ConsumerGroup
+			if err := json.Unmarshal(object, &group); err != nil {
+				return nil, err
+			}
+			groups = append(groups, &group)
+		}
+	}
+	return groups, nil
+}
+
+// ExportCredentials fetches the key-auth, basic-auth, hmac-auth, jwt, acl
+// and oauth2 credentials of a Consumer, listing every type of credential
+// concurrently.
+// The passwords of basic-auth credentials, and the client secrets of
+// oauth2 credentials with hash_secret enabled, are hashed by Kong: these
+// credentials are listed in ConsumerCredentials.HashedSecrets.
+func (s *ConsumerService) ExportCredentials(ctx context.Context,
+	usernameOrID *string,
+) (*ConsumerCredentials, error) {
+	if isEmptyString(usernameOrID) {
+		return nil, fmt.Errorf("usernameOrID cannot be nil for ExportCredentials operation")
+	}
+
+	var res ConsumerCredentials
+	credentials := []struct {
+		path string
+		dest interface{}
+	}{
+		{"key-auth", &res.KeyAuths},
+		{"basic-auth", &res.BasicAuths},
+		{"hmac-auth", &res.HMACAuths},
+		{"jwt", &res.JWTAuths},
+		{"acls", &res.ACLGroups},
+		{"oauth2", &res.Oauth2Credentials},
+	}
+	errs := make([]error, len(credentials))
+	var wg sync.WaitGroup
+	for i, cred := range credentials {
+		wg.Add(1)
+		go func(i int, path string, dest interface{}) {
+			defer wg.Done()
+			endpoint := fmt.Sprintf("/consumers/%v/%v", *usernameOrID, path)
+			if err := s.listAllRaw(ctx, endpoint, dest); err != nil {
+				errs[i] = fmt.Errorf("listing %s credentials: %w", path, err)
+			}
+		}(i, cred.path, cred.dest)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range res.BasicAuths {
+		if c.Password != nil && c.ID != nil {
+			res.HashedSecrets = append(res.HashedSecrets, "basic-auth "+*c.ID)
+		}
+	}
+	for _, c := range res.Oauth2Credentials {
+		if c.HashSecret != nil && *c.HashSecret && c.ID != nil {
+			res.HashedSecrets = append(res.HashedSecrets, "oauth2 "+*c.ID)
+		}
+	}
+	return &res, nil
+}
+
+// listAllRaw decodes all entities listed at endpoint into dest, a pointer
+// to a slice.
+func (s *ConsumerService) listAllRaw(ctx context.Context, endpoint string, dest interface{}) error {
+	var all []json.RawMessage
+	opt := &ListOpt{Size: pageSize}
+	for opt != nil {
+		var data []json.RawMessage
+		var err error
+		data, opt, err = s.client.list(ctx, endpoint, opt)
+		if err != nil {
+			return err
+		}
+		all = append(all, data...)
+	}
+	if len(all) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dest)
+}
@@ -1,6 +1,9 @@
 package kong
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"sort"
 	"testing"
@@ -296,3 +299,194 @@ func compareSlices(expected, actual []string) bool {
 	sort.Strings(actual)
 	return (reflect.DeepEqual(expected, actual))
 }
+
+func TestConsumerListGroups(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/consumers/alice/consumer_groups": fakeList(`[
+			{"id": "g1", "name": "gold"},
+			{"id": "g2", "name": "beta-testers", "tags": ["t1"]}
+		]`),
+		"*": {status: http.StatusNotFound, body: `{"message": "Not found"}`},
+	})
+	client := newFakeKongClient(t, srv)
+
+	groups, err := client.Consumers.ListGroups(defaultCtx, String("alice"))
+	require.NoError(t, err)
+	assert.Equal(t, []*ConsumerGroup{
+		{ID: String("g1"), Name: String("gold")},
+		{ID: String("g2"), Name: String("beta-testers"), Tags: StringSlice("t1")},
+	}, groups)
+
+	groups, err = client.Consumers.ListGroups(defaultCtx, String("bob"))
+	require.NoError(t, err)
+	assert.Empty(t, groups)
+
+	_, err = client.Consumers.ListGroups(defaultCtx, nil)
+	assert.Error(t, err)
+}
+
+func TestConsumerExportCredentials(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		// key-auth credentials are returned in two pages
+		"/consumers/alice/key-auth":             {body: `{"data": [{"id": "k1", "key": "secret1"}], "offset": "next"}`},
+		"/consumers/alice/key-auth?offset=next": fakeList(`[{"id": "k2", "key": "secret2"}]`),
+		"/consumers/alice/basic-auth": fakeList(`[{"id": "b1", "username": "alice",
+			"password": "4bc1bd85b3d8f2be5b4f0b930a5578e6331d4b96"}]`),
+		"/consumers/alice/hmac-auth": fakeList(`[{"id": "h1", "username": "alice", "secret": "hmac"}]`),
+		"/consumers/alice/jwt":       fakeList(`[{"id": "j1", "key": "iss", "algorithm": "HS256"}]`),
+		"/consumers/alice/acls":      fakeList(`[{"id": "a1", "group": "admins"}]`),
+		"/consumers/alice/oauth2": fakeList(`[
+			{"id": "o1", "name": "app1", "client_id": "c1", "client_secret": "plain", "hash_secret": false},
+			{"id": "o2", "name": "app2", "client_id": "c2", "client_secret": "$pbkdf2-sha512$i=10000",
+				"hash_secret": true}
+		]`),
+		"*": {status: http.StatusNotFound, body: `{"message": "Not found"}`},
+	})
+	client := newFakeKongClient(t, srv)
+
+	creds, err := client.Consumers.ExportCredentials(defaultCtx, String("alice"))
+	require.NoError(t, err)
+	require.Len(t, creds.KeyAuths, 2)
+	assert.Equal(t, "secret2", *creds.KeyAuths[1].Key)
+	require.Len(t, creds.BasicAuths, 1)
+	require.Len(t, creds.HMACAuths, 1)
+	assert.Equal(t, "hmac", *creds.HMACAuths[0].Secret)
+	require.Len(t, creds.JWTAuths, 1)
+	assert.Equal(t, "HS256", *creds.JWTAuths[0].Algorithm)
+	require.Len(t, creds.ACLGroups, 1)
+	assert.Equal(t, "admins", *creds.ACLGroups[0].Group)
+	require.Len(t, creds.Oauth2Credentials, 2)
+	assert.Equal(t, []string{"basic-auth b1", "oauth2 o2"}, creds.HashedSecrets)
+
+	_, err = client.Consumers.ExportCredentials(defaultCtx, String("bob"))
+	assert.ErrorContains(t, err, "listing key-auth credentials")
+	_, err = client.Consumers.ExportCredentials(defaultCtx, nil)
+	assert.Error(t, err)
+}
+
+func TestConsumerImportCredentials(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/consumers/c1/key-auth/existing":
+			_, _ = w.Write([]byte(`{"id": "k1", "key": "existing", "consumer": {"id": "c1"}}`))
+			return
+		case "/consumers/c1/hmac-auth/bob":
+			_, _ = w.Write([]byte(`{"id": "h1", "username": "bob", "secret": "s1", "consumer": {"id": "c1"}}`))
+			return
+		}
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "invalid body"}`))
+			return
+		}
+		switch {
+		case r.URL.Path == "/consumers/c1/key-auth" && (body["key"] == "existing" || body["key"] == "taken"),
+			r.URL.Path == "/consumers/c1/hmac-auth" && body["username"] == "bob":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message": "UNIQUE violation detected"}`))
+		case r.URL.Path == "/consumers/c1/key-auth" && body["key"] == "invalid":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "schema violation"}`))
+		case r.URL.Path == "/consumers/c1/key-auth" || r.URL.Path == "/consumers/c1/acls":
+			body["id"] = "id-" + r.URL.Path
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	client, err := NewClient(String(srv.URL), nil)
+	require.NoError(t, err)
+
+	creds := []interface{}{
+		&KeyAuth{Key: String("new")},
+		&ACLGroup{Group: String("admins")},
+		&KeyAuth{Key: String("existing")},
+		&KeyAuth{Key: String("invalid")},
+		&Consumer{Username: String("not-a-credential")},
+		&KeyAuth{Key: String("taken")},
+		&HMACAuth{Username: String("bob"), Secret: String("s2")},
+	}
+	res, err := client.Consumers.ImportCredentials(defaultCtx, String("c1"), creds, 3)
+	require.NoError(t, err)
+	require.Len(t, res.Items, 7)
+
+	assert.Equal(t, "new", *res.Items[0].Created.(*KeyAuth).Key)
+	assert.NoError(t, res.Items[0].Err)
+	assert.Equal(t, "admins", *res.Items[1].Created.(*ACLGroup).Group)
+	assert.Equal(t, BatchItemResult{Duplicate: true}, res.Items[2])
+	assert.Nil(t, res.Items[3].Created)
+	assert.ErrorContains(t, res.Items[3].Err, "schema violation")
+	assert.ErrorContains(t, res.Items[4].Err, "unsupported credential type: *kong.Consumer")
+	assert.False(t, res.Items[5].Duplicate)
+	assert.ErrorContains(t, res.Items[5].Err, "conflicts with a credential of another consumer")
+	assert.True(t, IsConflictErr(res.Items[5].Err))
+	assert.False(t, res.Items[6].Duplicate)
+	assert.ErrorContains(t, res.Items[6].Err, "conflicts with an existing credential with a different secret")
+	assert.Len(t, res.Errors(), 4)
+
+	_, err = client.Consumers.ImportCredentials(defaultCtx, nil, creds, 1)
+	assert.Error(t, err)
+}
+
+func TestConsumerImportCredentialsLive(T *testing.T) {
+	RunWhenDBMode(T, "postgres")
+
+	assert := assert.New(T)
+	require := require.New(T)
+
+	client, err := NewTestClient(nil, nil)
+	require.NoError(err)
+	require.NotNil(client)
+
+	foo, err := client.Consumers.Create(defaultCtx, &Consumer{Username: String("foo")})
+	require.NoError(err)
+	T.Cleanup(func() {
+		assert.NoError(client.Consumers.Delete(defaultCtx, foo.ID))
+	})
+	bar, err := client.Consumers.Create(defaultCtx, &Consumer{Username: String("bar")})
+	require.NoError(err)
+	T.Cleanup(func() {
+		assert.NoError(client.Consumers.Delete(defaultCtx, bar.ID))
+	})
+
+	// fixtures
+	_, err = client.KeyAuths.Create(defaultCtx, foo.ID, &KeyAuth{Key: String("existing")})
+	require.NoError(err)
+	_, err = client.KeyAuths.Create(defaultCtx, bar.ID, &KeyAuth{Key: String("taken")})
+	require.NoError(err)
+
+	creds := []interface{}{
+		&KeyAuth{Key: String("new")},
+		&ACLGroup{Group: String("admins")},
+		&KeyAuth{Key: String("existing")},
+		&KeyAuth{Key: String("taken")},
+	}
+	res, err := client.Consumers.ImportCredentials(defaultCtx, foo.Username, creds, 2)
+	require.NoError(err)
+	require.Len(res.Items, 4)
+	require.NotNil(res.Items[0].Created)
+	assert.Equal("new", *res.Items[0].Created.(*KeyAuth).Key)
+	require.NotNil(res.Items[1].Created)
+	assert.Equal("admins", *res.Items[1].Created.(*ACLGroup).Group)
+	assert.Equal(BatchItemResult{Duplicate: true}, res.Items[2])
+	assert.True(IsConflictErr(res.Items[3].Err))
+	assert.Len(res.Errors(), 1)
+
+	// running the import again only reports duplicates
+	res, err = client.Consumers.ImportCredentials(defaultCtx, foo.Username, creds[:3], 2)
+	require.NoError(err)
+	for _, item := range res.Items {
+		assert.Equal(BatchItemResult{Duplicate: true}, item)
+	}
+
+	keys, _, err := client.KeyAuths.ListForConsumer(defaultCtx, foo.ID, nil)
+	require.NoError(err)
+	assert.Len(keys, 2)
+}
@@ -0,0 +1,355 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// Content represents a declarative configuration of Kong entities,
+// in the format accepted by the /config endpoint.
+// Read https://docs.konghq.com/gateway/latest/production/deployment-topologies/db-less-and-declarative-config/
+type Content struct {
+	FormatVersion *string     `json:"_format_version,omitempty" yaml:"_format_version,omitempty"`
+	Services      []*Service  `json:"services,omitempty" yaml:"services,omitempty"`
+	Routes        []*Route    `json:"routes,omitempty" yaml:"routes,omitempty"`
+	Consumers     []*Consumer `json:"consumers,omitempty" yaml:"consumers,omitempty"`
+	Upstreams     []*Upstream `json:"upstreams,omitempty" yaml:"upstreams,omitempty"`
+	Targets       []*Target   `json:"targets,omitempty" yaml:"targets,omitempty"`
+	Plugins       []*Plugin   `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+	// ConsumerGroups holds the Consumer Groups of Kong Gateway Enterprise,
+	// along with their member Consumers and their plugin overrides. The
+	// fields of a Consumer Group sit next to its consumers and plugins in
+	// the JSON and YAML representations of Content.
+	ConsumerGroups []*ConsumerGroupObject `json:"consumer_groups,omitempty" yaml:"consumer_groups,omitempty"`
+
+	// ConsumerCredentials holds the credentials of Consumers, keyed by the
+	// FriendlyName of their Consumer. They are nested under their Consumer
+	// in the JSON and YAML representations of Content, e.g. as
+	// keyauth_credentials.
+	ConsumerCredentials map[string]*ConsumerCredentials `json:"-" yaml:"-"`
+
+	// SelectTags scopes the configuration to the entities having all these
+	// tags: Plan ignores the other entities of Kong. It is laid out under
+	// _info.select_tags in the JSON and YAML representations of Content.
+	SelectTags []string `json:"-" yaml:"-"`
+}
+
+// contentInfo is the _info metadata of the decK file format.
+type contentInfo struct {
+	SelectTags []string `json:"select_tags,omitempty"`
+}
+
+// nestedContent is the JSON representation of Content, with credentials
+// nested under their Consumer.
+type nestedContent struct {
+	*content
+	Info           *contentInfo           `json:"_info,omitempty"`
+	Consumers      []json.RawMessage      `json:"consumers,omitempty"`
+	ConsumerGroups []contentConsumerGroup `json:"consumer_groups,omitempty"`
+}
+
+// contentConsumerGroup is the decK layout of a ConsumerGroupObject: the
+// fields of the Consumer Group along with its members and its plugin
+// overrides.
+type contentConsumerGroup struct {
+	*ConsumerGroup
+	Consumers []*Consumer            `json:"consumers,omitempty"`
+	Plugins   []*ConsumerGroupPlugin `json:"plugins,omitempty"`
+}
+
+func newContentConsumerGroup(group *ConsumerGroupObject) contentConsumerGroup {
+	return contentConsumerGroup{
+		ConsumerGroup: group.ConsumerGroup,
+		Consumers:     group.Consumers,
+		Plugins:       group.Plugins,
+	}
+}
+
+func (g contentConsumerGroup) object() *ConsumerGroupObject {
+	return &ConsumerGroupObject{
+		ConsumerGroup: g.ConsumerGroup,
+		Consumers:     g.Consumers,
+		Plugins:       g.Plugins,
+	}
+}
+
+// content has the fields of Content but not its methods.
+type content Content
+
+// MarshalJSON implements json.Marshaler, nesting credentials under their
+// Consumer.
+func (c Content) MarshalJSON() ([]byte, error) {
+	res := nestedContent{content: (*content)(&c)}
+	if len(c.SelectTags) > 0 {
+		res.Info = &contentInfo{SelectTags: c.SelectTags}
+	}
+	for _, consumer := range c.Consumers {
+		b, err := json.Marshal(consumer)
+		if err != nil {
+			return nil, err
+		}
+		creds := c.ConsumerCredentials[consumer.FriendlyName()]
+		if creds != nil && !creds.IsEmpty() {
+			if b, err = mergeJSONObjects(b, creds.withoutHashedSecrets()); err != nil {
+				return nil, err
+			}
+		}
+		res.Consumers = append(res.Consumers, b)
+	}
+	for _, group := range c.ConsumerGroups {
+		if group != nil {
+			res.ConsumerGroups = append(res.ConsumerGroups, newContentConsumerGroup(group))
+		}
+	}
+	return json.Marshal(res)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, collecting the credentials
+// nested under Consumers in ConsumerCredentials.
+func (c *Content) UnmarshalJSON(data []byte) error {
+	res := nestedContent{content: (*content)(c)}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return err
+	}
+	c.SelectTags = nil
+	if res.Info != nil {
+		c.SelectTags = res.Info.SelectTags
+	}
+	c.Consumers = nil
+	for _, raw := range res.Consumers {
+		var consumer Consumer
+		if err := json.Unmarshal(raw, &consumer); err != nil {
+			return err
+		}
+		var creds ConsumerCredentials
+		if err := json.Unmarshal(raw, &creds); err != nil {
+			return err
+		}
+		if !creds.IsEmpty() {
+			if c.ConsumerCredentials == nil {
+				c.ConsumerCredentials = make(map[string]*ConsumerCredentials)
+			}
+			c.ConsumerCredentials[consumer.FriendlyName()] = &creds
+		}
+		c.Consumers = append(c.Consumers, &consumer)
+	}
+	c.ConsumerGroups = nil
+	for _, group := range res.ConsumerGroups {
+		c.ConsumerGroups = append(c.ConsumerGroups, group.object())
+	}
+	return nil
+}
+
+// mergeJSONObjects returns the JSON object holding the fields of the JSON
+// object a and of v.
+func mergeJSONObjects(a []byte, v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var res, fields map[string]json.RawMessage
+	if err := json.Unmarshal(a, &res); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for k, v := range fields {
+		res[k] = v
+	}
+	return json.Marshal(res)
+}
+
+// minFormatVersionServerVersions maps the declarative configuration
+// format versions to the first Kong version supporting them.
+var minFormatVersionServerVersions = map[string]Version{
+	"1.1": MustNewVersion("1.1.0"),
+	"2.1": MustNewVersion("2.1.0"),
+	"3.0": MustNewVersion("3.0.0"),
+}
+
+// ValidateFormatVersion returns an error if the _format_version of content
+// is missing, unknown or not supported by a Kong server of serverVersion.
+func (c *Content) ValidateFormatVersion(serverVersion Version) error {
+	if isEmptyString(c.FormatVersion) {
+		return fmt.Errorf("_format_version is required")
+	}
+	minVersion, ok := minFormatVersionServerVersions[*c.FormatVersion]
+	if !ok {
+		return fmt.Errorf("unknown _format_version '%s'", *c.FormatVersion)
+	}
+	if serverVersion.Compare(minVersion) < 0 {
+		return fmt.Errorf("_format_version '%s' requires Kong %s or later, got %s",
+			*c.FormatVersion, minVersion, serverVersion)
+	}
+	return nil
+}
+
+// FillContentDefaults ingests the defaults of every entity in content from
+// the schemas served by Kong. Schemas are fetched with
+// SchemaService.GetForEntity, so each of them is only fetched once for the
+// lifetime of the client.
+// Entities are mutated in place.
+func (c *Client) FillContentDefaults(ctx context.Context, content *Content) error {
+	if content == nil {
+		return fmt.Errorf("content cannot be nil")
+	}
+
+	fill := func(entity string, e interface{}) error {
+		schema, err := c.Schemas.GetForEntity(ctx, entity, "")
+		if err != nil {
+			return fmt.Errorf("fetching %s schema: %w", entity, err)
+		}
+		return FillEntityDefaults(e, schema)
+	}
+
+	for _, s := range content.Services {
+		if err := fill("services", s); err != nil {
+			return err
+		}
+	}
+	for _, r := range content.Routes {
+		if err := fill("routes", r); err != nil {
+			return err
+		}
+	}
+	for _, u := range content.Upstreams {
+		if err := fill("upstreams", u); err != nil {
+			return err
+		}
+	}
+	for _, t := range content.Targets {
+		if err := fill("targets", t); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range content.Plugins {
+		if err := c.fillPluginDefaults(ctx, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FillPluginsDefaultsBatch ingests the defaults of plugins from their
+// schemas, using workers goroutines. Schemas are fetched with
+// SchemaService.GetForEntity, so that each schema is fetched once and
+// shared by the workers.
+// The returned slice holds the error which occurred for each plugin, at
+// the same index, or nil if the defaults of the plugin were filled.
+// Plugins are mutated in place.
+func (c *Client) FillPluginsDefaultsBatch(ctx context.Context, plugins []*Plugin, workers int) []error {
+	if workers < 1 {
+		workers = 1
+	}
+	errs := make([]error, len(plugins))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = c.fillPluginDefaults(ctx, plugins[i])
+			}
+		}()
+	}
+	for i := range plugins {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+	return errs
+}
+
+// FillPluginsDefaultsNoCache ingests the defaults of plugin from its
+// schema, which is always fetched from Kong instead of being read from
+// the cache of SchemaService.GetForEntity. The fetched schema replaces the
+// cached one, see SchemaService.RefreshForEntity.
+// plugin is mutated in place.
+func (c *Client) FillPluginsDefaultsNoCache(ctx context.Context, plugin *Plugin) error {
+	return c.fillPluginDefaultsFrom(ctx, plugin, c.Schemas.RefreshForEntity)
+}
+
+func (c *Client) fillPluginDefaults(ctx context.Context, plugin *Plugin) error {
+	return c.fillPluginDefaultsFrom(ctx, plugin, c.Schemas.GetForEntity)
+}
+
+func (c *Client) fillPluginDefaultsFrom(ctx context.Context, plugin *Plugin,
+	getSchema func(ctx context.Context, entity string, subtype string) (Schema, error),
+) error {
+	if plugin == nil || isEmptyString(plugin.Name) {
+		return fmt.Errorf("plugin name cannot be empty")
+	}
+	schema, err := getSchema(ctx, "plugins", *plugin.Name)
+	if err != nil {
+		return fmt.Errorf("fetching schema for plugin %s: %w", *plugin.Name, err)
+	}
+	if err := FillPluginsDefaults(plugin, schema); err != nil {
+		return fmt.Errorf("filling defaults for plugin %s: %w", *plugin.Name, err)
+	}
+	return nil
+}
+
+// minDynamicOrderingVersion is the first Kong Gateway Enterprise version
+// supporting the ordering field of plugins.
+var minDynamicOrderingVersion = MustNewVersion("3.0.0")
+
+// CheckContentCompatibility reports the entities of content which can't be
+// applied to the Kong gateway the client talks to:
+//   - plugins which are not available on the server, if the server reports
+//     them in plugins.available_on_server
+//   - plugins using dynamic ordering on gateways not supporting it
+//   - routes which can't be used with the router flavor of the gateway
+//
+// A nil slice is returned if content is compatible.
+func (c *Client) CheckContentCompatibility(ctx context.Context, content *Content) ([]string, error) {
+	if content == nil {
+		return nil, fmt.Errorf("content cannot be nil")
+	}
+
+	info, err := c.Root(ctx)
+	if err != nil {
+		return nil, err
+	}
+	version, err := ParseSemanticVersion(VersionFromInfo(info))
+	if err != nil {
+		return nil, err
+	}
+	flavor, err := routerFlavorFromInfo(info)
+	if err != nil {
+		return nil, err
+	}
+	available := availablePluginsFromInfo(info)
+	supportsOrdering := version.IsKongGatewayEnterprise() &&
+		version.Compare(minDynamicOrderingVersion) >= 0
+
+	var issues []string
+	missing := make(map[string]bool)
+	for _, p := range content.Plugins {
+		if isEmptyString(p.Name) {
+			issues = append(issues, "plugin with no name")
+			continue
+		}
+		if _, ok := available[*p.Name]; !ok && available != nil {
+			if !missing[*p.Name] {
+				missing[*p.Name] = true
+				issues = append(issues, fmt.Sprintf("plugin '%s' is not available on the server", *p.Name))
+			}
+			continue
+		}
+		if p.Ordering != nil && !supportsOrdering {
+			issues = append(issues, fmt.Sprintf(
+				"plugin '%s' uses dynamic ordering which isn't supported by Kong %s", *p.Name, version))
+		}
+	}
+	for _, r := range content.Routes {
+		if err := r.ValidateForFlavor(flavor); err != nil {
+			issues = append(issues, err.Error())
+		}
+	}
+	return issues, nil
+}
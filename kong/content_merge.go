@@ -0,0 +1,191 @@
+package kong
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// MergeContent returns the Content composed of base and overlay, to layer
+// configurations, e.g. environment specific overlays on top of a shared
+// base. Neither base nor overlay is modified.
+// Entities are matched using their natural key, the way Plan matches
+// them. The entities of base come first, merged with the matching entity
+// of overlay if any, followed by the entities only found in overlay.
+// When merging two entities, the fields set in overlay replace the fields
+// of base, arrays included, except for the config of Plugins which is
+// merged recursively. The members and the overrides of a Consumer Group
+// in overlay replace the ones of base, as any array does, while the
+// fields of the group itself are merged. The credentials of a Consumer in
+// overlay replace its credentials in base. The format version and select
+// tags of overlay win if set.
+// An error is returned if merged entities conflict: a Route using another
+// Service, or entities having different IDs. Entities with the same key
+// in base or in overlay are rejected too.
+func MergeContent(base, overlay *Content) (*Content, error) {
+	if base == nil || overlay == nil {
+		return nil, fmt.Errorf("base and overlay content cannot be nil")
+	}
+
+	// base entities are indexed first so that overlay entities can refer
+	// to them by ID
+	keys := planKeys{}
+	baseEntities := planEntities(base, keys)
+	overlayEntities := planEntities(overlay, keys)
+
+	res := &Content{
+		FormatVersion: base.FormatVersion,
+		SelectTags:    base.SelectTags,
+	}
+	if overlay.FormatVersion != nil {
+		res.FormatVersion = overlay.FormatVersion
+	}
+	if len(overlay.SelectTags) > 0 {
+		res.SelectTags = overlay.SelectTags
+	}
+
+	var conflicts []string
+	for _, entityType := range planEntityTypes {
+		overlays := make(map[string]interface{})
+		for _, e := range overlayEntities[entityType] {
+			if _, ok := overlays[e.key]; ok {
+				return nil, fmt.Errorf("duplicate %s '%s' in overlay content", entityType, e.key)
+			}
+			overlays[e.key] = e.entity
+		}
+		seen := make(map[string]bool)
+		for _, e := range baseEntities[entityType] {
+			if seen[e.key] {
+				return nil, fmt.Errorf("duplicate %s '%s' in base content", entityType, e.key)
+			}
+			seen[e.key] = true
+
+			o, ok := overlays[e.key]
+			if !ok {
+				appendEntity(res, withEntityID(e.entity, entityID(e.entity)))
+				continue
+			}
+			conflicts = append(conflicts, mergeConflicts(entityType, e.key, e.entity, o, keys)...)
+			merged, err := mergeEntity(e.entity, o)
+			if err != nil {
+				return nil, fmt.Errorf("merging %s '%s': %w", entityType, e.key, err)
+			}
+			appendEntity(res, merged)
+		}
+		for _, e := range overlayEntities[entityType] {
+			if !seen[e.key] {
+				appendEntity(res, withEntityID(e.entity, entityID(e.entity)))
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("conflicting content: %s", strings.Join(conflicts, "; "))
+	}
+
+	for _, credentials := range []map[string]*ConsumerCredentials{
+		base.ConsumerCredentials, overlay.ConsumerCredentials,
+	} {
+		for consumer, c := range credentials {
+			if res.ConsumerCredentials == nil {
+				res.ConsumerCredentials = make(map[string]*ConsumerCredentials)
+			}
+			res.ConsumerCredentials[consumer] = c.DeepCopy()
+		}
+	}
+	return res, nil
+}
+
+// mergeConflicts returns the reasons why the entities base and overlay,
+// both having key, can't be merged.
+func mergeConflicts(entityType, key string, base, overlay interface{}, keys planKeys) []string {
+	var conflicts []string
+	if baseID, overlayID := entityID(base), entityID(overlay); baseID != nil && overlayID != nil &&
+		*baseID != *overlayID {
+		conflicts = append(conflicts, fmt.Sprintf("%s '%s' has ID '%s' in base and '%s' in overlay",
+			entityType, key, *baseID, *overlayID))
+	}
+	if b, ok := base.(*Route); ok {
+		o := overlay.(*Route)
+		if b.Service != nil && o.Service != nil {
+			baseService := keys.ref("services", b.Service.Name, b.Service.ID)
+			overlayService := keys.ref("services", o.Service.Name, o.Service.ID)
+			if baseService != overlayService {
+				conflicts = append(conflicts, fmt.Sprintf(
+					"route '%s' uses service '%s' in base and '%s' in overlay",
+					key, baseService, overlayService))
+			}
+		}
+	}
+	return conflicts
+}
+
+// mergeEntity returns a copy of base with the fields set in overlay.
+func mergeEntity(base, overlay interface{}) (interface{}, error) {
+	b, err := toJSONObject(base)
+	if err != nil {
+		return nil, err
+	}
+	o, err := toJSONObject(overlay)
+	if err != nil {
+		return nil, err
+	}
+	for field, value := range o {
+		bm, bok := b[field].(map[string]interface{})
+		om, ook := value.(map[string]interface{})
+		if _, isPlugin := base.(*Plugin); isPlugin && field == "config" && bok && ook {
+			b[field] = mergeConfigs(bm, om)
+			continue
+		}
+		if _, isGroup := base.(*ConsumerGroupObject); isGroup && field == "consumer_group" && bok && ook {
+			for k, v := range om {
+				bm[k] = v
+			}
+			continue
+		}
+		b[field] = value
+	}
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	res := reflect.New(reflect.TypeOf(base).Elem()).Interface()
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// mergeConfigs sets the fields of overlay in base, recursively.
+func mergeConfigs(base, overlay map[string]interface{}) map[string]interface{} {
+	for k, ov := range overlay {
+		if om, ok := ov.(map[string]interface{}); ok {
+			if bm, ok := base[k].(map[string]interface{}); ok {
+				base[k] = mergeConfigs(bm, om)
+				continue
+			}
+		}
+		base[k] = ov
+	}
+	return base
+}
+
+func appendEntity(content *Content, entity interface{}) {
+	switch e := entity.(type) {
+	case *Service:
+		content.Services = append(content.Services, e)
+	case *Route:
+		content.Routes = append(content.Routes, e)
+	case *Consumer:
+		content.Consumers = append(content.Consumers, e)
+	case *Upstream:
+		content.Upstreams = append(content.Upstreams, e)
+	case *Target:
+		content.Targets = append(content.Targets, e)
+	case *Plugin:
+		content.Plugins = append(content.Plugins, e)
+	case *ConsumerGroupObject:
+		content.ConsumerGroups = append(content.ConsumerGroups, e)
+	}
+}
@@ -0,0 +1,150 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeContent(t *testing.T) {
+	base := &Content{
+		FormatVersion: String("3.0"),
+		Services: []*Service{
+			{Name: String("svc1"), Host: String("example.com"), Port: Int(80), Tags: StringSlice("base")},
+			{Name: String("svc2"), Host: String("other.example.com")},
+		},
+		Routes: []*Route{
+			{Name: String("route1"), Paths: StringSlice("/foo"), Service: &Service{Name: String("svc1")}},
+		},
+		Plugins: []*Plugin{
+			{
+				Name:    String("rate-limiting"),
+				Service: &Service{Name: String("svc1")},
+				Config: Configuration{
+					"minute": float64(10),
+					"policy": "redis",
+					"redis":  map[string]interface{}{"host": "redis", "port": float64(6379)},
+				},
+			},
+		},
+		ConsumerCredentials: map[string]*ConsumerCredentials{
+			"alice": {KeyAuths: []*KeyAuth{{Key: String("base")}}},
+			"bob":   {KeyAuths: []*KeyAuth{{Key: String("bob")}}},
+		},
+		ConsumerGroups: []*ConsumerGroupObject{
+			{
+				ConsumerGroup: &ConsumerGroup{Name: String("gold")},
+				Consumers:     []*Consumer{{Username: String("alice")}},
+			},
+			{ConsumerGroup: &ConsumerGroup{Name: String("silver")}},
+		},
+	}
+	overlay := &Content{
+		Services: []*Service{
+			{Name: String("svc1"), Host: String("staging.example.com"), Tags: StringSlice("staging")},
+		},
+		Routes: []*Route{
+			{Name: String("route2"), Paths: StringSlice("/bar"), Service: &Service{Name: String("svc2")}},
+		},
+		Plugins: []*Plugin{
+			{
+				Name:    String("rate-limiting"),
+				Service: &Service{Name: String("svc1")},
+				Config: Configuration{
+					"minute": float64(100),
+					"redis":  map[string]interface{}{"host": "staging-redis"},
+				},
+			},
+		},
+		ConsumerCredentials: map[string]*ConsumerCredentials{
+			"alice": {KeyAuths: []*KeyAuth{{Key: String("overlay")}}},
+		},
+		ConsumerGroups: []*ConsumerGroupObject{
+			{
+				ConsumerGroup: &ConsumerGroup{Name: String("gold")},
+				Consumers:     []*Consumer{{Username: String("bob")}},
+			},
+		},
+	}
+
+	merged, err := MergeContent(base, overlay)
+	require.NoError(t, err)
+	assert.Equal(t, &Content{
+		FormatVersion: String("3.0"),
+		Services: []*Service{
+			{
+				Name: String("svc1"), Host: String("staging.example.com"), Port: Int(80),
+				Tags: StringSlice("staging"),
+			},
+			{Name: String("svc2"), Host: String("other.example.com")},
+		},
+		Routes: []*Route{
+			{Name: String("route1"), Paths: StringSlice("/foo"), Service: &Service{Name: String("svc1")}},
+			{Name: String("route2"), Paths: StringSlice("/bar"), Service: &Service{Name: String("svc2")}},
+		},
+		Plugins: []*Plugin{
+			{
+				Name:    String("rate-limiting"),
+				Service: &Service{Name: String("svc1")},
+				Config: Configuration{
+					"minute": float64(100),
+					"policy": "redis",
+					"redis":  map[string]interface{}{"host": "staging-redis", "port": float64(6379)},
+				},
+			},
+		},
+		ConsumerCredentials: map[string]*ConsumerCredentials{
+			"alice": {KeyAuths: []*KeyAuth{{Key: String("overlay")}}},
+			"bob":   {KeyAuths: []*KeyAuth{{Key: String("bob")}}},
+		},
+		ConsumerGroups: []*ConsumerGroupObject{
+			{
+				ConsumerGroup: &ConsumerGroup{Name: String("gold")},
+				Consumers:     []*Consumer{{Username: String("bob")}},
+			},
+			{ConsumerGroup: &ConsumerGroup{Name: String("silver")}},
+		},
+	}, merged)
+
+	// the inputs are left untouched
+	assert.Equal(t, "example.com", *base.Services[0].Host)
+	assert.Equal(t, map[string]interface{}{"host": "redis", "port": float64(6379)},
+		base.Plugins[0].Config["redis"])
+	merged.Services[1].Host = String("changed")
+	assert.Equal(t, "other.example.com", *base.Services[1].Host)
+}
+
+func TestMergeContentConflicts(t *testing.T) {
+	base := &Content{
+		Services: []*Service{
+			{ID: String("s1"), Name: String("svc1")},
+			{ID: String("s2"), Name: String("svc2")},
+		},
+		Routes: []*Route{
+			{Name: String("route1"), Service: &Service{ID: String("s1")}},
+		},
+	}
+
+	// references by ID and by name to the same service don't conflict
+	_, err := MergeContent(base, &Content{
+		Routes: []*Route{{Name: String("route1"), Service: &Service{Name: String("svc1")}}},
+	})
+	assert.NoError(t, err)
+
+	_, err = MergeContent(base, &Content{
+		Services: []*Service{{ID: String("other"), Name: String("svc1")}},
+		Routes:   []*Route{{Name: String("route1"), Service: &Service{Name: String("svc2")}}},
+	})
+	assert.EqualError(t, err, "conflicting content: "+
+		"services 'svc1' has ID 's1' in base and 'other' in overlay; "+
+		"route 'route1' uses service 'svc1' in base and 'svc2' in overlay")
+
+	_, err = MergeContent(base, &Content{
+		Consumers: []*Consumer{{Username: String("alice")}, {Username: String("alice")}},
+	})
+	assert.EqualError(t, err, "duplicate consumers 'alice' in overlay content")
+
+	_, err = MergeContent(nil, base)
+	assert.Error(t, err)
+}
@@ -0,0 +1,335 @@
+package kong
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	contentServicesSchema = `{
+	"fields": [
+		{ "id": { "type": "string", "auto": true } },
+		{ "protocol": { "type": "string", "default": "http" } },
+		{ "port": { "type": "integer", "default": 80 } },
+		{ "retries": { "type": "integer", "default": 5 } },
+		{ "connect_timeout": { "type": "integer", "default": 60000 } }
+	]
+}`
+	contentRoutesSchema = `{
+	"fields": [
+		{ "id": { "type": "string", "auto": true } },
+		{ "protocols": { "type": "set", "default": ["http", "https"] } },
+		{ "strip_path": { "type": "boolean", "default": true } },
+		{ "preserve_host": { "type": "boolean", "default": false } }
+	]
+}`
+	contentKeyAuthSchema = `{
+	"fields": [
+		{ "protocols": { "type": "set", "default": ["grpc", "grpcs", "http", "https"] } },
+		{
+			"config": {
+				"type": "record",
+				"fields": [
+					{ "key_names": { "type": "array", "default": ["apikey"], "elements": { "type": "string" } } },
+					{ "hide_credentials": { "type": "boolean", "default": false } }
+				]
+			}
+		}
+	]
+}`
+)
+
+func TestFillContentDefaults(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/schemas/services":         {body: contentServicesSchema},
+		"/schemas/routes":           {body: contentRoutesSchema},
+		"/schemas/plugins/key-auth": {body: contentKeyAuthSchema},
+	})
+	defer srv.Close()
+
+	client, err := NewClient(String(srv.URL), nil)
+	require.NoError(t, err)
+
+	content := &Content{
+		Services: []*Service{
+			{Name: String("s1"), Host: String("example.com")},
+			{Name: String("s2"), Host: String("example.com"), Port: Int(8080)},
+		},
+		Routes: []*Route{
+			{Name: String("r1"), Paths: StringSlice("/r1"), StripPath: Bool(false)},
+		},
+		Plugins: []*Plugin{
+			{Name: String("key-auth")},
+		},
+	}
+	require.NoError(t, client.FillContentDefaults(defaultCtx, content))
+
+	assert.Equal(t, "http", *content.Services[0].Protocol)
+	assert.Equal(t, 80, *content.Services[0].Port)
+	assert.Equal(t, 5, *content.Services[0].Retries)
+	assert.Equal(t, 8080, *content.Services[1].Port)
+
+	assert.Equal(t, StringSlice("http", "https"), content.Routes[0].Protocols)
+	assert.False(t, *content.Routes[0].StripPath)
+	assert.False(t, *content.Routes[0].PreserveHost)
+
+	assert.True(t, *content.Plugins[0].Enabled)
+	assert.Equal(t, []interface{}{"apikey"}, content.Plugins[0].Config["key_names"])
+	assert.Equal(t, false, content.Plugins[0].Config["hide_credentials"])
+	assert.Len(t, content.Plugins[0].Protocols, 4)
+
+	// schemas are cached across calls
+	require.NoError(t, client.FillContentDefaults(defaultCtx, &Content{
+		Services: []*Service{{Name: String("s3"), Host: String("example.com")}},
+		Plugins:  []*Plugin{{Name: String("key-auth")}, {Name: String("key-auth")}},
+	}))
+	assert.Equal(t, 1, srv.count("/schemas/services"))
+	assert.Equal(t, 1, srv.count("/schemas/routes"))
+	assert.Equal(t, 1, srv.count("/schemas/plugins/key-auth"))
+}
+
+func TestFillContentDefaultsErrors(t *testing.T) {
+	client := newFakeKongClient(t, newFakeKong(t, nil))
+
+	assert.Error(t, client.FillContentDefaults(defaultCtx, nil))
+	assert.Error(t, client.FillContentDefaults(defaultCtx, &Content{
+		Plugins: []*Plugin{{}},
+	}))
+	err := client.FillContentDefaults(defaultCtx, &Content{
+		Services: []*Service{{Name: String("s1")}},
+	})
+	assert.True(t, IsNotFoundErr(err))
+}
+
+func TestFillPluginsDefaultsBatch(t *testing.T) {
+	client := newFakeKongClient(t, newFakeKong(t, map[string]fakeResponse{
+		"/schemas/plugins/key-auth": {body: contentKeyAuthSchema},
+	}))
+
+	const count = 100
+	plugins := make([]*Plugin, 0, count+2)
+	for i := 0; i < count; i++ {
+		plugins = append(plugins, &Plugin{
+			Name:   String("key-auth"),
+			Config: Configuration{"hide_credentials": i%2 == 0},
+		})
+	}
+	plugins = append(plugins, &Plugin{Name: String("unknown")}, &Plugin{})
+
+	errs := client.FillPluginsDefaultsBatch(defaultCtx, plugins, 8)
+	require.Len(t, errs, len(plugins))
+	for i := 0; i < count; i++ {
+		require.NoError(t, errs[i])
+		p := plugins[i]
+		assert.True(t, *p.Enabled)
+		assert.Len(t, p.Protocols, 4)
+		assert.Equal(t, []interface{}{"apikey"}, p.Config["key_names"])
+		assert.Equal(t, i%2 == 0, p.Config["hide_credentials"])
+	}
+	assert.True(t, IsNotFoundErr(errs[count]))
+	assert.Error(t, errs[count+1])
+
+	stats := client.SchemaCacheStats()
+	assert.Equal(t, 1, stats.Entries)
+	assert.Equal(t, uint64(count+1), stats.Hits+stats.Misses)
+}
+
+func TestFillPluginsDefaultsNoCache(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/schemas/plugins/key-auth": {body: contentKeyAuthSchema},
+	})
+	client := newFakeKongClient(t, srv)
+
+	plugin := &Plugin{Name: String("key-auth")}
+	require.NoError(t, client.FillPluginsDefaultsBatch(defaultCtx, []*Plugin{plugin}, 1)[0])
+	assert.Equal(t, []interface{}{"apikey"}, plugin.Config["key_names"])
+	assert.Equal(t, 1, srv.count("/schemas/plugins/key-auth"))
+
+	// the schema changed server-side
+	srv.set("/schemas/plugins/key-auth", fakeResponse{
+		body: strings.Replace(contentKeyAuthSchema, `"apikey"`, `"x-api-key"`, 1),
+	})
+	plugin = &Plugin{Name: String("key-auth")}
+	require.NoError(t, client.FillPluginsDefaultsNoCache(defaultCtx, plugin))
+	assert.Equal(t, []interface{}{"x-api-key"}, plugin.Config["key_names"])
+	assert.Equal(t, 2, srv.count("/schemas/plugins/key-auth"))
+
+	// the cache holds the refreshed schema
+	plugin = &Plugin{Name: String("key-auth")}
+	require.NoError(t, client.FillPluginsDefaultsBatch(defaultCtx, []*Plugin{plugin}, 1)[0])
+	assert.Equal(t, []interface{}{"x-api-key"}, plugin.Config["key_names"])
+	assert.Equal(t, 2, srv.count("/schemas/plugins/key-auth"))
+	assert.Equal(t, 1, client.SchemaCacheStats().Entries)
+
+	assert.Error(t, client.FillPluginsDefaultsNoCache(defaultCtx, &Plugin{}))
+}
+
+func TestCheckContentCompatibility(t *testing.T) {
+	newClient := func(t *testing.T, root string) *Client {
+		return newFakeKongClient(t, newFakeKong(t, map[string]fakeResponse{"*": {body: root}}))
+	}
+	content := &Content{
+		Plugins: []*Plugin{
+			{Name: String("key-auth")},
+			{Name: String("custom-auth")},
+			{Name: String("custom-auth")},
+			{
+				Name:     String("rate-limiting"),
+				Ordering: &PluginOrdering{Before: PluginOrderingPhase{"access": []string{"key-auth"}}},
+			},
+		},
+		Routes: []*Route{
+			{Name: String("r1"), Paths: StringSlice("/foo")},
+			{Name: String("r2"), Expression: String(`http.path == "/bar"`)},
+		},
+	}
+
+	t.Run("OSS traditional", func(t *testing.T) {
+		client := newClient(t, `{
+			"version": "3.3.0",
+			"configuration": {"router_flavor": "traditional_compatible"},
+			"plugins": {"available_on_server": {"key-auth": true, "rate-limiting": true}}
+		}`)
+
+		issues, err := client.CheckContentCompatibility(defaultCtx, content)
+		require.NoError(t, err)
+		require.Len(t, issues, 3)
+		assert.Equal(t, "plugin 'custom-auth' is not available on the server", issues[0])
+		assert.Contains(t, issues[1], "plugin 'rate-limiting' uses dynamic ordering")
+		assert.Contains(t, issues[2], "route 'r2' uses an expression")
+	})
+
+	t.Run("Enterprise expressions", func(t *testing.T) {
+		client := newClient(t, `{
+			"version": "3.3.0.0-enterprise-edition",
+			"configuration": {"router_flavor": "expressions"},
+			"plugins": {"available_on_server": {
+				"key-auth": {"version": "3.3.0"},
+				"rate-limiting": {"version": "3.3.0"},
+				"custom-auth": {"version": "0.1.0"}
+			}}
+		}`)
+
+		issues, err := client.CheckContentCompatibility(defaultCtx, content)
+		require.NoError(t, err)
+		require.Len(t, issues, 1)
+		assert.Contains(t, issues[0], "route 'r1' has no expression")
+	})
+
+	t.Run("plugins not reported by the server", func(t *testing.T) {
+		client := newClient(t, `{
+			"version": "3.3.0",
+			"configuration": {},
+			"plugins": {}
+		}`)
+
+		issues, err := client.CheckContentCompatibility(defaultCtx, content)
+		require.NoError(t, err)
+		require.Len(t, issues, 2)
+		assert.Contains(t, issues[0], "plugin 'rate-limiting' uses dynamic ordering")
+		assert.Contains(t, issues[1], "route 'r2' uses an expression")
+	})
+
+	t.Run("compatible content", func(t *testing.T) {
+		client := newClient(t, `{
+			"version": "3.3.0",
+			"configuration": {},
+			"plugins": {"available_on_server": {"key-auth": true}}
+		}`)
+
+		issues, err := client.CheckContentCompatibility(defaultCtx, &Content{
+			Plugins: []*Plugin{{Name: String("key-auth")}},
+			Routes:  []*Route{{Name: String("r1"), Paths: StringSlice("/foo")}},
+		})
+		require.NoError(t, err)
+		assert.Nil(t, issues)
+	})
+}
+
+func TestContentValidateFormatVersion(t *testing.T) {
+	v2 := MustNewVersion("2.8.1")
+	v3 := MustNewVersion("3.4.0")
+
+	assert.NoError(t, (&Content{FormatVersion: String("3.0")}).ValidateFormatVersion(v3))
+	assert.NoError(t, (&Content{FormatVersion: String("1.1")}).ValidateFormatVersion(v3))
+	assert.NoError(t, (&Content{FormatVersion: String("2.1")}).ValidateFormatVersion(v2))
+
+	assert.EqualError(t, (&Content{FormatVersion: String("3.0")}).ValidateFormatVersion(v2),
+		"_format_version '3.0' requires Kong 3.0.0 or later, got 2.8.1")
+	assert.EqualError(t, (&Content{FormatVersion: String("4.0")}).ValidateFormatVersion(v3),
+		"unknown _format_version '4.0'")
+	assert.EqualError(t, (&Content{}).ValidateFormatVersion(v3), "_format_version is required")
+}
+
+func TestContentNestedCredentials(t *testing.T) {
+	const doc = `_format_version: "3.0"
+consumers:
+- username: alice
+  keyauth_credentials:
+  - key: secret
+  basicauth_credentials:
+  - username: alice
+    password: hunter2
+- username: bob
+services:
+- name: svc1
+  host: example.com
+`
+	var content Content
+	require.NoError(t, yaml.Unmarshal([]byte(doc), &content))
+	require.Len(t, content.Consumers, 2)
+	assert.Equal(t, &Consumer{Username: String("alice")}, content.Consumers[0])
+	require.Len(t, content.Services, 1)
+	assert.Equal(t, map[string]*ConsumerCredentials{
+		"alice": {
+			KeyAuths:   []*KeyAuth{{Key: String("secret")}},
+			BasicAuths: []*BasicAuth{{Username: String("alice"), Password: String("hunter2")}},
+		},
+	}, content.ConsumerCredentials)
+
+	b, err := yaml.Marshal(&content)
+	require.NoError(t, err)
+	var roundTripped Content
+	require.NoError(t, yaml.Unmarshal(b, &roundTripped))
+	assert.Equal(t, content, roundTripped)
+
+	b, err = json.Marshal(content)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"_format_version": "3.0",
+		"consumers": [
+			{
+				"username": "alice",
+				"keyauth_credentials": [{"key": "secret"}],
+				"basicauth_credentials": [{"username": "alice", "password": "hunter2"}]
+			},
+			{"username": "bob"}
+		],
+		"services": [{"name": "svc1", "host": "example.com"}]
+	}`, string(b))
+}
+
+func TestContentSelectTags(t *testing.T) {
+	content := &Content{
+		FormatVersion: String("3.0"),
+		SelectTags:    []string{"team-a"},
+		Services:      []*Service{{Name: String("svc1")}},
+	}
+	b, err := json.Marshal(content)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"_format_version": "3.0", "_info": {"select_tags": ["team-a"]},
+		"services": [{"name": "svc1"}]}`, string(b))
+
+	var decoded Content
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, content, &decoded)
+
+	b, err = json.Marshal(&Content{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, string(b))
+}
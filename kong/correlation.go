@@ -0,0 +1,23 @@
+package kong
+
+import "context"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a copy of ctx carrying the correlation ID id.
+// Every request made by a Client with the returned context, or a context
+// derived from it, sends id in the correlation header, which defaults to
+// DefaultCorrelationHeader and can be changed with WithCorrelationHeader.
+// The header is part of the requests logged in debug mode, which makes it
+// possible to find the requests of an operation in the logs of the client
+// and of Kong.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationID returns the correlation ID set on ctx with
+// WithCorrelationID, if any.
+func CorrelationID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok && id != ""
+}
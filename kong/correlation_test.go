@@ -0,0 +1,62 @@
+package kong
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithCorrelationID(t *testing.T) {
+	var received []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Header.Get("X-Request-Tag"))
+		_, _ = w.Write([]byte(`{"data": [], "next": null}`))
+	}))
+	defer srv.Close()
+
+	var logs bytes.Buffer
+	client, err := NewClientWithOptions(srv.URL,
+		WithCorrelationHeader("X-Request-Tag"), WithLogger(&logs))
+	require.NoError(t, err)
+	client.SetDebugMode(true)
+
+	ctx := WithCorrelationID(defaultCtx, "sync-42")
+	id, ok := CorrelationID(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, "sync-42", id)
+
+	req, err := client.NewRequest(http.MethodGet, "/services", nil, nil)
+	require.NoError(t, err)
+	_, err = client.Do(ctx, req, nil)
+	require.NoError(t, err)
+	assert.Empty(t, req.Header.Get("X-Request-Tag"), "request of the caller must not be modified")
+	_, _, err = client.Routes.List(ctx, nil)
+	require.NoError(t, err)
+	_, _, err = client.Routes.List(defaultCtx, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"sync-42", "sync-42", ""}, received)
+	assert.Equal(t, 2, bytes.Count(logs.Bytes(), []byte("X-Request-Tag: sync-42")))
+
+	_, ok = CorrelationID(defaultCtx)
+	assert.False(t, ok)
+}
+
+func TestDefaultCorrelationHeader(t *testing.T) {
+	var received string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Get(DefaultCorrelationHeader)
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+	client, err := NewClient(String(srv.URL), nil)
+	require.NoError(t, err)
+
+	_, err = client.Status(WithCorrelationID(defaultCtx, "abc"))
+	require.NoError(t, err)
+	assert.Equal(t, "abc", received)
+}
@@ -0,0 +1,202 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// BatchResult holds the outcome of a batch operation, one per item of the
+// batch, in the order of the items.
+type BatchResult struct {
+	Items []BatchItemResult
+}
+
+// BatchItemResult is the outcome of a single item of a batch operation.
+type BatchItemResult struct {
+	// Created is the entity created in Kong. It is nil if the item failed
+	// or already existed.
+	Created interface{}
+	// Duplicate is true if the item already existed in Kong.
+	Duplicate bool
+	// Err is the error which occurred for the item, if any.
+	Err error
+}
+
+// Errors returns the errors of the items which failed.
+func (r *BatchResult) Errors() []error {
+	var errs []error
+	for _, item := range r.Items {
+		if item.Err != nil {
+			errs = append(errs, item.Err)
+		}
+	}
+	return errs
+}
+
+// ImportCredentials creates creds, credentials of any of the types
+// *KeyAuth, *BasicAuth, *HMACAuth, *JWTAuth, *ACLGroup, *Oauth2Credential
+// and *MTLSAuth, for the Consumer identified by usernameOrID, using
+// workers goroutines to create them concurrently.
+// A credential which fails doesn't stop the others from being created: its
+// error is reported in the returned BatchResult. Credentials which already
+// exist for this Consumer with the same values, i.e. for which Kong
+// responds with a 409 and the existing credential matches, are reported as
+// duplicates rather than errors, so that an import can be run again. Other
+// conflicts, such as a key used by another Consumer, are reported as errors.
+func (s *ConsumerService) ImportCredentials(ctx context.Context,
+	usernameOrID *string, creds []interface{}, workers int,
+) (*BatchResult, error) {
+	if isEmptyString(usernameOrID) {
+		return nil, fmt.Errorf("usernameOrID cannot be nil for ImportCredentials operation")
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	res := &BatchResult{Items: make([]BatchItemResult, len(creds))}
+	var wg sync.WaitGroup
+	queue := make(chan int)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// each item is written by a single worker
+			for i := range queue {
+				created, err := s.createCredential(ctx, usernameOrID, creds[i])
+				switch {
+				case IsConflictErr(err):
+					if err := s.checkDuplicateCredential(ctx, usernameOrID, creds[i], err); err != nil {
+						res.Items[i].Err = fmt.Errorf("creating credential %d: %w", i, err)
+					} else {
+						res.Items[i].Duplicate = true
+					}
+				case err != nil:
+					res.Items[i].Err = fmt.Errorf("creating credential %d: %w", i, err)
+				default:
+					res.Items[i].Created = created
+				}
+			}
+		}()
+	}
+	for i := range creds {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	return res, nil
+}
+
+func (s *ConsumerService) createCredential(ctx context.Context,
+	usernameOrID *string, cred interface{},
+) (interface{}, error) {
+	switch c := cred.(type) {
+	case *KeyAuth:
+		return s.client.KeyAuths.Create(ctx, usernameOrID, c)
+	case *BasicAuth:
+		return s.client.BasicAuths.Create(ctx, usernameOrID, c)
+	case *HMACAuth:
+		return s.client.HMACAuths.Create(ctx, usernameOrID, c)
+	case *JWTAuth:
+		return s.client.JWTAuths.Create(ctx, usernameOrID, c)
+	case *ACLGroup:
+		return s.client.ACLs.Create(ctx, usernameOrID, c)
+	case *Oauth2Credential:
+		return s.client.Oauth2Credentials.Create(ctx, usernameOrID, c)
+	case *MTLSAuth:
+		return s.client.MTLSAuths.Create(ctx, usernameOrID, c)
+	}
+	return nil, fmt.Errorf("unsupported credential type: %T", cred)
+}
+
+// checkDuplicateCredential returns nil if cred, which Kong refused with
+// conflict, already exists for the Consumer identified by usernameOrID with
+// the same values, and an error describing the conflict otherwise.
+func (s *ConsumerService) checkDuplicateCredential(ctx context.Context,
+	usernameOrID *string, cred interface{}, conflict error,
+) error {
+	existing, ignore, err := s.existingCredential(ctx, usernameOrID, cred)
+	switch {
+	case IsNotFoundErr(err):
+		return fmt.Errorf("conflicts with a credential of another consumer: %w", conflict)
+	case err != nil:
+		return fmt.Errorf("fetching the conflicting credential: %w", err)
+	case existing == nil:
+		return conflict
+	}
+	fields, err := changedFields(cred, existing, append(ignore, planManagedFields...))
+	if err != nil {
+		return err
+	}
+	if len(fields) > 0 {
+		return fmt.Errorf("conflicts with an existing credential with a different %s: %w",
+			strings.Join(fields, ", "), conflict)
+	}
+	return nil
+}
+
+// existingCredential fetches the credential of the Consumer identified by
+// usernameOrID having the ID or the unique value of cred, along with the
+// fields which can't be compared, such as the hashed password of basic-auth
+// credentials. It returns a nil credential if cred has neither.
+func (s *ConsumerService) existingCredential(ctx context.Context,
+	usernameOrID *string, cred interface{},
+) (interface{}, []string, error) {
+	// the credential is fetched through the Consumer, so that its consumer
+	// field matches
+	ignore := []string{"consumer"}
+	switch c := cred.(type) {
+	case *KeyAuth:
+		if id := credentialLookup(c.ID, c.Key); id != nil {
+			existing, err := s.client.KeyAuths.Get(ctx, usernameOrID, id)
+			return existing, ignore, err
+		}
+	case *BasicAuth:
+		if id := credentialLookup(c.ID, c.Username); id != nil {
+			existing, err := s.client.BasicAuths.Get(ctx, usernameOrID, id)
+			return existing, append(ignore, "password"), err
+		}
+	case *HMACAuth:
+		if id := credentialLookup(c.ID, c.Username); id != nil {
+			existing, err := s.client.HMACAuths.Get(ctx, usernameOrID, id)
+			return existing, ignore, err
+		}
+	case *JWTAuth:
+		if id := credentialLookup(c.ID, c.Key); id != nil {
+			existing, err := s.client.JWTAuths.Get(ctx, usernameOrID, id)
+			return existing, ignore, err
+		}
+	case *ACLGroup:
+		if id := credentialLookup(c.ID, c.Group); id != nil {
+			existing, err := s.client.ACLs.Get(ctx, usernameOrID, id)
+			return existing, ignore, err
+		}
+	case *Oauth2Credential:
+		if id := credentialLookup(c.ID, c.ClientID); id != nil {
+			existing, err := s.client.Oauth2Credentials.Get(ctx, usernameOrID, id)
+			if err == nil && existing.HashSecret != nil && *existing.HashSecret {
+				ignore = append(ignore, "client_secret")
+			}
+			return existing, ignore, err
+		}
+	case *MTLSAuth:
+		if id := credentialLookup(c.ID, nil); id != nil {
+			existing, err := s.client.MTLSAuths.Get(ctx, usernameOrID, id)
+			return existing, ignore, err
+		}
+	}
+	return nil, nil, nil
+}
+
+// credentialLookup returns id if set, and the unique value of the
+// credential otherwise.
+func credentialLookup(id, value *string) *string {
+	if !isEmptyString(id) {
+		return id
+	}
+	if !isEmptyString(value) {
+		return value
+	}
+	return nil
+}
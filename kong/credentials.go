@@ -113,3 +113,31 @@ type ACLGroup struct {
 func (c ACLGroup) id() *string {
 	return c.ID
 }
+
+// ConsumerCredentials holds the credentials of a Consumer, per type.
+// +k8s:deepcopy-gen=true
+type ConsumerCredentials struct {
+	KeyAuths          []*KeyAuth          `json:"keyauth_credentials,omitempty" yaml:"keyauth_credentials,omitempty"`
+	BasicAuths        []*BasicAuth        `json:"basicauth_credentials,omitempty" yaml:"basicauth_credentials,omitempty"`
+	HMACAuths         []*HMACAuth         `json:"hmacauth_credentials,omitempty" yaml:"hmacauth_credentials,omitempty"`
+	JWTAuths          []*JWTAuth          `json:"jwt_secrets,omitempty" yaml:"jwt_secrets,omitempty"`
+	ACLGroups         []*ACLGroup         `json:"acls,omitempty" yaml:"acls,omitempty"`
+	Oauth2Credentials []*Oauth2Credential `json:"oauth2_credentials,omitempty" yaml:"oauth2_credentials,omitempty"`
+	// HashedSecrets lists the credentials whose secret is returned hashed
+	// by Kong, as "<credential type> <ID>": the secret can't be used to
+	// authenticate and is hashed again if the credential is recreated
+	// from it.
+	HashedSecrets []string `json:"hashed_secrets,omitempty" yaml:"hashed_secrets,omitempty"`
+}
+
+// IsEmpty returns true if c holds no credential.
+func (c *ConsumerCredentials) IsEmpty() bool {
+	return len(c.KeyAuths) == 0 && len(c.BasicAuths) == 0 && len(c.HMACAuths) == 0 &&
+		len(c.JWTAuths) == 0 && len(c.ACLGroups) == 0 && len(c.Oauth2Credentials) == 0
+}
+
+func (c *ConsumerCredentials) withoutHashedSecrets() *ConsumerCredentials {
+	res := *c
+	res.HashedSecrets = nil
+	return &res
+}
@@ -0,0 +1,113 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// AbstractDebugService handles the debug endpoints of Kong.
+type AbstractDebugService interface {
+	// GetNodeLogLevel fetches the log level of the Kong node.
+	GetNodeLogLevel(ctx context.Context) (string, error)
+	// SetNodeLogLevel changes the log level of the Kong node.
+	SetNodeLogLevel(ctx context.Context, level string) error
+	// SetClusterLogLevel changes the log level of every node of the cluster.
+	SetClusterLogLevel(ctx context.Context, level string) error
+	// SetControlPlanesLogLevel changes the log level of every control
+	// plane node of a hybrid cluster.
+	SetControlPlanesLogLevel(ctx context.Context, level string) error
+}
+
+// DebugService handles the debug endpoints of Kong.
+type DebugService service
+
+// LogLevels are the log levels supported by Kong, from the most verbose
+// to the least verbose.
+var LogLevels = []string{"debug", "info", "notice", "warn", "error", "crit", "alert", "emerg"}
+
+// minLogLevelVersion is the first Kong version supporting changing the log
+// level at runtime.
+var minLogLevelVersion = MustNewVersion("3.1.0")
+
+// GetNodeLogLevel fetches the log level of the Kong node.
+// This requires Kong 3.1.0 or later.
+func (s *DebugService) GetNodeLogLevel(ctx context.Context) (string, error) {
+	if err := s.checkLogLevelSupport(ctx); err != nil {
+		return "", err
+	}
+	req, err := s.client.NewRequest("GET", "/debug/node/log-level", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	var resp struct {
+		Message string `json:"message"`
+	}
+	_, err = s.client.Do(ctx, req, &resp)
+	if err != nil {
+		return "", err
+	}
+	// Kong reports the log level as "log level: <level>"
+	return strings.TrimPrefix(resp.Message, "log level: "), nil
+}
+
+// SetNodeLogLevel changes the log level of the Kong node.
+// level must be one of LogLevels. This requires Kong 3.1.0 or later.
+func (s *DebugService) SetNodeLogLevel(ctx context.Context, level string) error {
+	return s.setLogLevel(ctx, "/debug/node/log-level/", level)
+}
+
+// SetClusterLogLevel changes the log level of every node of the cluster.
+// level must be one of LogLevels. This requires Kong 3.1.0 or later.
+func (s *DebugService) SetClusterLogLevel(ctx context.Context, level string) error {
+	return s.setLogLevel(ctx, "/debug/cluster/log-level/", level)
+}
+
+// SetControlPlanesLogLevel changes the log level of every control plane
+// node of a hybrid cluster.
+// level must be one of LogLevels. This requires Kong 3.1.0 or later.
+func (s *DebugService) SetControlPlanesLogLevel(ctx context.Context, level string) error {
+	return s.setLogLevel(ctx, "/debug/cluster/control-planes-nodes/log-level/", level)
+}
+
+func (s *DebugService) setLogLevel(ctx context.Context, endpoint, level string) error {
+	if !isLogLevel(level) {
+		return fmt.Errorf("invalid log level '%s': must be one of [%s]",
+			level, strings.Join(LogLevels, ", "))
+	}
+	if err := s.checkLogLevelSupport(ctx); err != nil {
+		return err
+	}
+	req, err := s.client.NewRequest("PUT", endpoint+level, nil, nil)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
+
+// checkLogLevelSupport returns an error if Kong reports a version
+// predating the log level endpoints.
+func (s *DebugService) checkLogLevelSupport(ctx context.Context) error {
+	info, err := s.client.Root(ctx)
+	if err != nil {
+		return err
+	}
+	// unknown versions are let through for Kong to reject the request
+	// if it isn't supported
+	version, err := ParseSemanticVersion(VersionFromInfo(info))
+	if err == nil && version.Compare(minLogLevelVersion) < 0 {
+		return fmt.Errorf("the log level endpoints require Kong %s or later, got %s",
+			minLogLevelVersion, version)
+	}
+	return nil
+}
+
+func isLogLevel(level string) bool {
+	for _, l := range LogLevels {
+		if l == level {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDebugServer(t *testing.T, version string) *fakeKong {
+	return newFakeKong(t, map[string]fakeResponse{
+		"/":                         {body: `{"version": "` + version + `"}`},
+		"GET /debug/node/log-level": {body: `{"message": "log level: notice"}`},
+		"PUT *":                     {body: `{"message": "log level changed"}`},
+	})
+}
+
+// debugCalls returns the requests received by srv but those to the root
+// endpoint, each as its method and path.
+func debugCalls(srv *fakeKong) []string {
+	var res []string
+	for _, r := range srv.requests() {
+		if r.path != "/" {
+			res = append(res, r.method+" "+r.path)
+		}
+	}
+	return res
+}
+
+func TestDebugServiceLogLevel(t *testing.T) {
+	srv := newDebugServer(t, "3.4.1.0-enterprise-edition")
+	client := newFakeKongClient(t, srv)
+
+	level, err := client.Debug.GetNodeLogLevel(defaultCtx)
+	require.NoError(t, err)
+	assert.Equal(t, "notice", level)
+
+	require.NoError(t, client.Debug.SetNodeLogLevel(defaultCtx, "debug"))
+	require.NoError(t, client.Debug.SetClusterLogLevel(defaultCtx, "warn"))
+	require.NoError(t, client.Debug.SetControlPlanesLogLevel(defaultCtx, "error"))
+	assert.Equal(t, []string{
+		"GET /debug/node/log-level",
+		"PUT /debug/node/log-level/debug",
+		"PUT /debug/cluster/log-level/warn",
+		"PUT /debug/cluster/control-planes-nodes/log-level/error",
+	}, debugCalls(srv))
+
+	srv.reset()
+	err = client.Debug.SetNodeLogLevel(defaultCtx, "verbose")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid log level 'verbose'")
+	assert.Empty(t, debugCalls(srv))
+}
+
+func TestDebugServiceLogLevelUnsupportedVersion(t *testing.T) {
+	srv := newDebugServer(t, "3.0.1")
+	client := newFakeKongClient(t, srv)
+
+	_, err := client.Debug.GetNodeLogLevel(defaultCtx)
+	assert.EqualError(t, err, "the log level endpoints require Kong 3.1.0 or later, got 3.0.1")
+	err = client.Debug.SetNodeLogLevel(defaultCtx, "debug")
+	assert.Error(t, err)
+	assert.Empty(t, debugCalls(srv))
+}
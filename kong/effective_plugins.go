@@ -0,0 +1,167 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// EffectivePlugins returns, per plugin name, the enabled plugin which
+// Kong runs for requests matched by a Route, resolving the precedence of
+// plugins configured at different scopes: a plugin scoped to the Route
+// wins over one scoped to its Service, which wins over a global plugin.
+// A plugin scoped to both the Route and its Service wins over both.
+// Plugins scoped to a Consumer or a Consumer Group only apply to
+// authenticated requests and are ignored.
+func (c *Client) EffectivePlugins(ctx context.Context,
+	routeNameOrID *string,
+) (map[string]*Plugin, error) {
+	if isEmptyString(routeNameOrID) {
+		return nil, fmt.Errorf("routeNameOrID cannot be nil for EffectivePlugins operation")
+	}
+	route, err := c.Routes.Get(ctx, routeNameOrID)
+	if err != nil {
+		return nil, err
+	}
+	var serviceID *string
+	if route.Service != nil {
+		serviceID = route.Service.ID
+	}
+
+	plugins, err := c.Plugins.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]*Plugin)
+	precedences := make(map[string]int)
+	for _, p := range plugins {
+		if p.Name == nil || (p.Enabled != nil && !*p.Enabled) {
+			continue
+		}
+		precedence, ok := pluginPrecedence(p, route.ID, serviceID)
+		if !ok {
+			continue
+		}
+		if cur, found := precedences[*p.Name]; !found || precedence > cur {
+			res[*p.Name] = p
+			precedences[*p.Name] = precedence
+		}
+	}
+	return res, nil
+}
+
+// pluginPrecedence returns the precedence of plugin for requests matched
+// by the Route routeID of the Service serviceID, the higher the more
+// specific. It returns false if plugin doesn't apply to these requests.
+func pluginPrecedence(plugin *Plugin, routeID, serviceID *string) (int, bool) {
+	if plugin.Consumer != nil || plugin.ConsumerGroup != nil {
+		return 0, false
+	}
+	precedence := 0
+	if plugin.Route != nil {
+		if !equalIDs(plugin.Route.ID, routeID) {
+			return 0, false
+		}
+		precedence += 2
+	}
+	if plugin.Service != nil {
+		if !equalIDs(plugin.Service.ID, serviceID) {
+			return 0, false
+		}
+		precedence++
+	}
+	return precedence, true
+}
+
+func equalIDs(a, b *string) bool {
+	return a != nil && b != nil && *a == *b
+}
+
+// EffectiveResponseHeaders returns the response headers which the plugins
+// applying to a Route, as returned by EffectivePlugins, add to or remove
+// from the responses to requests matched by the Route.
+// Headers added by the cors plugin are reported first, then the headers
+// removed, added and appended by the response-transformer plugin, the
+// way Kong runs them. As Kong only runs the most specific instance of a
+// plugin, a response-transformer scoped to the Route replaces one scoped
+// to its Service.
+// Headers are keyed by their canonical name. Removed headers are mapped
+// to an empty slice. Headers added by response-transformer are only
+// added by Kong if the upstream response doesn't have them already.
+func (c *Client) EffectiveResponseHeaders(ctx context.Context,
+	routeNameOrID *string,
+) (map[string][]string, error) {
+	plugins, err := c.EffectivePlugins(ctx, routeNameOrID)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string][]string)
+	if cors, ok := plugins["cors"]; ok {
+		addCORSHeaders(res, cors.Config)
+	}
+	if transformer, ok := plugins["response-transformer"]; ok {
+		for _, name := range configStrings(transformer.Config, "remove", "headers") {
+			res[http.CanonicalHeaderKey(name)] = []string{}
+		}
+		for _, header := range configStrings(transformer.Config, "add", "headers") {
+			name, value := splitHeader(header)
+			if len(res[name]) == 0 {
+				res[name] = []string{value}
+			}
+		}
+		for _, header := range configStrings(transformer.Config, "append", "headers") {
+			name, value := splitHeader(header)
+			res[name] = append(res[name], value)
+		}
+	}
+	return res, nil
+}
+
+// addCORSHeaders adds the headers set by the cors plugin on responses to
+// requests which aren't preflight requests. Access-Control-Allow-Origin
+// lists the configured origins: Kong sends back the one matching the
+// origin of the request.
+func addCORSHeaders(headers map[string][]string, config Configuration) {
+	origins := configStrings(config, "origins")
+	if len(origins) == 0 {
+		origins = []string{"*"}
+	}
+	headers["Access-Control-Allow-Origin"] = origins
+	if credentials, ok := config["credentials"].(bool); ok && credentials {
+		headers["Access-Control-Allow-Credentials"] = []string{"true"}
+	}
+	if exposed := configStrings(config, "exposed_headers"); len(exposed) > 0 {
+		headers["Access-Control-Expose-Headers"] = []string{strings.Join(exposed, ",")}
+	}
+}
+
+// configStrings returns the strings of the array at path in config.
+func configStrings(config Configuration, path ...string) []string {
+	var v interface{} = map[string]interface{}(config)
+	for _, key := range path {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	var res []string
+	switch values := v.(type) {
+	case []interface{}:
+		for _, value := range values {
+			if s, ok := value.(string); ok {
+				res = append(res, s)
+			}
+		}
+	case []string:
+		res = values
+	}
+	return res
+}
+
+// splitHeader splits a "name:value" header of response-transformer.
+func splitHeader(header string) (string, string) {
+	name, value, _ := strings.Cut(header, ":")
+	return http.CanonicalHeaderKey(strings.TrimSpace(name)), value
+}
@@ -0,0 +1,91 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEffectivePlugins(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/routes/r1":     {body: `{"id": "r1", "name": "route1", "service": {"id": "s1"}}`},
+		"/routes/route1": {body: `{"id": "r1", "name": "route1", "service": {"id": "s1"}}`},
+		"/plugins": fakeList(`[
+			{"id": "p1", "name": "rate-limiting", "service": {"id": "s1"}},
+			{"id": "p2", "name": "rate-limiting", "route": {"id": "r1"}},
+			{"id": "p3", "name": "rate-limiting"},
+			{"id": "p4", "name": "cors"},
+			{"id": "p5", "name": "cors", "service": {"id": "s2"}},
+			{"id": "p6", "name": "key-auth", "service": {"id": "s1"}},
+			{"id": "p7", "name": "key-auth", "route": {"id": "r1"}, "enabled": false},
+			{"id": "p8", "name": "acl", "route": {"id": "r1"}, "consumer": {"id": "c1"}}
+		]`),
+	})
+	client := newFakeKongClient(t, srv)
+
+	plugins, err := client.EffectivePlugins(defaultCtx, String("route1"))
+	require.NoError(t, err)
+	ids := make(map[string]string)
+	for name, p := range plugins {
+		ids[name] = *p.ID
+	}
+	assert.Equal(t, map[string]string{
+		"rate-limiting": "p2",
+		"cors":          "p4",
+		"key-auth":      "p6",
+	}, ids)
+
+	_, err = client.EffectivePlugins(defaultCtx, String("unknown"))
+	assert.True(t, IsNotFoundErr(err))
+	_, err = client.EffectivePlugins(defaultCtx, nil)
+	assert.Error(t, err)
+}
+
+func TestEffectiveResponseHeaders(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/routes/route1": {body: `{"id": "r1", "name": "route1", "service": {"id": "s1"}}`},
+		"/routes/route2": {body: `{"id": "r2", "name": "route2", "service": {"id": "s1"}}`},
+		"/plugins": fakeList(`[
+			{"id": "p1", "name": "response-transformer", "service": {"id": "s1"}, "config": {
+				"remove": {"headers": ["server"], "json": []},
+				"add": {"headers": ["x-service:svc1"], "json": []},
+				"append": {"headers": [], "json": []}
+			}},
+			{"id": "p2", "name": "response-transformer", "route": {"id": "r1"}, "config": {
+				"remove": {"headers": ["x-powered-by", "access-control-allow-origin"], "json": []},
+				"add": {"headers": ["x-route:route1", "x-cache:miss"], "json": []},
+				"append": {"headers": ["x-cache:route1", "x-route:extra"], "json": []}
+			}},
+			{"id": "p3", "name": "cors", "config": {
+				"origins": ["https://a.example.com", "https://b.example.com"],
+				"credentials": true,
+				"exposed_headers": null
+			}}
+		]`),
+	})
+	client := newFakeKongClient(t, srv)
+
+	// the response-transformer of the route replaces the one of the service
+	headers, err := client.EffectiveResponseHeaders(defaultCtx, String("route1"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"Access-Control-Allow-Origin":      {},
+		"Access-Control-Allow-Credentials": {"true"},
+		"X-Powered-By":                     {},
+		"X-Route":                          {"route1", "extra"},
+		"X-Cache":                          {"miss", "route1"},
+	}, headers)
+
+	headers, err = client.EffectiveResponseHeaders(defaultCtx, String("route2"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"Access-Control-Allow-Origin":      {"https://a.example.com", "https://b.example.com"},
+		"Access-Control-Allow-Credentials": {"true"},
+		"Server":                           {},
+		"X-Service":                        {"svc1"},
+	}, headers)
+
+	_, err = client.EffectiveResponseHeaders(defaultCtx, String("unknown"))
+	assert.True(t, IsNotFoundErr(err))
+}
@@ -0,0 +1,77 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+)
+
+// enterprisePlugins lists the plugins bundled with Kong Gateway Enterprise
+// only.
+var enterprisePlugins = map[string]bool{
+	"application-registration":       true,
+	"canary":                         true,
+	"degraphql":                      true,
+	"exit-transformer":               true,
+	"forward-proxy":                  true,
+	"graphql-proxy-cache-advanced":   true,
+	"graphql-rate-limiting-advanced": true,
+	"jq":                             true,
+	"jwe-decrypt":                    true,
+	"jwt-signer":                     true,
+	"kafka-log":                      true,
+	"kafka-upstream":                 true,
+	"key-auth-enc":                   true,
+	"konnect-application-auth":       true,
+	"ldap-auth-advanced":             true,
+	"mocking":                        true,
+	"mtls-auth":                      true,
+	"oas-validation":                 true,
+	"oauth2-introspection":           true,
+	"opa":                            true,
+	"openid-connect":                 true,
+	"proxy-cache-advanced":           true,
+	"rate-limiting-advanced":         true,
+	"request-transformer-advanced":   true,
+	"request-validator":              true,
+	"response-transformer-advanced":  true,
+	"route-by-header":                true,
+	"route-transformer-advanced":     true,
+	"saml":                           true,
+	"statsd-advanced":                true,
+	"tls-handshake-modifier":         true,
+	"tls-metadata-headers":           true,
+	"upstream-timeout":               true,
+	"vault-auth":                     true,
+	"websocket-size-limit":           true,
+	"websocket-validator":            true,
+	"xml-threat-protection":          true,
+}
+
+// IsEnterprisePlugin returns true if the plugin pluginName is only
+// available in Kong Gateway Enterprise, so that it can't be configured on
+// Kong Gateway OSS.
+// Plugins bundled with Kong Gateway Enterprise only are known by the
+// client. A plugin available on an OSS server, as reported by the
+// plugins.available_on_server property of the root endpoint, is never
+// enterprise-only.
+func (c *Client) IsEnterprisePlugin(ctx context.Context, pluginName *string) (bool, error) {
+	if isEmptyString(pluginName) {
+		return false, fmt.Errorf("pluginName cannot be empty")
+	}
+	if !enterprisePlugins[*pluginName] {
+		return false, nil
+	}
+	info, err := c.Root(ctx)
+	if err != nil {
+		return false, err
+	}
+	version, err := ParseSemanticVersion(VersionFromInfo(info))
+	if err != nil {
+		return false, err
+	}
+	if version.IsKongGatewayEnterprise() {
+		return true, nil
+	}
+	_, availableOnOSS := availablePluginsFromInfo(info)[*pluginName]
+	return !availableOnOSS, nil
+}
@@ -0,0 +1,78 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	ossRootResponse = `{
+	"version": "3.4.0",
+	"plugins": {
+		"available_on_server": {
+			"key-auth": {"version": "3.4.0", "priority": 1250},
+			"rate-limiting": {"version": "3.4.0", "priority": 910},
+			"degraphql": {"version": "0.1.0", "priority": 1500}
+		},
+		"enabled_in_cluster": []
+	}
+}`
+	enterpriseRootResponse = `{
+	"version": "3.4.1.0-enterprise-edition",
+	"plugins": {
+		"available_on_server": {
+			"key-auth": {"version": "3.4.1", "priority": 1250},
+			"rate-limiting": {"version": "3.4.1", "priority": 910},
+			"rate-limiting-advanced": {"version": "3.4.1", "priority": 910},
+			"openid-connect": {"version": "3.4.1", "priority": 1050}
+		},
+		"enabled_in_cluster": []
+	}
+}`
+)
+
+func TestIsEnterprisePlugin(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		root     string
+		expected map[string]bool
+	}{
+		{
+			name: "oss",
+			root: ossRootResponse,
+			expected: map[string]bool{
+				"rate-limiting-advanced": true,
+				"openid-connect":         true,
+				"key-auth":               false,
+				"my-custom-plugin":       false,
+				// installed on the OSS server
+				"degraphql": false,
+			},
+		},
+		{
+			name: "enterprise",
+			root: enterpriseRootResponse,
+			expected: map[string]bool{
+				"rate-limiting-advanced": true,
+				"openid-connect":         true,
+				"degraphql":              true,
+				"key-auth":               false,
+				"my-custom-plugin":       false,
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newFakeKongClient(t, newFakeKong(t, map[string]fakeResponse{"*": {body: tc.root}}))
+
+			for name, expected := range tc.expected {
+				isEnterprise, err := client.IsEnterprisePlugin(defaultCtx, String(name))
+				require.NoError(t, err)
+				assert.Equal(t, expected, isEnterprise, name)
+			}
+			_, err := client.IsEnterprisePlugin(defaultCtx, nil)
+			assert.Error(t, err)
+		})
+	}
+}
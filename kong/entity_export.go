@@ -0,0 +1,524 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const exportFormatVersion = "3.0"
+
+// ImportOpt configures ImportEntity.
+type ImportOpt struct {
+	// Tags are added to every imported entity, e.g. to keep track of
+	// entities promoted from another environment.
+	Tags []*string
+}
+
+// ExportEntity fetches a single entity along with the entities it owns
+// and encodes them as portable JSON, in the Content format.
+// Supported entity types are:
+//   - "services": the Service, its Routes and the Plugins attached to the
+//     Service or its Routes
+//   - "upstreams": the Upstream and its Targets
+//   - "consumers": the Consumer and its credentials, nested under it
+//   - "consumer_groups": the Consumer Group, its member Consumers, referred
+//     to by username or ID, and its plugin overrides
+//
+// Server-managed fields (IDs and timestamps) are stripped. Children refer
+// to their parent by name, or by ID if the parent has no name, in which
+// case the parent keeps its ID.
+// Plugins scoped to a Consumer are not exported since Consumers are not
+// part of the export of Services.
+// Secrets which Kong stores hashed, the passwords of basic-auth credentials
+// and the client secrets of oauth2 credentials with hash_secret enabled,
+// are left out: a hashed secret can't be imported since Kong would hash it
+// again, and ImportEntity refuses credentials missing their secret.
+func (c *Client) ExportEntity(ctx context.Context, entityType, id string) ([]byte, error) {
+	if id == "" {
+		return nil, fmt.Errorf("id cannot be empty for Export operation")
+	}
+
+	content := &Content{FormatVersion: String(exportFormatVersion)}
+	var err error
+	switch entityType {
+	case "services":
+		err = c.exportService(ctx, id, content)
+	case "upstreams":
+		err = c.exportUpstream(ctx, id, content)
+	case "consumers":
+		err = c.exportConsumer(ctx, id, content)
+	case "consumer_groups":
+		err = c.exportConsumerGroup(ctx, id, content)
+	default:
+		return nil, fmt.Errorf("unsupported entity type for export: '%s'", entityType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(content, "", "  ")
+}
+
+// exportRef returns the reference used by exported children to refer to
+// their parent: its name if set, its ID otherwise.
+func exportRef(name, id *string) (*string, *string) {
+	if name != nil {
+		return name, nil
+	}
+	return nil, id
+}
+
+func (c *Client) exportService(ctx context.Context, nameOrID string, content *Content) error {
+	service, err := c.Services.Get(ctx, &nameOrID)
+	if err != nil {
+		return err
+	}
+	var routes, data []*Route
+	opt := &ListOpt{Size: pageSize}
+	for opt != nil {
+		data, opt, err = c.Routes.ListForService(ctx, service.ID, opt)
+		if err != nil {
+			return err
+		}
+		routes = append(routes, data...)
+	}
+
+	serviceName, serviceID := exportRef(service.Name, service.ID)
+	plugins, err := c.Plugins.ListAllForService(ctx, service.ID)
+	if err != nil {
+		return err
+	}
+	for _, plugin := range plugins {
+		plugin.Service = &Service{Name: serviceName, ID: serviceID}
+		plugin.Route = nil
+	}
+
+	for _, route := range routes {
+		routePlugins, err := c.Plugins.ListAllForRoute(ctx, route.ID)
+		if err != nil {
+			return err
+		}
+		routeName, routeID := exportRef(route.Name, route.ID)
+		for _, plugin := range routePlugins {
+			plugin.Route = &Route{Name: routeName, ID: routeID}
+			plugin.Service = nil
+		}
+		plugins = append(plugins, routePlugins...)
+
+		route.ID, route.CreatedAt, route.UpdatedAt = routeID, nil, nil
+		route.Service = &Service{Name: serviceName, ID: serviceID}
+		content.Routes = append(content.Routes, route)
+	}
+
+	for _, plugin := range plugins {
+		if plugin.Consumer != nil || plugin.ConsumerGroup != nil {
+			continue
+		}
+		plugin.ID, plugin.CreatedAt = nil, nil
+		content.Plugins = append(content.Plugins, plugin)
+	}
+
+	service.ID, service.CreatedAt, service.UpdatedAt = serviceID, nil, nil
+	content.Services = append(content.Services, service)
+	return nil
+}
+
+func (c *Client) exportUpstream(ctx context.Context, nameOrID string, content *Content) error {
+	upstream, err := c.Upstreams.Get(ctx, &nameOrID)
+	if err != nil {
+		return err
+	}
+	targets, err := c.Targets.ListAll(ctx, upstream.ID)
+	if err != nil {
+		return err
+	}
+
+	upstreamName, upstreamID := exportRef(upstream.Name, upstream.ID)
+	for _, target := range targets {
+		target.ID, target.CreatedAt = nil, nil
+		target.Upstream = &Upstream{Name: upstreamName, ID: upstreamID}
+		content.Targets = append(content.Targets, target)
+	}
+
+	upstream.ID, upstream.CreatedAt = upstreamID, nil
+	content.Upstreams = append(content.Upstreams, upstream)
+	return nil
+}
+
+func (c *Client) exportConsumer(ctx context.Context, usernameOrID string, content *Content) error {
+	consumer, err := c.Consumers.Get(ctx, &usernameOrID)
+	if err != nil {
+		return err
+	}
+	creds, err := c.Consumers.ExportCredentials(ctx, consumer.ID)
+	if err != nil {
+		return err
+	}
+	hashed := make(map[string]bool, len(creds.HashedSecrets))
+	for _, secret := range creds.HashedSecrets {
+		hashed[secret] = true
+	}
+	creds.HashedSecrets = nil
+	for _, cred := range creds.KeyAuths {
+		cred.ID, cred.CreatedAt, cred.Consumer = nil, nil, nil
+	}
+	for _, cred := range creds.BasicAuths {
+		if cred.ID != nil && hashed["basic-auth "+*cred.ID] {
+			cred.Password = nil
+		}
+		cred.ID, cred.CreatedAt, cred.Consumer = nil, nil, nil
+	}
+	for _, cred := range creds.HMACAuths {
+		cred.ID, cred.CreatedAt, cred.Consumer = nil, nil, nil
+	}
+	for _, cred := range creds.JWTAuths {
+		cred.ID, cred.CreatedAt, cred.Consumer = nil, nil, nil
+	}
+	for _, cred := range creds.ACLGroups {
+		cred.ID, cred.CreatedAt, cred.Consumer = nil, nil, nil
+	}
+	for _, cred := range creds.Oauth2Credentials {
+		if cred.ID != nil && hashed["oauth2 "+*cred.ID] {
+			cred.ClientSecret = nil
+		}
+		cred.ID, cred.CreatedAt, cred.Consumer = nil, nil, nil
+	}
+
+	_, consumer.ID = exportRef(consumer.Username, consumer.ID)
+	consumer.CreatedAt = nil
+	content.Consumers = append(content.Consumers, consumer)
+	if !creds.IsEmpty() {
+		content.ConsumerCredentials = map[string]*ConsumerCredentials{consumer.FriendlyName(): creds}
+	}
+	return nil
+}
+
+func (c *Client) exportConsumerGroup(ctx context.Context, nameOrID string, content *Content) error {
+	group, err := c.ConsumerGroups.Get(ctx, &nameOrID)
+	if err != nil {
+		return err
+	}
+	if group.ConsumerGroup == nil {
+		return fmt.Errorf("consumer group '%s' not returned by Kong", nameOrID)
+	}
+	for i, consumer := range group.Consumers {
+		username, consumerID := exportRef(consumer.Username, consumer.ID)
+		group.Consumers[i] = &Consumer{Username: username, ID: consumerID}
+	}
+	for _, plugin := range group.Plugins {
+		plugin.ID, plugin.CreatedAt, plugin.ConsumerGroup = nil, nil, nil
+	}
+
+	_, group.ConsumerGroup.ID = exportRef(group.ConsumerGroup.Name, group.ConsumerGroup.ID)
+	group.ConsumerGroup.CreatedAt = nil
+	content.ConsumerGroups = append(content.ConsumerGroups, group)
+	return nil
+}
+
+// ImportEntity creates the entities of a document produced by
+// ExportEntity. References between entities are re-resolved against the
+// IDs of the newly created entities, so that a document can be imported
+// in a different Kong cluster or workspace. The credentials of a Consumer
+// are created after the Consumer; an error is returned before creating
+// any entity if a basic-auth credential has no password, or an oauth2
+// credential with hash_secret enabled has no client secret, as exported
+// by ExportEntity when Kong only returned their hash. Consumer Groups are
+// created after the Consumers, followed by their members and their plugin
+// overrides, whose defaults are filled as ConsumerGroupService.SetPlugin
+// does. Plugins
+// scoped to an imported Consumer or Consumer Group refer to its new ID.
+// The created entities are returned. If an error occurs, the entities
+// created until then are not rolled back.
+func (c *Client) ImportEntity(ctx context.Context, data []byte, opt *ImportOpt) (*Content, error) {
+	var content Content
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("decoding entity: %w", err)
+	}
+	var tags []*string
+	if opt != nil {
+		tags = opt.Tags
+	}
+
+	// references map the name or exported ID of a parent to its new ID
+	services := make(map[string]*string)
+	routes := make(map[string]*string)
+	upstreams := make(map[string]*string)
+	consumers := make(map[string]*string)
+	resolve := func(refs map[string]*string, entity string, name, id *string) (*string, error) {
+		ref := name
+		if ref == nil {
+			ref = id
+		}
+		if ref == nil {
+			return nil, fmt.Errorf("%s reference cannot be empty", entity)
+		}
+		newID, ok := refs[*ref]
+		if !ok {
+			return nil, fmt.Errorf("%s '%s' is not part of the imported entities", entity, *ref)
+		}
+		return newID, nil
+	}
+	record := func(refs map[string]*string, name, id, newID *string) {
+		if name != nil {
+			refs[*name] = newID
+		}
+		if id != nil {
+			refs[*id] = newID
+		}
+	}
+
+	if err := checkImportedSecrets(content.ConsumerCredentials); err != nil {
+		return nil, err
+	}
+
+	var res Content
+	for _, consumer := range content.Consumers {
+		key := consumer.FriendlyName()
+		username, id := consumer.Username, consumer.ID
+		consumer.ID = nil
+		consumer.Tags = append(consumer.Tags, tags...)
+		created, err := c.Consumers.Create(ctx, consumer)
+		if err != nil {
+			return nil, err
+		}
+		record(consumers, username, id, created.ID)
+		res.Consumers = append(res.Consumers, created)
+		creds, ok := content.ConsumerCredentials[key]
+		if !ok {
+			continue
+		}
+		createdCreds, err := c.importCredentials(ctx, created.ID, creds, tags)
+		if err != nil {
+			return nil, fmt.Errorf("importing credentials of consumer '%s': %w", key, err)
+		}
+		if res.ConsumerCredentials == nil {
+			res.ConsumerCredentials = make(map[string]*ConsumerCredentials)
+		}
+		res.ConsumerCredentials[created.FriendlyName()] = createdCreds
+	}
+	consumerGroups := make(map[string]*string)
+	for _, group := range content.ConsumerGroups {
+		var name, id *string
+		if group != nil && group.ConsumerGroup != nil {
+			name, id = group.ConsumerGroup.Name, group.ConsumerGroup.ID
+		}
+		created, err := c.importConsumerGroup(ctx, group, consumers, tags)
+		if err != nil {
+			return nil, err
+		}
+		record(consumerGroups, name, id, created.ConsumerGroup.ID)
+		res.ConsumerGroups = append(res.ConsumerGroups, created)
+	}
+	for _, service := range content.Services {
+		name, id := service.Name, service.ID
+		service.ID = nil
+		service.Tags = append(service.Tags, tags...)
+		created, err := c.Services.Create(ctx, service)
+		if err != nil {
+			return nil, err
+		}
+		record(services, name, id, created.ID)
+		res.Services = append(res.Services, created)
+	}
+	for _, route := range content.Routes {
+		if route.Service != nil {
+			serviceID, err := resolve(services, "service", route.Service.Name, route.Service.ID)
+			if err != nil {
+				return nil, err
+			}
+			route.Service = &Service{ID: serviceID}
+		}
+		name, id := route.Name, route.ID
+		route.ID = nil
+		route.Tags = append(route.Tags, tags...)
+		created, err := c.Routes.Create(ctx, route)
+		if err != nil {
+			return nil, err
+		}
+		record(routes, name, id, created.ID)
+		res.Routes = append(res.Routes, created)
+	}
+	for _, upstream := range content.Upstreams {
+		name, id := upstream.Name, upstream.ID
+		upstream.ID = nil
+		upstream.Tags = append(upstream.Tags, tags...)
+		created, err := c.Upstreams.Create(ctx, upstream)
+		if err != nil {
+			return nil, err
+		}
+		record(upstreams, name, id, created.ID)
+		res.Upstreams = append(res.Upstreams, created)
+	}
+	for _, target := range content.Targets {
+		if target.Upstream == nil {
+			return nil, fmt.Errorf("target '%s' has no upstream", target.FriendlyName())
+		}
+		upstreamID, err := resolve(upstreams, "upstream", target.Upstream.Name, target.Upstream.ID)
+		if err != nil {
+			return nil, err
+		}
+		target.ID, target.Upstream = nil, nil
+		target.Tags = append(target.Tags, tags...)
+		created, err := c.Targets.Create(ctx, upstreamID, target)
+		if err != nil {
+			return nil, err
+		}
+		res.Targets = append(res.Targets, created)
+	}
+	for _, plugin := range content.Plugins {
+		if plugin.Service != nil {
+			serviceID, err := resolve(services, "service", plugin.Service.Name, plugin.Service.ID)
+			if err != nil {
+				return nil, err
+			}
+			plugin.Service = &Service{ID: serviceID}
+		}
+		if plugin.Route != nil {
+			routeID, err := resolve(routes, "route", plugin.Route.Name, plugin.Route.ID)
+			if err != nil {
+				return nil, err
+			}
+			plugin.Route = &Route{ID: routeID}
+		}
+		// consumers and consumer groups which aren't part of the imported
+		// entities must exist already
+		if plugin.Consumer != nil {
+			ref := planNameOrID(plugin.Consumer.Username, plugin.Consumer.ID)
+			if consumerID, ok := consumers[ref]; ok {
+				plugin.Consumer = &Consumer{ID: consumerID}
+			}
+		}
+		if plugin.ConsumerGroup != nil {
+			ref := planNameOrID(plugin.ConsumerGroup.Name, plugin.ConsumerGroup.ID)
+			if groupID, ok := consumerGroups[ref]; ok {
+				plugin.ConsumerGroup = &ConsumerGroup{ID: groupID}
+			}
+		}
+		plugin.ID = nil
+		plugin.Tags = append(plugin.Tags, tags...)
+		created, err := c.Plugins.Create(ctx, plugin)
+		if err != nil {
+			return nil, err
+		}
+		res.Plugins = append(res.Plugins, created)
+	}
+	return &res, nil
+}
+
+// importConsumerGroup creates group, then adds its members and sets its
+// plugin overrides. Members which are part of the imported Consumers are
+// referred to by their new ID, consumers maps their username or exported
+// ID to it; other members must exist already.
+func (c *Client) importConsumerGroup(ctx context.Context, group *ConsumerGroupObject,
+	consumers map[string]*string, tags []*string,
+) (*ConsumerGroupObject, error) {
+	if group == nil || group.ConsumerGroup == nil {
+		return nil, fmt.Errorf("consumer group cannot be empty")
+	}
+	group.ConsumerGroup.ID = nil
+	group.ConsumerGroup.Tags = append(group.ConsumerGroup.Tags, tags...)
+	for i, member := range group.Consumers {
+		ref := member.Username
+		if ref == nil {
+			ref = member.ID
+		}
+		if ref == nil {
+			continue
+		}
+		if newID, ok := consumers[*ref]; ok {
+			group.Consumers[i] = &Consumer{ID: newID, Username: member.Username}
+		}
+	}
+	return c.createConsumerGroupObject(ctx, group)
+}
+
+// checkImportedSecrets returns an error if credentials hold credentials
+// missing the secret which Kong hashes, so that they can't be created
+// with a secret nobody knows.
+func checkImportedSecrets(credentials map[string]*ConsumerCredentials) error {
+	var missing []string
+	for consumer, creds := range credentials {
+		for _, cred := range creds.BasicAuths {
+			if isEmptyString(cred.Password) {
+				missing = append(missing, fmt.Sprintf("basic-auth credential '%s' of consumer '%s'",
+					planNameOrID(cred.Username, nil), consumer))
+			}
+		}
+		for _, cred := range creds.Oauth2Credentials {
+			if cred.HashSecret != nil && *cred.HashSecret && isEmptyString(cred.ClientSecret) {
+				missing = append(missing, fmt.Sprintf("oauth2 credential '%s' of consumer '%s'",
+					planNameOrID(cred.ClientID, cred.Name), consumer))
+			}
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return fmt.Errorf("secrets hashed by Kong can't be imported, set them for: %s",
+		strings.Join(missing, ", "))
+}
+
+// importCredentials creates creds for the Consumer consumerID.
+func (c *Client) importCredentials(ctx context.Context,
+	consumerID *string, creds *ConsumerCredentials, tags []*string,
+) (*ConsumerCredentials, error) {
+	var res ConsumerCredentials
+	for _, cred := range creds.KeyAuths {
+		cred.ID, cred.Consumer = nil, nil
+		cred.Tags = append(cred.Tags, tags...)
+		created, err := c.KeyAuths.Create(ctx, consumerID, cred)
+		if err != nil {
+			return nil, err
+		}
+		res.KeyAuths = append(res.KeyAuths, created)
+	}
+	for _, cred := range creds.BasicAuths {
+		cred.ID, cred.Consumer = nil, nil
+		cred.Tags = append(cred.Tags, tags...)
+		created, err := c.BasicAuths.Create(ctx, consumerID, cred)
+		if err != nil {
+			return nil, err
+		}
+		res.BasicAuths = append(res.BasicAuths, created)
+	}
+	for _, cred := range creds.HMACAuths {
+		cred.ID, cred.Consumer = nil, nil
+		cred.Tags = append(cred.Tags, tags...)
+		created, err := c.HMACAuths.Create(ctx, consumerID, cred)
+		if err != nil {
+			return nil, err
+		}
+		res.HMACAuths = append(res.HMACAuths, created)
+	}
+	for _, cred := range creds.JWTAuths {
+		cred.ID, cred.Consumer = nil, nil
+		cred.Tags = append(cred.Tags, tags...)
+		created, err := c.JWTAuths.Create(ctx, consumerID, cred)
+		if err != nil {
+			return nil, err
+		}
+		res.JWTAuths = append(res.JWTAuths, created)
+	}
+	for _, cred := range creds.ACLGroups {
+		cred.ID, cred.Consumer = nil, nil
+		cred.Tags = append(cred.Tags, tags...)
+		created, err := c.ACLs.Create(ctx, consumerID, cred)
+		if err != nil {
+			return nil, err
+		}
+		res.ACLGroups = append(res.ACLGroups, created)
+	}
+	for _, cred := range creds.Oauth2Credentials {
+		cred.ID, cred.Consumer = nil, nil
+		cred.Tags = append(cred.Tags, tags...)
+		created, err := c.Oauth2Credentials.Create(ctx, consumerID, cred)
+		if err != nil {
+			return nil, err
+		}
+		res.Oauth2Credentials = append(res.Oauth2Credentials, created)
+	}
+	return &res, nil
+}
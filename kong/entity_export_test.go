@@ -0,0 +1,406 @@
+package kong
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newImportServer returns a fake Kong which creates entities using
+// generated IDs and records the request bodies per endpoint.
+func newImportServer() (*httptest.Server, map[string][]map[string]interface{}) {
+	created := make(map[string][]map[string]interface{})
+	var count int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var body map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "invalid body"}`))
+			return
+		}
+		created[r.URL.Path] = append(created[r.URL.Path], body)
+
+		count++
+		resp := map[string]interface{}{"id": fmt.Sprintf("new-%d", count)}
+		for k, v := range body {
+			resp[k] = v
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	return srv, created
+}
+
+func TestExportImportService(t *testing.T) {
+	sourceClient := newFakeKongClient(t, newFakeKong(t, map[string]fakeResponse{
+		"/services/svc1": {body: `{"id": "s1", "name": "svc1", "host": "example.com",
+			"created_at": 1, "updated_at": 2}`},
+		"/services/s1/routes": fakeList(`[
+			{"id": "r1", "name": "route1", "paths": ["/foo"], "service": {"id": "s1"}, "created_at": 1},
+			{"id": "r2", "paths": ["/bar"], "service": {"id": "s1"}, "created_at": 1}
+		]`),
+		"/services/s1/plugins": fakeList(`[
+			{"id": "p1", "name": "key-auth", "service": {"id": "s1"}, "created_at": 1},
+			{"id": "p2", "name": "rate-limiting", "service": {"id": "s1"}, "consumer": {"id": "c1"}}
+		]`),
+		"/routes/r1/plugins": fakeList(`[{"id": "p3", "name": "cors", "route": {"id": "r1"}}]`),
+		"/routes/r2/plugins": fakeList(`[{"id": "p4", "name": "acl", "route": {"id": "r2"}}]`),
+	}))
+	data, err := sourceClient.ExportEntity(defaultCtx, "services", "svc1")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "created_at")
+	assert.NotContains(t, string(data), "updated_at")
+	assert.NotContains(t, string(data), `"s1"`)
+	assert.NotContains(t, string(data), `"r1"`)
+	assert.Contains(t, string(data), `"r2"`, "unnamed routes keep their ID")
+	assert.NotContains(t, string(data), "rate-limiting", "consumer plugins are not exported")
+
+	target, created := newImportServer()
+	defer target.Close()
+	targetClient, err := NewClient(String(target.URL), nil)
+	require.NoError(t, err)
+	res, err := targetClient.ImportEntity(defaultCtx, data, &ImportOpt{Tags: StringSlice("promoted")})
+	require.NoError(t, err)
+	require.Len(t, res.Services, 1)
+	require.Len(t, res.Routes, 2)
+	require.Len(t, res.Plugins, 3)
+
+	require.Len(t, created["/services"], 1)
+	assert.Equal(t, "svc1", created["/services"][0]["name"])
+	assert.Equal(t, "example.com", created["/services"][0]["host"])
+	assert.NotContains(t, created["/services"][0], "id")
+	assert.Equal(t, []interface{}{"promoted"}, created["/services"][0]["tags"])
+
+	serviceID := *res.Services[0].ID
+	require.Len(t, created["/routes"], 2)
+	for _, route := range created["/routes"] {
+		assert.NotContains(t, route, "id")
+		assert.Equal(t, map[string]interface{}{"id": serviceID}, route["service"])
+	}
+
+	require.Len(t, created["/plugins"], 3)
+	assert.Equal(t, map[string]interface{}{"id": serviceID}, created["/plugins"][0]["service"])
+	assert.Equal(t, map[string]interface{}{"id": *res.Routes[0].ID}, created["/plugins"][1]["route"])
+	assert.Equal(t, map[string]interface{}{"id": *res.Routes[1].ID}, created["/plugins"][2]["route"])
+	for _, plugin := range created["/plugins"] {
+		assert.NotContains(t, plugin, "id")
+	}
+}
+
+func TestExportImportUpstream(t *testing.T) {
+	sourceClient := newFakeKongClient(t, newFakeKong(t, map[string]fakeResponse{
+		"/upstreams/up1": {body: `{"id": "u1", "name": "up1", "created_at": 1}`},
+		"/upstreams/u1/targets": fakeList(`[
+			{"id": "t1", "target": "10.0.0.1:80", "weight": 100, "upstream": {"id": "u1"}, "created_at": 1.5}
+		]`),
+	}))
+	data, err := sourceClient.ExportEntity(defaultCtx, "upstreams", "up1")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "created_at")
+	assert.NotContains(t, string(data), `"u1"`)
+
+	target, created := newImportServer()
+	defer target.Close()
+	targetClient, err := NewClient(String(target.URL), nil)
+	require.NoError(t, err)
+	res, err := targetClient.ImportEntity(defaultCtx, data, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Upstreams, 1)
+	require.Len(t, res.Targets, 1)
+
+	upstreamID := *res.Upstreams[0].ID
+	require.Len(t, created["/upstreams/"+upstreamID+"/targets"], 1)
+	assert.Equal(t, "10.0.0.1:80", created["/upstreams/"+upstreamID+"/targets"][0]["target"])
+}
+
+func TestExportImportConsumer(t *testing.T) {
+	sourceClient := newFakeKongClient(t, newFakeKong(t, map[string]fakeResponse{
+		"/consumers/alice": {body: `{"id": "c1", "username": "alice", "created_at": 1}`},
+		"/consumers/c1/key-auth": fakeList(`[
+			{"id": "k1", "key": "secret", "consumer": {"id": "c1"}, "created_at": 1}
+		]`),
+		"/consumers/c1/acls": fakeList(`[
+			{"id": "a1", "group": "admins", "consumer": {"id": "c1"}, "created_at": 1}
+		]`),
+		"*": fakeList("[]"),
+	}))
+	data, err := sourceClient.ExportEntity(defaultCtx, "consumers", "alice")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "created_at")
+	assert.NotContains(t, string(data), `"c1"`)
+	assert.NotContains(t, string(data), `"k1"`)
+
+	// credentials are nested under their consumer
+	var doc struct {
+		Consumers []map[string]interface{} `json:"consumers"`
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	require.Len(t, doc.Consumers, 1)
+	assert.Equal(t, map[string]interface{}{
+		"username":            "alice",
+		"keyauth_credentials": []interface{}{map[string]interface{}{"key": "secret"}},
+		"acls":                []interface{}{map[string]interface{}{"group": "admins"}},
+	}, doc.Consumers[0])
+
+	target, created := newImportServer()
+	defer target.Close()
+	targetClient, err := NewClient(String(target.URL), nil)
+	require.NoError(t, err)
+	res, err := targetClient.ImportEntity(defaultCtx, data, nil)
+	require.NoError(t, err)
+	require.Len(t, res.Consumers, 1)
+	consumerID := *res.Consumers[0].ID
+
+	require.Len(t, created["/consumers"], 1)
+	assert.Equal(t, map[string]interface{}{"username": "alice"}, created["/consumers"][0])
+	require.Len(t, created["/consumers/"+consumerID+"/key-auth"], 1)
+	assert.Equal(t, "secret", created["/consumers/"+consumerID+"/key-auth"][0]["key"])
+	require.Len(t, created["/consumers/"+consumerID+"/acls"], 1)
+	assert.Equal(t, "admins", created["/consumers/"+consumerID+"/acls"][0]["group"])
+
+	creds := res.ConsumerCredentials["alice"]
+	require.NotNil(t, creds)
+	require.Len(t, creds.KeyAuths, 1)
+	require.Len(t, creds.ACLGroups, 1)
+	assert.Empty(t, creds.BasicAuths)
+}
+
+func TestExportImportHashedSecrets(t *testing.T) {
+	sourceClient := newFakeKongClient(t, newFakeKong(t, map[string]fakeResponse{
+		"/consumers/alice": {body: `{"id": "c1", "username": "alice"}`},
+		"/consumers/c1/basic-auth": fakeList(`[
+			{"id": "b1", "username": "alice", "password": "5e8b1c0d8f", "consumer": {"id": "c1"}}
+		]`),
+		"/consumers/c1/oauth2": fakeList(`[
+			{"id": "o1", "name": "app", "client_id": "app-id", "client_secret": "$pbkdf2-sha512$x",
+				"hash_secret": true, "consumer": {"id": "c1"}}
+		]`),
+		"*": fakeList("[]"),
+	}))
+	data, err := sourceClient.ExportEntity(defaultCtx, "consumers", "alice")
+	require.NoError(t, err)
+	// hashed secrets are left out
+	assert.NotContains(t, string(data), "5e8b1c0d8f")
+	assert.NotContains(t, string(data), "pbkdf2")
+	assert.Contains(t, string(data), `"client_id": "app-id"`)
+
+	target, created := newImportServer()
+	defer target.Close()
+	targetClient, err := NewClient(String(target.URL), nil)
+	require.NoError(t, err)
+	_, err = targetClient.ImportEntity(defaultCtx, data, nil)
+	assert.EqualError(t, err, "secrets hashed by Kong can't be imported, set them for: "+
+		"basic-auth credential 'alice' of consumer 'alice', oauth2 credential 'app-id' of consumer 'alice'")
+	assert.Empty(t, created, "nothing is created")
+
+	// secrets set again are imported
+	data, err = json.Marshal(&Content{
+		Consumers: []*Consumer{{Username: String("alice")}},
+		ConsumerCredentials: map[string]*ConsumerCredentials{"alice": {
+			BasicAuths: []*BasicAuth{{Username: String("alice"), Password: String("s3cret")}},
+		}},
+	})
+	require.NoError(t, err)
+	_, err = targetClient.ImportEntity(defaultCtx, data, nil)
+	require.NoError(t, err)
+	require.Len(t, created["/consumers/new-1/basic-auth"], 1)
+	assert.Equal(t, "s3cret", created["/consumers/new-1/basic-auth"][0]["password"])
+}
+
+func TestExportImportConsumerGroup(t *testing.T) {
+	sourceClient := newFakeKongClient(t, newFakeKong(t, map[string]fakeResponse{
+		"/consumer_groups/gold": {body: `{
+			"consumer_group": {"id": "cg1", "name": "gold", "created_at": 1},
+			"consumers": [
+				{"id": "c1", "username": "alice", "created_at": 1},
+				{"id": "c2", "username": "bob", "created_at": 1}
+			],
+			"plugins": [{
+				"id": "p1", "name": "rate-limiting-advanced", "created_at": 1,
+				"consumer_group": {"id": "cg1"},
+				"config": {"limit": [10], "window_size": [60]}
+			}]
+		}`},
+	}))
+	data, err := sourceClient.ExportEntity(defaultCtx, "consumer_groups", "gold")
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "created_at")
+	assert.NotContains(t, string(data), `"cg1"`)
+	assert.NotContains(t, string(data), `"p1"`)
+
+	// the members of the group are referred to by username
+	var doc struct {
+		ConsumerGroups []map[string]interface{} `json:"consumer_groups"`
+	}
+	require.NoError(t, json.Unmarshal(data, &doc))
+	require.Len(t, doc.ConsumerGroups, 1)
+	assert.Equal(t, map[string]interface{}{
+		"name": "gold",
+		"consumers": []interface{}{
+			map[string]interface{}{"username": "alice"},
+			map[string]interface{}{"username": "bob"},
+		},
+		"plugins": []interface{}{map[string]interface{}{
+			"name":   "rate-limiting-advanced",
+			"config": map[string]interface{}{"limit": []interface{}{float64(10)}, "window_size": []interface{}{float64(60)}},
+		}},
+	}, doc.ConsumerGroups[0])
+
+	target := newFakeKong(t, map[string]fakeResponse{
+		"POST /consumer_groups": {status: http.StatusCreated, body: `{"id": "new-cg", "name": "gold"}`},
+		"POST /consumer_groups/new-cg/consumers": {
+			status: http.StatusCreated,
+			body:   `{"consumer_group": {"id": "new-cg", "name": "gold"}}`,
+		},
+		"GET /schemas/consumer_group_plugins": {body: `{"fields": [{"config": {"type": "record", "fields": [
+			{"window_type": {"type": "string", "default": "sliding"}}
+		]}}]}`},
+		"PUT *": {body: `{"consumer_group": "gold", "plugin": "rate-limiting-advanced",
+			"config": {"limit": [10], "window_size": [60], "window_type": "sliding"}}`},
+		"POST /plugins": {
+			status: http.StatusCreated,
+			body:   `{"name": "rate-limiting", "consumer_group": {"id": "new-cg"}}`,
+		},
+		"*": {status: http.StatusMethodNotAllowed},
+	})
+	targetClient := newFakeKongClient(t, target)
+	res, err := targetClient.ImportEntity(defaultCtx, data, &ImportOpt{Tags: StringSlice("imported")})
+	require.NoError(t, err)
+
+	// the group is created first, then its members and its override
+	assert.Equal(t, []string{
+		`POST /consumer_groups {"name":"gold","tags":["imported"]}`,
+		`POST /consumer_groups/new-cg/consumers {"consumer":["alice"]}`,
+		`POST /consumer_groups/new-cg/consumers {"consumer":["bob"]}`,
+		`GET /schemas/consumer_group_plugins`,
+		`PUT /consumer_groups/new-cg/overrides/plugins/rate-limiting-advanced ` +
+			`{"config":{"limit":[10],"window_size":[60],"window_type":"sliding"}}`,
+	}, target.calls())
+
+	require.Len(t, res.ConsumerGroups, 1)
+	group := res.ConsumerGroups[0]
+	assert.Equal(t, "new-cg", *group.ConsumerGroup.ID)
+	require.Len(t, group.Consumers, 2)
+	assert.Equal(t, "alice", *group.Consumers[0].Username)
+	assert.Equal(t, "bob", *group.Consumers[1].Username)
+	require.Len(t, group.Plugins, 1)
+	assert.Equal(t, "rate-limiting-advanced", *group.Plugins[0].Name)
+	assert.Equal(t, "sliding", group.Plugins[0].Config["window_type"])
+
+	// plugins scoped to an imported group refer to its new ID
+	target.reset()
+	_, err = targetClient.ImportEntity(defaultCtx, []byte(`{
+		"consumer_groups": [{"name": "gold"}],
+		"plugins": [{"name": "rate-limiting", "consumer_group": {"name": "gold"}}]
+	}`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		`POST /consumer_groups {"name":"gold"}`,
+		`POST /plugins {"name":"rate-limiting","consumer_group":{"id":"new-cg"}}`,
+	}, target.calls())
+}
+
+func TestExportImportConsumerGroupLive(t *testing.T) {
+	RunWhenEnterprise(t, ">=2.7.0", RequiredFeatures{})
+	require := require.New(t)
+	assert := assert.New(t)
+
+	client, err := NewTestClient(nil, nil)
+	require.NoError(err)
+	require.NotNil(client)
+
+	// members are referred to by username, so they must exist where the
+	// group is imported
+	for _, username := range []string{"alice", "bob"} {
+		consumer, err := client.Consumers.Create(defaultCtx, &Consumer{Username: String(username)})
+		require.NoError(err)
+		t.Cleanup(func() {
+			assert.NoError(client.Consumers.Delete(defaultCtx, consumer.ID))
+		})
+	}
+
+	rlaPlugin, err := client.Plugins.Create(defaultCtx, &Plugin{
+		Name: String("rate-limiting-advanced"),
+		Config: Configuration{
+			"limit":                   []interface{}{5},
+			"window_size":             []interface{}{30},
+			"enforce_consumer_groups": true,
+			"consumer_groups":         []string{"gold"},
+			"sync_rate":               float64(1),
+			"strategy":                "cluster",
+		},
+	})
+	require.NoError(err)
+	t.Cleanup(func() {
+		assert.NoError(client.Plugins.Delete(defaultCtx, rlaPlugin.ID))
+	})
+
+	cg, err := client.ConsumerGroups.Create(defaultCtx, &ConsumerGroup{Name: String("gold")})
+	require.NoError(err)
+	for _, username := range []string{"alice", "bob"} {
+		_, err := client.ConsumerGroupConsumers.Create(defaultCtx, cg.ID, String(username))
+		require.NoError(err)
+	}
+	_, err = client.ConsumerGroups.SetPlugin(defaultCtx, cg.Name, &ConsumerGroupPlugin{
+		Name: String("rate-limiting-advanced"),
+		Config: Configuration{
+			"limit":       []interface{}{float64(10)},
+			"window_size": []interface{}{float64(60)},
+		},
+	})
+	require.NoError(err)
+
+	data, err := client.ExportEntity(defaultCtx, "consumer_groups", "gold")
+	require.NoError(err)
+	assert.NotContains(string(data), *cg.ID)
+
+	// import the group again in place of the original one
+	require.NoError(client.ConsumerGroups.Delete(defaultCtx, cg.ID))
+	res, err := client.ImportEntity(defaultCtx, data, &ImportOpt{Tags: StringSlice("imported")})
+	require.NoError(err)
+	require.Len(res.ConsumerGroups, 1)
+	imported := res.ConsumerGroups[0].ConsumerGroup
+	t.Cleanup(func() {
+		assert.NoError(client.ConsumerGroups.Delete(defaultCtx, imported.ID))
+	})
+	assert.NotEqual(*cg.ID, *imported.ID)
+
+	group, err := client.ConsumerGroups.Get(defaultCtx, String("gold"))
+	require.NoError(err)
+	assert.Equal([]*string{String("imported")}, group.ConsumerGroup.Tags)
+	var usernames []string
+	for _, consumer := range group.Consumers {
+		usernames = append(usernames, *consumer.Username)
+	}
+	assert.ElementsMatch([]string{"alice", "bob"}, usernames)
+
+	override, err := client.ConsumerGroups.GetPlugin(defaultCtx, String("gold"), String("rate-limiting-advanced"))
+	require.NoError(err)
+	assert.Equal([]interface{}{float64(10)}, override.Config["limit"])
+	assert.Equal([]interface{}{float64(60)}, override.Config["window_size"])
+}
+
+func TestExportImportEntityErrors(t *testing.T) {
+	client, err := NewClient(String("http://localhost:1"), nil)
+	require.NoError(t, err)
+
+	_, err = client.ExportEntity(defaultCtx, "vaults", "foo")
+	assert.ErrorContains(t, err, "unsupported entity type")
+	_, err = client.ExportEntity(defaultCtx, "services", "")
+	assert.Error(t, err)
+
+	_, err = client.ImportEntity(defaultCtx, []byte("{"), nil)
+	assert.Error(t, err)
+	_, err = client.ImportEntity(defaultCtx,
+		[]byte(`{"routes": [{"name": "r", "service": {"name": "missing"}}]}`), nil)
+	assert.ErrorContains(t, err, "missing")
+}
@@ -0,0 +1,42 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+)
+
+// EntityLimits returns the maximum number of entities allowed by Kong, per
+// entity type, e.g. "routes", on deployments enforcing such limits.
+// Kong doesn't document any setting or Admin API endpoint exposing these
+// limits, so none are known to the client: the result is empty, meaning
+// that the limits are unknown and that no entity type is known to be
+// limited.
+func (c *Client) EntityLimits(_ context.Context) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+
+// CheckLimit returns an error if adding entities of entityType, e.g.
+// "routes", would exceed the limit returned by EntityLimits for this type.
+// It returns nil if the limit of the entity type is unknown.
+func (c *Client) CheckLimit(ctx context.Context, entityType string, adding int) error {
+	if entityType == "" {
+		return fmt.Errorf("entityType cannot be empty")
+	}
+	limits, err := c.EntityLimits(ctx)
+	if err != nil {
+		return err
+	}
+	limit, ok := limits[entityType]
+	if !ok {
+		return nil
+	}
+	count, err := c.countEntityType(ctx, entityType)
+	if err != nil {
+		return fmt.Errorf("counting %s: %w", entityType, err)
+	}
+	if count+adding > limit {
+		return fmt.Errorf("adding %d %s would exceed the limit of %d %s (%d exist already)",
+			adding, entityType, limit, entityType, count)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEntityLimitsUnknown(t *testing.T) {
+	srv := newFakeKong(t, nil)
+	client := newFakeKongClient(t, srv)
+
+	limits, err := client.EntityLimits(defaultCtx)
+	require.NoError(t, err)
+	assert.Empty(t, limits)
+	assert.NoError(t, client.CheckLimit(defaultCtx, "routes", 1000))
+	assert.Error(t, client.CheckLimit(defaultCtx, "", 1))
+	assert.Empty(t, srv.requests())
+}
@@ -0,0 +1,98 @@
+package kong
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// collectionPaths maps the type of Kong entities to the path of their
+// collection in the Admin API. Entities nested under a parent entity have
+// a %v placeholder for the name or ID of their parent.
+var collectionPaths = map[string]string{
+	"acl":                                  "/consumers/%v/acls",
+	"admin":                                "/admins",
+	"basic_auth":                           "/consumers/%v/basic-auth",
+	"ca_certificate":                       "/ca_certificates",
+	"certificate":                          "/certificates",
+	"consumer":                             "/consumers",
+	"consumer_group":                       "/consumer_groups",
+	"consumer_group_consumer":              "/consumer_groups/%v/consumers",
+	"consumer_group_plugin":                "/consumer_groups/%v/overrides/plugins",
+	"degraphql_route":                      "/services/%v/degraphql/routes",
+	"developer":                            "/developers",
+	"developer_role":                       "/developers/roles",
+	"graphql_ratelimiting_cost_decoration": "/graphql-rate-limiting-advanced/costs",
+	"hmac_auth":                            "/consumers/%v/hmac-auth",
+	"jwt_auth":                             "/consumers/%v/jwt",
+	"key":                                  "/keys",
+	"key_auth":                             "/consumers/%v/key-auth",
+	"key_set":                              "/key-sets",
+	"license":                              "/licenses",
+	"mtls_auth":                            "/consumers/%v/mtls-auth",
+	"oauth2":                               "/consumers/%v/oauth2",
+	"plugin":                               "/plugins",
+	"rbac_endpoint_permission":             "/rbac/roles/%v/endpoints",
+	"rbac_entity_permission":               "/rbac/roles/%v/entities",
+	"rbac_role":                            "/rbac/roles",
+	"rbac_user":                            "/rbac/users",
+	"route":                                "/routes",
+	"service":                              "/services",
+	"sni":                                  "/snis",
+	"target":                               "/upstreams/%v/targets",
+	"upstream":                             "/upstreams",
+	"vault":                                "/vaults",
+	"workspace":                            "/workspaces",
+}
+
+// CollectionPath returns the path of the collection of entities of
+// entityType in the Admin API, e.g. "/services" for "service".
+// Paths of entities nested under a parent entity, such as
+// "consumer_group_plugin", contain a %v placeholder for the name or ID of
+// the parent: "/consumer_groups/%v/overrides/plugins".
+func CollectionPath(entityType string) (string, error) {
+	path, ok := collectionPaths[entityType]
+	if !ok {
+		return "", fmt.Errorf("unknown entity type: '%s'", entityType)
+	}
+	return path, nil
+}
+
+// EntityTypeFromPath returns the type of the entities of the collection
+// at path. It is the inverse of CollectionPath: path is either a path
+// returned by CollectionPath or the same path with the name or ID of the
+// parent entity in place of the %v placeholder, such as
+// "/consumer_groups/gold/overrides/plugins".
+func EntityTypeFromPath(path string) (string, error) {
+	segments := pathSegments(path)
+	// sorted for the result not to depend on the order of the map
+	types := make([]string, 0, len(collectionPaths))
+	for entityType := range collectionPaths {
+		types = append(types, entityType)
+	}
+	sort.Strings(types)
+	for _, entityType := range types {
+		if pathMatches(pathSegments(collectionPaths[entityType]), segments) {
+			return entityType, nil
+		}
+	}
+	return "", fmt.Errorf("unknown collection path: '%s'", path)
+}
+
+func pathSegments(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// pathMatches returns true if segments match the segments of a collection
+// path, placeholders matching any non-empty segment.
+func pathMatches(collection, segments []string) bool {
+	if len(collection) != len(segments) {
+		return false
+	}
+	for i, s := range collection {
+		if segments[i] == "" || (s != "%v" && s != segments[i]) {
+			return false
+		}
+	}
+	return true
+}
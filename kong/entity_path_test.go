@@ -0,0 +1,57 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionPath(t *testing.T) {
+	for entityType, expected := range map[string]string{
+		"service":               "/services",
+		"key_set":               "/key-sets",
+		"rbac_role":             "/rbac/roles",
+		"target":                "/upstreams/%v/targets",
+		"consumer_group_plugin": "/consumer_groups/%v/overrides/plugins",
+		"jwt_auth":              "/consumers/%v/jwt",
+	} {
+		path, err := CollectionPath(entityType)
+		require.NoError(t, err)
+		assert.Equal(t, expected, path)
+	}
+
+	_, err := CollectionPath("services")
+	assert.Error(t, err)
+}
+
+func TestEntityTypeFromPath(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/services":             "service",
+		"services/":             "service",
+		"/developers/roles":     "developer_role",
+		"/upstreams/%v/targets": "target",
+		"/upstreams/u1/targets": "target",
+		"/consumer_groups/gold/overrides/plugins":     "consumer_group_plugin",
+		"/consumer_groups/gold/consumers":             "consumer_group_consumer",
+		"/rbac/roles/a3c4a8e1-role/entities":          "rbac_entity_permission",
+		"/graphql-rate-limiting-advanced/costs":       "graphql_ratelimiting_cost_decoration",
+		"/services/5a8ef1c2-service/degraphql/routes": "degraphql_route",
+	} {
+		entityType, err := EntityTypeFromPath(path)
+		require.NoError(t, err, path)
+		assert.Equal(t, expected, entityType, path)
+	}
+
+	for _, path := range []string{"/services/s1", "/upstreams//targets", "/foo", ""} {
+		_, err := EntityTypeFromPath(path)
+		assert.Error(t, err, path)
+	}
+
+	// every collection path maps back to its entity type
+	for entityType, path := range collectionPaths {
+		res, err := EntityTypeFromPath(path)
+		require.NoError(t, err)
+		assert.Equal(t, entityType, res)
+	}
+}
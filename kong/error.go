@@ -1,18 +1,88 @@
 package kong
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 )
 
+var (
+	// ErrTimeout is matched, using errors.Is, by errors of requests which
+	// didn't complete before their context deadline or the timeout of the
+	// HTTP client. Such errors also match context.DeadlineExceeded when
+	// caused by a context deadline.
+	ErrTimeout = errors.New("request timed out")
+	// ErrCanceled is matched, using errors.Is, by errors of requests whose
+	// context was canceled. Such errors also match context.Canceled.
+	ErrCanceled = errors.New("request canceled")
+)
+
+// requestContextError wraps an error caused by a request deadline or
+// cancellation so that it matches both its sentinel (ErrTimeout or
+// ErrCanceled) and the original error.
+type requestContextError struct {
+	sentinel error
+	err      error
+}
+
+func (e *requestContextError) Error() string {
+	return fmt.Sprintf("%v: %v", e.sentinel, e.err)
+}
+
+func (e *requestContextError) Is(target error) bool {
+	return target == e.sentinel
+}
+
+func (e *requestContextError) Unwrap() error {
+	return e.err
+}
+
+// wrapRequestContextErr wraps err with ErrTimeout or ErrCanceled when it
+// was caused by a timeout or a canceled context, and returns it unchanged
+// otherwise.
+func wrapRequestContextErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return &requestContextError{sentinel: ErrCanceled, err: err}
+	case errors.Is(err, context.DeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return &requestContextError{sentinel: ErrTimeout, err: err}
+	}
+	return err
+}
+
+// Codes of the errors reported by Kong's database layer,
+// see APIError.ErrorCode.
+const (
+	ErrorCodeInvalidPrimaryKey   = 1
+	ErrorCodeSchemaViolation     = 2
+	ErrorCodePrimaryKeyViolation = 3
+	ErrorCodeForeignKeyViolation = 4
+	ErrorCodeUniqueViolation     = 5
+	ErrorCodeNotFound            = 6
+)
+
 // APIError is used for Kong Admin API errors.
 type APIError struct {
 	httpCode int
 	message  string
 	raw      []byte
 	details  any
+	kongCode int
+	kongName string
+
+	// request which caused the error, only recorded in debug mode
+	reqMethod string
+	reqURL    string
+	reqBody   []byte
 }
 
 func NewAPIError(code int, msg string) *APIError {
@@ -39,11 +109,32 @@ func (e *APIError) Code() int {
 	return e.httpCode
 }
 
+// Message returns the message of the error. When Kong's response body
+// isn't JSON, e.g. an HTML page returned by a proxy, the message is the
+// body itself.
+func (e *APIError) Message() string {
+	return e.message
+}
+
 // Raw returns the raw HTTP error response body.
 func (e *APIError) Raw() []byte {
 	return e.raw
 }
 
+// ErrorCode returns the code of the error reported by Kong's database
+// layer, such as ErrorCodeUniqueViolation, or 0 if the response
+// carried none.
+func (e *APIError) ErrorCode() int {
+	return e.kongCode
+}
+
+// ErrorName returns the name of the error reported by Kong's database
+// layer, such as "unique constraint violation", or an empty string if the
+// response carried none.
+func (e *APIError) ErrorName() string {
+	return e.kongName
+}
+
 // Details returns optional details that might be relevant for proper
 // handling of the APIError on the caller side.
 func (e *APIError) Details() any {
@@ -56,6 +147,34 @@ func (e *APIError) SetDetails(details any) {
 	e.details = details
 }
 
+// setRequest records the request which caused the error, so that it can
+// be reproduced. Secrets in body are scrubbed.
+func (e *APIError) setRequest(method, url string, body []byte) {
+	e.reqMethod = method
+	e.reqURL = url
+	e.reqBody = scrubSecrets(body)
+}
+
+// Reproduction returns a curl command reproducing the request which
+// caused the error, with secrets in the request body redacted.
+// The request is only recorded when the client is in debug mode,
+// see Client.SetDebugMode; an empty string is returned otherwise.
+func (e *APIError) Reproduction() string {
+	if e.reqMethod == "" {
+		return ""
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "curl -X %s %s", e.reqMethod, shellQuote(e.reqURL))
+	if len(e.reqBody) > 0 {
+		fmt.Fprintf(&b, " -H 'Content-Type: application/json' -d %s", shellQuote(string(e.reqBody)))
+	}
+	return b.String()
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // IsNotFoundErr returns true if the error or it's cause is
 // a 404 response from Kong.
 func IsNotFoundErr(e error) bool {
@@ -66,6 +185,15 @@ func IsNotFoundErr(e error) bool {
 	return false
 }
 
+// ignoreNotFoundErr returns nil if e is a 404 response from Kong,
+// and e otherwise.
+func ignoreNotFoundErr(e error) error {
+	if IsNotFoundErr(e) {
+		return nil
+	}
+	return e
+}
+
 // IsForbiddenErr returns true if the error or its cause is
 // a 403 response from Kong.
 func IsForbiddenErr(e error) bool {
@@ -76,6 +204,17 @@ func IsForbiddenErr(e error) bool {
 	return false
 }
 
+// IsConflictErr returns true if the error or its cause is
+// a 409 response from Kong, e.g. when an entity with the same unique
+// field already exists.
+func IsConflictErr(e error) bool {
+	var apiErr *APIError
+	if errors.As(e, &apiErr) {
+		return apiErr.httpCode == http.StatusConflict
+	}
+	return false
+}
+
 // ErrTooManyRequestsDetails is expected to be available under APIError.Details()
 // when the API returns status code 429 (Too many requests) and a `Retry-After` header
 // is set.
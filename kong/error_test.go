@@ -1,10 +1,14 @@
 package kong
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -81,3 +85,87 @@ func (ft *forbiddenTransport) RoundTrip(_ *http.Request) (*http.Response, error)
 		"Enterprise license missing or expired",
 	)
 }
+
+func TestRequestContextErrors(T *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	T.Run("context deadline", func(t *testing.T) {
+		client, err := NewClient(String(srv.URL), nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(defaultCtx, 10*time.Millisecond)
+		defer cancel()
+		_, err = client.Root(ctx)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrTimeout))
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.False(t, errors.Is(err, ErrCanceled))
+	})
+
+	T.Run("http client timeout", func(t *testing.T) {
+		client, err := NewClient(String(srv.URL), &http.Client{Timeout: 10 * time.Millisecond})
+		require.NoError(t, err)
+
+		_, err = client.Root(defaultCtx)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrTimeout))
+	})
+
+	T.Run("canceled context", func(t *testing.T) {
+		client, err := NewClient(String(srv.URL), nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithCancel(defaultCtx)
+		time.AfterFunc(10*time.Millisecond, cancel)
+		_, err = client.Root(ctx)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrCanceled))
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.False(t, errors.Is(err, ErrTimeout))
+	})
+
+	T.Run("other errors are not wrapped", func(t *testing.T) {
+		err := errors.New("boom")
+		assert.Equal(t, err, wrapRequestContextErr(err))
+		assert.Nil(t, wrapRequestContextErr(nil))
+	})
+}
+
+func TestAPIErrorReproduction(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message": "schema violation (ttl: expected an integer)"}`))
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(String(srv.URL), nil)
+	require.NoError(t, err)
+	client.SetLogger(io.Discard)
+
+	credential := &KeyAuth{Key: String("my-s3cr3t"), TTL: Int(-1)}
+	_, err = client.KeyAuths.Create(defaultCtx, String("alice"), credential)
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "", apiErr.Reproduction(), "requests are only recorded in debug mode")
+
+	client.SetDebugMode(true)
+	_, err = client.KeyAuths.Create(defaultCtx, String("alice"), credential)
+	require.ErrorAs(t, err, &apiErr)
+	repro := apiErr.Reproduction()
+	assert.Contains(t, repro, "curl -X POST '"+srv.URL+"/consumers/alice/key-auth'")
+	assert.Contains(t, repro, `"ttl":-1`)
+	assert.Contains(t, repro, `"key":"[REDACTED]"`)
+	assert.NotContains(t, repro, "my-s3cr3t")
+
+	_, err = client.Services.Get(defaultCtx, String("foo'bar"))
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, `curl -X GET '`+srv.URL+`/services/foo'\''bar'`, apiErr.Reproduction())
+}
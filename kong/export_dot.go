@@ -0,0 +1,124 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// dotShapes are the shapes of the nodes of each entity type in DOT graphs.
+var dotShapes = map[string]string{
+	"services":        "box",
+	"routes":          "ellipse",
+	"consumers":       "house",
+	"consumer_groups": "tab",
+	"upstreams":       "box3d",
+	"targets":         "component",
+	"plugins":         "note",
+}
+
+// ExportDOT returns the entities exported by Export with opts as a
+// Graphviz DOT graph, see ContentDOT.
+func (c *Client) ExportDOT(ctx context.Context, opts ExportOpts) (string, error) {
+	content, err := c.Export(ctx, opts)
+	if err != nil {
+		return "", err
+	}
+	return ContentDOT(content), nil
+}
+
+// ContentDOT returns a Graphviz DOT graph of the entities of content and
+// of their relationships. Nodes are named after the entity type and the
+// natural key of entities, as used by Plan, e.g. "services/svc1". Edges
+// go from Services to their Routes, from Upstreams to their Targets, from
+// Consumer Groups to their member Consumers and from Services, Routes,
+// Consumers and Consumer Groups to the Plugins scoped to them.
+// A dashed edge goes from a Service to the Upstream it load-balances to,
+// i.e. the Upstream named after the host of the Service.
+func ContentDOT(content *Content) string {
+	keys := planKeys{}
+	entities := planEntities(content, keys)
+	upstreams := make(map[string]bool)
+	for _, e := range entities["upstreams"] {
+		upstreams[e.key] = true
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph kong {\n\trankdir=LR;\n")
+	node := func(entityType, key, label string) {
+		fmt.Fprintf(&b, "\t%s [label=%s, shape=%s];\n", dotID(entityType, key),
+			dotQuote(strings.TrimSuffix(entityType, "s")+"\n"+label), dotShapes[entityType])
+	}
+	edge := func(fromType, fromKey, toType, toKey, attrs string) {
+		fmt.Fprintf(&b, "\t%s -> %s%s;\n", dotID(fromType, fromKey), dotID(toType, toKey), attrs)
+	}
+	for _, entityType := range planEntityTypes {
+		for _, e := range entities[entityType] {
+			label := e.key
+			switch entity := e.entity.(type) {
+			case *Target:
+				label = planNameOrID(entity.Target, nil)
+			case *Plugin:
+				label = planNameOrID(entity.InstanceName, entity.Name)
+			case *ConsumerGroupObject:
+				label = planNameOrID(consumerGroupName(entity), nil)
+			}
+			node(entityType, e.key, label)
+		}
+	}
+	for _, entityType := range planEntityTypes {
+		for _, e := range entities[entityType] {
+			switch entity := e.entity.(type) {
+			case *Service:
+				if entity.Host != nil && upstreams[*entity.Host] {
+					edge("services", e.key, "upstreams", *entity.Host, " [style=dashed]")
+				}
+			case *Route:
+				if entity.Service != nil {
+					edge("services", keys.ref("services", entity.Service.Name, entity.Service.ID),
+						"routes", e.key, "")
+				}
+			case *ConsumerGroupObject:
+				for _, consumer := range entity.Consumers {
+					edge("consumer_groups", e.key,
+						"consumers", keys.ref("consumers", consumer.Username, consumer.ID), "")
+				}
+			case *Target:
+				if entity.Upstream != nil {
+					edge("upstreams", keys.ref("upstreams", entity.Upstream.Name, entity.Upstream.ID),
+						"targets", e.key, "")
+				}
+			case *Plugin:
+				if entity.Service != nil {
+					edge("services", keys.ref("services", entity.Service.Name, entity.Service.ID),
+						"plugins", e.key, "")
+				}
+				if entity.Route != nil {
+					edge("routes", keys.ref("routes", entity.Route.Name, entity.Route.ID),
+						"plugins", e.key, "")
+				}
+				if entity.Consumer != nil {
+					edge("consumers", keys.ref("consumers", entity.Consumer.Username, entity.Consumer.ID),
+						"plugins", e.key, "")
+				}
+				if entity.ConsumerGroup != nil {
+					edge("consumer_groups", keys.ref("consumer_groups", entity.ConsumerGroup.Name,
+						entity.ConsumerGroup.ID), "plugins", e.key, "")
+				}
+			}
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func dotID(entityType, key string) string {
+	return dotQuote(entityType + "/" + key)
+}
+
+// dotQuote returns s as a quoted DOT string.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + strings.ReplaceAll(s, "\n", `\n`) + `"`
+}
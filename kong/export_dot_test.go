@@ -0,0 +1,68 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportDOT(t *testing.T) {
+	srv := newPlanServer(t, map[string]string{
+		"/services": `[
+			{"id": "s1", "name": "svc1", "host": "backend"},
+			{"id": "s2", "name": "svc2", "host": "example.com"}
+		]`,
+		"/routes":               `[{"id": "r1", "name": "route1", "service": {"id": "s1"}}]`,
+		"/consumers":            `[{"id": "c1", "username": "alice"}]`,
+		"/upstreams":            `[{"id": "u1", "name": "backend"}]`,
+		"/upstreams/u1/targets": `[{"id": "t1", "target": "10.0.0.1:80", "upstream": {"id": "u1"}}]`,
+		"/plugins": `[
+			{"id": "p1", "name": "key-auth", "route": {"id": "r1"}},
+			{"id": "p2", "name": "rate-limiting", "service": {"id": "s2"}, "consumer": {"id": "c1"}},
+			{"id": "p3", "name": "prometheus"}
+		]`,
+	})
+	client := newFakeKongClient(t, srv)
+
+	dot, err := client.ExportDOT(defaultCtx, ExportOpts{})
+	require.NoError(t, err)
+	assert.Equal(t, `digraph kong {
+	rankdir=LR;
+	"services/svc1" [label="service\nsvc1", shape=box];
+	"services/svc2" [label="service\nsvc2", shape=box];
+	"routes/route1" [label="route\nroute1", shape=ellipse];
+	"consumers/alice" [label="consumer\nalice", shape=house];
+	"upstreams/backend" [label="upstream\nbackend", shape=box3d];
+	"targets/backend/10.0.0.1:80" [label="target\n10.0.0.1:80", shape=component];
+	"plugins/key-auth route:route1" [label="plugin\nkey-auth", shape=note];
+	"plugins/rate-limiting service:svc2 consumer:alice" [label="plugin\nrate-limiting", shape=note];
+	"plugins/prometheus" [label="plugin\nprometheus", shape=note];
+	"services/svc1" -> "upstreams/backend" [style=dashed];
+	"services/svc1" -> "routes/route1";
+	"upstreams/backend" -> "targets/backend/10.0.0.1:80";
+	"routes/route1" -> "plugins/key-auth route:route1";
+	"services/svc2" -> "plugins/rate-limiting service:svc2 consumer:alice";
+	"consumers/alice" -> "plugins/rate-limiting service:svc2 consumer:alice";
+}
+`, dot)
+}
+
+func TestContentDOTQuoting(t *testing.T) {
+	dot := ContentDOT(&Content{Services: []*Service{{Name: String(`say "hi"`)}}})
+	assert.Contains(t, dot, `"services/say \"hi\"" [label="service\nsay \"hi\"", shape=box];`)
+}
+
+func TestContentDOTConsumerGroups(t *testing.T) {
+	dot := ContentDOT(&Content{
+		Consumers: []*Consumer{{Username: String("alice")}},
+		ConsumerGroups: []*ConsumerGroupObject{{
+			ConsumerGroup: &ConsumerGroup{Name: String("gold")},
+			Consumers:     []*Consumer{{Username: String("alice")}},
+		}},
+		Plugins: []*Plugin{{Name: String("rate-limiting"), ConsumerGroup: &ConsumerGroup{Name: String("gold")}}},
+	})
+	assert.Contains(t, dot, `"consumer_groups/gold" [label="consumer_group\ngold", shape=tab];`)
+	assert.Contains(t, dot, `"consumer_groups/gold" -> "consumers/alice";`)
+	assert.Contains(t, dot, `"consumer_groups/gold" -> "plugins/rate-limiting consumer_group:gold";`)
+}
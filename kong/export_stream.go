@@ -0,0 +1,300 @@
+package kong
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Formats supported by ExportTo.
+const (
+	ExportFormatJSON = "json"
+	ExportFormatYAML = "yaml"
+)
+
+// ExportOpts configures Export and ExportTo.
+type ExportOpts struct {
+	// Format is the format of the document, ExportFormatJSON or
+	// ExportFormatYAML. It defaults to ExportFormatJSON.
+	Format string
+	// Tags restricts the export to the entities having any of the tags,
+	// or all of them if MatchAllTags is set. When exporting the entities
+	// having all the tags, i.e. if MatchAllTags is set or a single tag is
+	// given, the tags are recorded as the select_tags of the document.
+	Tags         []*string
+	MatchAllTags bool
+}
+
+// Export returns the Services, Routes, Consumers, Upstreams, Targets,
+// Plugins and Consumer Groups of Kong, as ExportTo writes them.
+// opts.Format is ignored.
+func (c *Client) Export(ctx context.Context, opts ExportOpts) (*Content, error) {
+	opts.Format = ExportFormatJSON
+	var buf bytes.Buffer
+	if err := c.ExportTo(ctx, &buf, opts); err != nil {
+		return nil, err
+	}
+	var content Content
+	if err := json.Unmarshal(buf.Bytes(), &content); err != nil {
+		return nil, err
+	}
+	return &content, nil
+}
+
+// selectTags returns the tags which all the entities exported with opts
+// have, if any.
+func (opts ExportOpts) selectTags() []string {
+	if len(opts.Tags) == 0 || (len(opts.Tags) > 1 && !opts.MatchAllTags) {
+		return nil
+	}
+	var res []string
+	for _, tag := range opts.Tags {
+		if tag != nil {
+			res = append(res, *tag)
+		}
+	}
+	return res
+}
+
+// ExportTo writes the Services, Routes, Consumers, Upstreams, Targets,
+// Plugins and Consumer Groups of Kong to w, as a declarative configuration
+// in the Content format. Consumer Groups, only supported by Kong Gateway
+// Enterprise, are written with their members and plugin overrides; their
+// section is left out if Kong doesn't support them.
+// Entities are written as they are fetched, one page at a time, so that
+// the whole configuration is never held in memory. Entities are exported
+// as returned by Kong, including their IDs.
+// If an error occurs, the document written to w is incomplete.
+func (c *Client) ExportTo(ctx context.Context, w io.Writer, opts ExportOpts) error {
+	var ew *exportWriter
+	switch opts.Format {
+	case "", ExportFormatJSON:
+		ew = &exportWriter{w: w}
+	case ExportFormatYAML:
+		ew = &exportWriter{w: w, yaml: true}
+	default:
+		return fmt.Errorf("unsupported export format: '%s'", opts.Format)
+	}
+	listOpt := ListOpt{Size: pageSize, Tags: opts.Tags, MatchAllTags: opts.MatchAllTags}
+
+	if err := ew.begin(opts.selectTags()); err != nil {
+		return err
+	}
+	for _, entityType := range []string{"services", "routes", "consumers"} {
+		if err := ew.section(entityType, func() error {
+			return c.exportPages(ctx, "/"+entityType, listOpt, ew.entity)
+		}); err != nil {
+			return err
+		}
+	}
+
+	// only the IDs of upstreams are kept to export their targets
+	var upstreamIDs []string
+	err := ew.section("upstreams", func() error {
+		return c.exportPages(ctx, "/upstreams", listOpt, func(raw json.RawMessage) error {
+			var upstream struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(raw, &upstream); err != nil {
+				return err
+			}
+			upstreamIDs = append(upstreamIDs, upstream.ID)
+			return ew.entity(raw)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	err = ew.section("targets", func() error {
+		for _, id := range upstreamIDs {
+			if err := c.exportPages(ctx, "/upstreams/"+id+"/targets", listOpt, ew.entity); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := ew.section("plugins", func() error {
+		return c.exportPages(ctx, "/plugins", listOpt, ew.entity)
+	}); err != nil {
+		return err
+	}
+
+	// Kong OSS has no /consumer_groups endpoint: the section is left out
+	// if Kong can't list them
+	groupsOpt := listOpt
+	groups, next, err := c.list(ctx, "/consumer_groups", &groupsOpt)
+	if IsNotFoundErr(err) {
+		return ew.end()
+	}
+	if err != nil {
+		return err
+	}
+	if err := ew.section("consumer_groups", func() error {
+		for {
+			for _, raw := range groups {
+				if err := c.exportConsumerGroupObject(ctx, raw, ew); err != nil {
+					return err
+				}
+			}
+			if next == nil {
+				return nil
+			}
+			if groups, next, err = c.list(ctx, "/consumer_groups", next); err != nil {
+				return err
+			}
+		}
+	}); err != nil {
+		return err
+	}
+	return ew.end()
+}
+
+// exportConsumerGroupObject writes the Consumer Group listed as raw along
+// with its members and plugin overrides, which are only returned when
+// fetching the group itself.
+func (c *Client) exportConsumerGroupObject(ctx context.Context, raw json.RawMessage,
+	ew *exportWriter,
+) error {
+	var group ConsumerGroup
+	if err := json.Unmarshal(raw, &group); err != nil {
+		return err
+	}
+	obj, err := c.ConsumerGroups.Get(ctx, group.ID)
+	if err != nil {
+		return fmt.Errorf("fetching consumer group '%s': %w", group.FriendlyName(), err)
+	}
+	b, err := json.Marshal(newContentConsumerGroup(obj))
+	if err != nil {
+		return err
+	}
+	return ew.entity(b)
+}
+
+// exportPages calls fn with each entity listed at endpoint, one page at
+// a time.
+func (c *Client) exportPages(ctx context.Context, endpoint string, listOpt ListOpt,
+	fn func(json.RawMessage) error,
+) error {
+	opt := &listOpt
+	for opt != nil {
+		var data []json.RawMessage
+		var err error
+		data, opt, err = c.list(ctx, endpoint, opt)
+		if err != nil {
+			return err
+		}
+		for _, raw := range data {
+			if err := fn(raw); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// exportWriter writes a declarative configuration incrementally.
+type exportWriter struct {
+	w    io.Writer
+	yaml bool
+	// entities is the number of entities written in the current section
+	entities int
+}
+
+func (e *exportWriter) write(s string) error {
+	_, err := io.WriteString(e.w, s)
+	return err
+}
+
+// begin writes the metadata of the document: its format version and its
+// select_tags, if any.
+func (e *exportWriter) begin(selectTags []string) error {
+	var info []byte
+	if len(selectTags) > 0 {
+		var err error
+		if info, err = json.Marshal(contentInfo{SelectTags: selectTags}); err != nil {
+			return err
+		}
+	}
+	if e.yaml {
+		if err := e.write(fmt.Sprintf("_format_version: %q\n", exportFormatVersion)); err != nil {
+			return err
+		}
+		if info == nil {
+			return nil
+		}
+		b, err := yaml.JSONToYAML(append(append([]byte(`{"_info":`), info...), '}'))
+		if err != nil {
+			return err
+		}
+		_, err = e.w.Write(b)
+		return err
+	}
+	if err := e.write(fmt.Sprintf(`{"_format_version":%q`, exportFormatVersion)); err != nil {
+		return err
+	}
+	if info == nil {
+		return nil
+	}
+	return e.write(`,"_info":` + string(info))
+}
+
+// section writes the list of entities name, written by fn.
+func (e *exportWriter) section(name string, fn func() error) error {
+	e.entities = 0
+	if e.yaml {
+		if err := e.write(name + ":"); err != nil {
+			return err
+		}
+	} else if err := e.write(fmt.Sprintf(",%q:[", name)); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
+	}
+	if e.yaml {
+		if e.entities == 0 {
+			return e.write(" []\n")
+		}
+		return nil
+	}
+	return e.write("]")
+}
+
+func (e *exportWriter) entity(raw json.RawMessage) error {
+	e.entities++
+	if e.yaml {
+		b, err := yaml.JSONToYAML(append(append([]byte("["), raw...), ']'))
+		if err != nil {
+			return err
+		}
+		if e.entities == 1 {
+			if err := e.write("\n"); err != nil {
+				return err
+			}
+		}
+		_, err = e.w.Write(b)
+		return err
+	}
+	if e.entities > 1 {
+		if err := e.write(","); err != nil {
+			return err
+		}
+	}
+	_, err := e.w.Write(raw)
+	return err
+}
+
+func (e *exportWriter) end() error {
+	if e.yaml {
+		return nil
+	}
+	return e.write("}\n")
+}
@@ -0,0 +1,133 @@
+package kong
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestExportTo(t *testing.T) {
+	client := newFakeKongClient(t, newFakeKong(t, map[string]fakeResponse{
+		// services are returned in two pages
+		"/services": {body: `{"data": [{"id": "s1", "name": "svc1", "host": "a.example.com"}],
+			"offset": "next"}`},
+		"/services?offset=next": fakeList(`[{"id": "s2", "name": "svc2", "host": "b.example.com"}]`),
+		"/routes":               fakeList(`[{"id": "r1", "name": "route1", "paths": ["/foo"], "service": {"id": "s1"}}]`),
+		"/upstreams":            fakeList(`[{"id": "u1", "name": "upstream1"}]`),
+		"/upstreams/u1/targets": fakeList(`[{"id": "t1", "target": "10.0.0.1:80", "upstream": {"id": "u1"}}]`),
+		"*":                     fakeList("[]"),
+	}))
+
+	for _, format := range []string{ExportFormatJSON, ExportFormatYAML} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, client.ExportTo(defaultCtx, &buf, ExportOpts{Format: format}))
+
+			var content Content
+			if format == ExportFormatJSON {
+				require.NoError(t, json.Unmarshal(buf.Bytes(), &content))
+			} else {
+				require.NoError(t, yaml.Unmarshal(buf.Bytes(), &content))
+			}
+			assert.Equal(t, exportFormatVersion, *content.FormatVersion)
+			require.Len(t, content.Services, 2)
+			assert.Equal(t, "svc1", *content.Services[0].Name)
+			assert.Equal(t, "s2", *content.Services[1].ID)
+			require.Len(t, content.Routes, 1)
+			assert.Equal(t, []*string{String("/foo")}, content.Routes[0].Paths)
+			assert.Empty(t, content.Consumers)
+			require.Len(t, content.Upstreams, 1)
+			require.Len(t, content.Targets, 1)
+			assert.Equal(t, "10.0.0.1:80", *content.Targets[0].Target)
+			assert.Empty(t, content.Plugins)
+		})
+	}
+
+	err := client.ExportTo(defaultCtx, &bytes.Buffer{}, ExportOpts{Format: "toml"})
+	assert.Error(t, err)
+}
+
+func TestExportConsumerGroups(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/consumer_groups": fakeList(`[{"id": "cg1", "name": "gold"}]`),
+		"/consumer_groups/cg1": {body: `{
+			"consumer_group": {"id": "cg1", "name": "gold"},
+			"consumers": [{"id": "c1", "username": "alice"}],
+			"plugins": [{"name": "rate-limiting-advanced", "config": {"limit": [10]}}]
+		}`},
+		"*": fakeList("[]"),
+	})
+	client := newFakeKongClient(t, srv)
+	notFound := fakeResponse{status: http.StatusNotFound, body: `{"message": "Not found"}`}
+
+	var buf bytes.Buffer
+	require.NoError(t, client.ExportTo(defaultCtx, &buf, ExportOpts{}))
+	var doc struct {
+		ConsumerGroups []map[string]interface{} `json:"consumer_groups"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	require.Len(t, doc.ConsumerGroups, 1)
+	assert.Equal(t, "gold", doc.ConsumerGroups[0]["name"])
+	assert.Equal(t, "cg1", doc.ConsumerGroups[0]["id"])
+
+	content, err := client.Export(defaultCtx, ExportOpts{})
+	require.NoError(t, err)
+	require.Len(t, content.ConsumerGroups, 1)
+	group := content.ConsumerGroups[0]
+	assert.Equal(t, "gold", *group.ConsumerGroup.Name)
+	require.Len(t, group.Consumers, 1)
+	assert.Equal(t, "alice", *group.Consumers[0].Username)
+	require.Len(t, group.Plugins, 1)
+	assert.Equal(t, "rate-limiting-advanced", *group.Plugins[0].Name)
+
+	// a group deleted while exporting fails the export
+	srv.set("/consumer_groups/cg1", notFound)
+	_, err = client.Export(defaultCtx, ExportOpts{})
+	assert.True(t, IsNotFoundErr(err))
+	assert.ErrorContains(t, err, "consumer group 'gold'")
+
+	// consumer groups are not supported by Kong OSS
+	srv.set("/consumer_groups", notFound)
+	for _, format := range []string{ExportFormatJSON, ExportFormatYAML} {
+		buf.Reset()
+		require.NoError(t, client.ExportTo(defaultCtx, &buf, ExportOpts{Format: format}))
+		assert.NotContains(t, buf.String(), "consumer_groups")
+		if format == ExportFormatJSON {
+			assert.True(t, json.Valid(buf.Bytes()))
+		}
+	}
+}
+
+func TestExportSelectTags(t *testing.T) {
+	client := newFakeKongClient(t, newFakeKong(t, map[string]fakeResponse{
+		"/services?tags=team-a": fakeList(`[{"id": "s1", "name": "svc1", "tags": ["team-a"]}]`),
+		"*":                     fakeList("[]"),
+	}))
+
+	content, err := client.Export(defaultCtx, ExportOpts{Tags: StringSlice("team-a")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a"}, content.SelectTags)
+	require.Len(t, content.Services, 1)
+	assert.Equal(t, "svc1", *content.Services[0].Name)
+
+	var buf bytes.Buffer
+	require.NoError(t, client.ExportTo(defaultCtx, &buf,
+		ExportOpts{Format: ExportFormatYAML, Tags: StringSlice("team-a")}))
+	assert.Contains(t, buf.String(), "_info:\n  select_tags:\n  - team-a\n")
+
+	// entities having any of several tags don't share select_tags
+	content, err = client.Export(defaultCtx, ExportOpts{Tags: StringSlice("team-a", "team-b")})
+	require.NoError(t, err)
+	assert.Empty(t, content.SelectTags)
+	content, err = client.Export(defaultCtx, ExportOpts{
+		Tags:         StringSlice("team-a", "team-b"),
+		MatchAllTags: true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a", "team-b"}, content.SelectTags)
+}
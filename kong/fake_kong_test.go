@@ -0,0 +1,265 @@
+package kong
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeResponse is a response served by a fake Kong, see newFakeKong.
+type fakeResponse struct {
+	// status defaults to 200.
+	status int
+	header http.Header
+	body   string
+}
+
+// fakeRequest is a request received by a fake Kong.
+type fakeRequest struct {
+	method string
+	path   string // escaped
+	query  url.Values
+	body   string
+}
+
+// fakeRoute is a key of the responses of newFakeKong, parsed.
+type fakeRoute struct {
+	key    string
+	method string
+	path   string
+	query  url.Values
+}
+
+func parseFakeRoute(key string) (fakeRoute, error) {
+	route := fakeRoute{key: key, path: key}
+	if i := strings.IndexByte(key, ' '); i >= 0 {
+		route.method, route.path = key[:i], key[i+1:]
+	}
+	if i := strings.IndexByte(route.path, '?'); i >= 0 {
+		query, err := url.ParseQuery(route.path[i+1:])
+		if err != nil {
+			return route, err
+		}
+		route.path, route.query = route.path[:i], query
+	}
+	return route, nil
+}
+
+// matches reports whether r is routed to the response of route.
+func (route fakeRoute) matches(r *http.Request) bool {
+	if route.method != "" && route.method != r.Method {
+		return false
+	}
+	if route.path != "*" && route.path != r.URL.EscapedPath() {
+		return false
+	}
+	query := r.URL.Query()
+	for k := range route.query {
+		if query.Get(k) != route.query.Get(k) {
+			return false
+		}
+	}
+	return true
+}
+
+// specificity ranks the routes matching a request: the response of the
+// most specific one is served.
+func (route fakeRoute) specificity() int {
+	res := len(route.query) * 4
+	if route.path != "*" {
+		res += 2
+	}
+	if route.method != "" {
+		res++
+	}
+	return res
+}
+
+// fakeKong is a fake Kong Admin API serving canned responses to the tests
+// which don't run against a live Kong.
+type fakeKong struct {
+	*httptest.Server
+	t *testing.T
+
+	lock      sync.Mutex // Synchronizes access to the fields below.
+	routes    []fakeRoute
+	responses map[string]fakeResponse
+	log       []fakeRequest
+}
+
+// newFakeKong starts a fake Kong serving responses, keyed by request:
+// "/services" matches every request to /services, "POST /services" only
+// the POST ones and "/services?offset=o1" only those whose offset query
+// parameter is o1. Paths are matched escaped, e.g. "/vaults/foo%2Fbar".
+// The response of the most specific matching key is served. A "*" path
+// matches any path, e.g. "GET *" sets the response of the GET requests
+// matching no other key; requests matching no key at all get a 404.
+// The fake Kong is closed when t and its subtests complete.
+func newFakeKong(t *testing.T, responses map[string]fakeResponse) *fakeKong {
+	t.Helper()
+	k := &fakeKong{t: t, responses: make(map[string]fakeResponse, len(responses))}
+	for key, response := range responses {
+		k.set(key, response)
+	}
+	k.Server = httptest.NewServer(http.HandlerFunc(k.serveHTTP))
+	t.Cleanup(k.Close)
+	return k
+}
+
+// set sets the response served for key, as passed to newFakeKong.
+func (k *fakeKong) set(key string, response fakeResponse) {
+	k.t.Helper()
+	route, err := parseFakeRoute(key)
+	if err != nil {
+		k.t.Fatalf("invalid fake Kong key '%s': %v", key, err)
+	}
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	if _, ok := k.responses[key]; !ok {
+		k.routes = append(k.routes, route)
+		// most specific routes first, in a stable order
+		sort.Slice(k.routes, func(i, j int) bool {
+			if a, b := k.routes[i].specificity(), k.routes[j].specificity(); a != b {
+				return a > b
+			}
+			return k.routes[i].key < k.routes[j].key
+		})
+	}
+	k.responses[key] = response
+}
+
+// remove stops serving the response set for key.
+func (k *fakeKong) remove(key string) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	delete(k.responses, key)
+	for i, route := range k.routes {
+		if route.key == key {
+			k.routes = append(k.routes[:i], k.routes[i+1:]...)
+			break
+		}
+	}
+}
+
+func (k *fakeKong) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	k.lock.Lock()
+	k.log = append(k.log, fakeRequest{
+		method: r.Method,
+		path:   r.URL.EscapedPath(),
+		query:  r.URL.Query(),
+		body:   string(body),
+	})
+	response, ok := k.route(r)
+	k.lock.Unlock()
+
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	for name, values := range response.header {
+		w.Header()[name] = values
+	}
+	if response.status != 0 {
+		w.WriteHeader(response.status)
+	}
+	_, _ = w.Write([]byte(response.body))
+}
+
+// route returns the response of the most specific route matching r.
+func (k *fakeKong) route(r *http.Request) (fakeResponse, bool) {
+	for _, route := range k.routes {
+		if route.matches(r) {
+			return k.responses[route.key], true
+		}
+	}
+	return fakeResponse{}, false
+}
+
+// requests returns the requests received so far, in order.
+func (k *fakeKong) requests() []fakeRequest {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	return append([]fakeRequest(nil), k.log...)
+}
+
+// paths returns the paths of the requests received so far, in order.
+func (k *fakeKong) paths() []string {
+	return k.pathsFor("")
+}
+
+// pathsFor returns the paths of the requests using method received so far,
+// in order. An empty method matches all requests.
+func (k *fakeKong) pathsFor(method string) []string {
+	var res []string
+	for _, r := range k.requests() {
+		if method == "" || r.method == method {
+			res = append(res, r.path)
+		}
+	}
+	return res
+}
+
+// queries returns the value of the name query parameter of the requests
+// received so far, in order.
+func (k *fakeKong) queries(name string) []string {
+	var res []string
+	for _, r := range k.requests() {
+		res = append(res, r.query.Get(name))
+	}
+	return res
+}
+
+// calls returns the requests received so far, in order, each as its method,
+// path and body, e.g. `POST /services {"name":"foo"}`.
+func (k *fakeKong) calls() []string {
+	var res []string
+	for _, r := range k.requests() {
+		res = append(res, strings.TrimSpace(r.method+" "+r.path+" "+r.body))
+	}
+	return res
+}
+
+// count returns the number of requests received so far whose method, if
+// any, and path match key, e.g. "POST /services" or "/services".
+func (k *fakeKong) count(key string) int {
+	method, path := "", key
+	if i := strings.IndexByte(key, ' '); i >= 0 {
+		method, path = key[:i], key[i+1:]
+	}
+	var count int
+	for _, r := range k.requests() {
+		if (method == "" || r.method == method) && r.path == path {
+			count++
+		}
+	}
+	return count
+}
+
+// reset forgets the requests received so far.
+func (k *fakeKong) reset() {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+	k.log = nil
+}
+
+// fakeList returns the response of a list endpoint serving the entities
+// of data, a JSON array, in a single page.
+func fakeList(data string) fakeResponse {
+	return fakeResponse{body: `{"data": ` + data + `, "next": null}`}
+}
+
+// newFakeKongClient returns a Client talking to k.
+func newFakeKongClient(t *testing.T, k *fakeKong) *Client {
+	t.Helper()
+	client, err := NewClient(String(k.URL), nil)
+	if err != nil {
+		t.Fatalf("creating client: %v", err)
+	}
+	return client
+}
@@ -0,0 +1,101 @@
+package kong
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// WithFieldAliases sets the names used in the JSON of entities by Kong
+// distributions which renamed some of their fields, keyed by the name used
+// by Kong, e.g. {"read_timeout": "readTimeout"}.
+// Fields are renamed to their alias in the bodies of requests and back from
+// their alias in the bodies of responses. Only the top-level fields of
+// entities are renamed, including those of the entities of list responses;
+// nested objects, such as the config of plugins or references to other
+// entities, are left as is, and so are fields without an alias.
+func WithFieldAliases(aliases map[string]string) ClientOption {
+	return func(o *clientOptions) {
+		if o.fieldAliases == nil {
+			o.fieldAliases = make(map[string]string, len(aliases))
+		}
+		for field, alias := range aliases {
+			o.fieldAliases[field] = alias
+		}
+	}
+}
+
+// fieldAliases renames the fields of the JSON sent to and received from
+// Kong, see WithFieldAliases.
+type fieldAliases struct {
+	// toAlias maps the name of fields used by Kong to their alias.
+	toAlias map[string]string
+	// fromAlias maps aliases to the name of fields used by Kong.
+	fromAlias map[string]string
+}
+
+func newFieldAliases(aliases map[string]string) *fieldAliases {
+	if len(aliases) == 0 {
+		return nil
+	}
+	res := &fieldAliases{
+		toAlias:   make(map[string]string, len(aliases)),
+		fromAlias: make(map[string]string, len(aliases)),
+	}
+	for field, alias := range aliases {
+		res.toAlias[field] = alias
+		res.fromAlias[alias] = field
+	}
+	return res
+}
+
+// encode renames the fields of data, the JSON of a request body, to their
+// alias.
+func (a *fieldAliases) encode(data []byte) ([]byte, error) {
+	return renameJSONFields(data, a.toAlias, false)
+}
+
+// decode renames the aliased fields of data, the JSON of a response body,
+// back to the name used by Kong. data is either an entity or a list of
+// entities.
+func (a *fieldAliases) decode(data []byte) ([]byte, error) {
+	return renameJSONFields(data, a.fromAlias, true)
+}
+
+// renameJSONFields renames the top-level keys of data, the JSON object of
+// an entity, found in names. If list is true and data holds a data array,
+// as list responses do, the keys of its objects are renamed instead.
+func renameJSONFields(data []byte, names map[string]string, list bool) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	// keep numbers as is
+	decoder.UseNumber()
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	if object, ok := v.(map[string]interface{}); ok && list {
+		if entities, ok := object["data"].([]interface{}); ok {
+			for i, entity := range entities {
+				entities[i] = renameFields(entity, names)
+			}
+			return json.Marshal(object)
+		}
+	}
+	return json.Marshal(renameFields(v, names))
+}
+
+// renameFields renames the keys of v, if it is a decoded JSON object,
+// found in names. The values of its fields are left as is.
+func renameFields(v interface{}, names map[string]string) interface{} {
+	object, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	res := make(map[string]interface{}, len(object))
+	for k, field := range object {
+		if name, ok := names[k]; ok {
+			k = name
+		}
+		res[k] = field
+	}
+	return res
+}
@@ -0,0 +1,82 @@
+package kong
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lastBody decodes the body of the last request received by srv.
+func lastBody(t *testing.T, srv *fakeKong) map[string]interface{} {
+	t.Helper()
+	requests := srv.requests()
+	require.NotEmpty(t, requests)
+	var body map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(requests[len(requests)-1].body), &body))
+	return body
+}
+
+func TestWithFieldAliases(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"POST /services": {status: http.StatusCreated, body: `{"id": "s1", "name": "svc1", "host": "example.com",
+			"readTimeout": 1000, "created_at": 1700000000}`},
+		"GET /services": fakeList(`[{"id": "s1", "name": "svc1", "readTimeout": 1000}]`),
+	})
+
+	client, err := NewClientWithOptions(srv.URL,
+		WithFieldAliases(map[string]string{"read_timeout": "readTimeout"}))
+	require.NoError(t, err)
+
+	service, err := client.Services.Create(defaultCtx, &Service{
+		Name:        String("svc1"),
+		Host:        String("example.com"),
+		ReadTimeout: Int(1000),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name":        "svc1",
+		"host":        "example.com",
+		"readTimeout": float64(1000),
+	}, lastBody(t, srv))
+	assert.Equal(t, 1000, *service.ReadTimeout)
+	assert.Equal(t, 1700000000, *service.CreatedAt)
+
+	services, err := client.Services.ListAll(defaultCtx)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	assert.Equal(t, 1000, *services[0].ReadTimeout)
+
+	// fields are sent as is by default
+	client, err = NewClientWithOptions(srv.URL)
+	require.NoError(t, err)
+	_, err = client.Services.Create(defaultCtx, &Service{Name: String("svc1"), ReadTimeout: Int(1000)})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "svc1", "read_timeout": float64(1000)}, lastBody(t, srv))
+}
+
+func TestFieldAliasesTopLevelOnly(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"POST /plugins": {status: http.StatusCreated, body: `{"id": "p1", "name": "proxy-cache",
+			"config": {"redis": {"Port": 6379, "port": 6380}}}`},
+	})
+
+	client, err := NewClientWithOptions(srv.URL, WithFieldAliases(map[string]string{"port": "Port"}))
+	require.NoError(t, err)
+
+	plugin, err := client.Plugins.Create(defaultCtx, &Plugin{
+		Name:   String("proxy-cache"),
+		Config: Configuration{"redis": map[string]interface{}{"port": 6380}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name":   "proxy-cache",
+		"config": map[string]interface{}{"redis": map[string]interface{}{"port": float64(6380)}},
+	}, lastBody(t, srv))
+	assert.Equal(t, Configuration{"redis": map[string]interface{}{
+		"Port": float64(6379),
+		"port": float64(6380),
+	}}, plugin.Config)
+}
@@ -1,5 +1,10 @@
 package kong
 
+import (
+	"encoding/json"
+	"strings"
+)
+
 // Info represents the information concerning Kong.
 type Info struct {
 	Version       string                `json:"version,omitempty" yaml:"version,omitempty"`
@@ -7,8 +12,105 @@ type Info struct {
 }
 
 // RuntimeConfiguration represents the runtime configuration of Kong.
+// Fields which are not reported by a Kong version are left empty.
 type RuntimeConfiguration struct {
 	Database string `json:"database,omitempty" yaml:"database,omitempty"`
 	Portal   bool   `json:"portal,omitempty" yaml:"portal,omitempty"`
 	RBAC     string `json:"rbac,omitempty" yaml:"rbac,omitempty"`
+
+	Role                   string `json:"role,omitempty" yaml:"role,omitempty"`
+	NginxWorkerProcesses   string `json:"nginx_worker_processes,omitempty" yaml:"nginx_worker_processes,omitempty"`
+	NginxWorkerConnections string `json:"nginx_events_worker_connections,omitempty" yaml:"nginx_events_worker_connections,omitempty"` //nolint:lll
+	MemCacheSize           string `json:"mem_cache_size,omitempty" yaml:"mem_cache_size,omitempty"`
+	ClientBodyBufferSize   string `json:"client_body_buffer_size,omitempty" yaml:"client_body_buffer_size,omitempty"`
+
+	ProxyListeners  []Listener `json:"proxy_listeners,omitempty" yaml:"proxy_listeners,omitempty"`
+	StreamListeners []Listener `json:"stream_listeners,omitempty" yaml:"stream_listeners,omitempty"`
+	AdminListeners  []Listener `json:"admin_listeners,omitempty" yaml:"admin_listeners,omitempty"`
+	StatusListeners []Listener `json:"status_listeners,omitempty" yaml:"status_listeners,omitempty"`
+
+	PGHost     string `json:"pg_host,omitempty" yaml:"pg_host,omitempty"`
+	PGPort     int    `json:"pg_port,omitempty" yaml:"pg_port,omitempty"`
+	PGDatabase string `json:"pg_database,omitempty" yaml:"pg_database,omitempty"`
+	PGUser     string `json:"pg_user,omitempty" yaml:"pg_user,omitempty"`
+	PGSSL      bool   `json:"pg_ssl,omitempty" yaml:"pg_ssl,omitempty"`
+
+	SSLCipherSuite string `json:"ssl_cipher_suite,omitempty" yaml:"ssl_cipher_suite,omitempty"`
+	SSLCiphers     string `json:"ssl_ciphers,omitempty" yaml:"ssl_ciphers,omitempty"`
+	SSLProtocols   string `json:"ssl_protocols,omitempty" yaml:"ssl_protocols,omitempty"`
+
+	LoadedPlugins map[string]bool `json:"loaded_plugins,omitempty" yaml:"loaded_plugins,omitempty"`
+	LoadedVaults  map[string]bool `json:"loaded_vaults,omitempty" yaml:"loaded_vaults,omitempty"`
+}
+
+// runtimeConfigurationListeners are the keys of the listener lists of
+// RuntimeConfiguration.
+var runtimeConfigurationListeners = []string{
+	"proxy_listeners", "stream_listeners", "admin_listeners", "status_listeners",
+}
+
+// UnmarshalJSON implements custom JSON unmarshaling for this type which must
+// be done because the Kong Admin API will return empty objects when a list
+// is empty, e.g. "stream_listeners": {} when no stream_listen is configured.
+func (c *RuntimeConfiguration) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for _, key := range runtimeConfigurationListeners {
+		var empty map[string]json.RawMessage
+		if json.Unmarshal(fields[key], &empty) == nil && empty != nil && len(empty) == 0 {
+			delete(fields, key)
+		}
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	type runtimeConfiguration RuntimeConfiguration
+	return json.Unmarshal(data, (*runtimeConfiguration)(c))
+}
+
+// TLSPolicy is the TLS policy of the proxy listeners of Kong.
+type TLSPolicy struct {
+	// CipherSuite is the name of the cipher suite, e.g. "intermediate",
+	// or "custom" when Ciphers is set explicitly.
+	CipherSuite string
+	// Ciphers are the OpenSSL names of the enabled ciphers.
+	Ciphers []string
+	// Protocols are the enabled protocol versions, e.g. "TLSv1.2".
+	Protocols []string
+}
+
+// TLSPolicy returns the TLS policy of Kong, as configured by the
+// ssl_cipher_suite, ssl_ciphers and ssl_protocols properties.
+// Fields are left empty for properties which aren't reported by the
+// version of Kong.
+func (i *Info) TLSPolicy() TLSPolicy {
+	var policy TLSPolicy
+	if i == nil || i.Configuration == nil {
+		return policy
+	}
+	config := i.Configuration
+	policy.CipherSuite = config.SSLCipherSuite
+	for _, cipher := range strings.Split(config.SSLCiphers, ":") {
+		if cipher = strings.TrimSpace(cipher); cipher != "" {
+			policy.Ciphers = append(policy.Ciphers, cipher)
+		}
+	}
+	if protocols := strings.Fields(config.SSLProtocols); len(protocols) > 0 {
+		policy.Protocols = protocols
+	}
+	return policy
+}
+
+// Listener represents an address Kong listens on, as configured by
+// the proxy_listen, admin_listen and similar properties.
+type Listener struct {
+	Listener      string `json:"listener,omitempty" yaml:"listener,omitempty"`
+	IP            string `json:"ip,omitempty" yaml:"ip,omitempty"`
+	Port          int    `json:"port,omitempty" yaml:"port,omitempty"`
+	SSL           bool   `json:"ssl,omitempty" yaml:"ssl,omitempty"`
+	HTTP2         bool   `json:"http2,omitempty" yaml:"http2,omitempty"`
+	ProxyProtocol bool   `json:"proxy_protocol,omitempty" yaml:"proxy_protocol,omitempty"`
 }
@@ -1,6 +1,7 @@
 package kong
 
 import (
+	"os"
 	"reflect"
 	"testing"
 
@@ -49,3 +50,47 @@ func TestConvert(T *testing.T) {
 	assert.False(actual.Configuration.IsInMemory())
 	assert.True(actual.Configuration.IsRBACEnabled())
 }
+
+func TestInfoServiceConfiguration(t *testing.T) {
+	root, err := os.ReadFile("testdata/root.json")
+	require.NoError(t, err)
+	client := newFakeKongClient(t, newFakeKong(t, map[string]fakeResponse{"/": {body: string(root)}}))
+	info, err := client.Info.Get(defaultCtx)
+	require.NoError(t, err)
+	require.NotNil(t, info.Configuration)
+
+	config := info.Configuration
+	assert.Equal(t, "4", config.NginxWorkerProcesses)
+	assert.Equal(t, "auto", config.NginxWorkerConnections)
+	assert.Equal(t, "traditional", config.Role)
+	assert.Equal(t, "128m", config.MemCacheSize)
+	assert.Equal(t, []Listener{
+		{Listener: "0.0.0.0:8000", IP: "0.0.0.0", Port: 8000},
+		{Listener: "0.0.0.0:8443 ssl http2", IP: "0.0.0.0", Port: 8443, SSL: true, HTTP2: true},
+	}, config.ProxyListeners)
+	assert.Len(t, config.AdminListeners, 1)
+	assert.Empty(t, config.StreamListeners)
+	assert.Empty(t, config.StatusListeners)
+	assert.Equal(t, "postgres", config.PGHost)
+	assert.Equal(t, 5432, config.PGPort)
+	assert.Equal(t, map[string]bool{"key-auth": true, "rate-limiting": true}, config.LoadedPlugins)
+
+	assert.Equal(t, TLSPolicy{
+		CipherSuite: "intermediate",
+		Ciphers: []string{
+			"ECDHE-ECDSA-AES128-GCM-SHA256",
+			"ECDHE-RSA-AES128-GCM-SHA256",
+			"ECDHE-ECDSA-CHACHA20-POLY1305",
+		},
+		Protocols: []string{"TLSv1.2", "TLSv1.3"},
+	}, info.TLSPolicy())
+}
+
+func TestInfoTLSPolicyMissingFields(t *testing.T) {
+	info := &Info{Version: "1.4.0", Configuration: &RuntimeConfiguration{Database: "off"}}
+	assert.Equal(t, TLSPolicy{}, info.TLSPolicy())
+	assert.Equal(t, TLSPolicy{}, (&Info{}).TLSPolicy())
+
+	info.Configuration.SSLCipherSuite = "custom"
+	assert.Equal(t, TLSPolicy{CipherSuite: "custom"}, info.TLSPolicy())
+}
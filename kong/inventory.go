@@ -0,0 +1,173 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// inventoryEntityTypes are the entity types counted by Inventory.
+var inventoryEntityTypes = []string{
+	"services", "routes", "consumers", "plugins",
+	"upstreams", "targets", "certificates", "snis",
+}
+
+// inventoryWorkers is the number of entity types counted concurrently by
+// Inventory.
+const inventoryWorkers = 4
+
+// Inventory returns the number of services, routes, consumers, plugins,
+// upstreams, targets, certificates and SNIs in Kong, keyed by entity type.
+// On Kong Gateway Enterprise, the counts reported by the meta endpoint of
+// the workspace of the client are used. Other counts are computed by
+// listing the entities, counting several entity types concurrently.
+func (c *Client) Inventory(ctx context.Context) (map[string]int, error) {
+	totals, err := c.inventoryTotals(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]int, len(inventoryEntityTypes))
+	var missing []string
+	for _, entityType := range inventoryEntityTypes {
+		if count, ok := totals[entityType]; ok {
+			res[entityType] = count
+		} else {
+			missing = append(missing, entityType)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		lock     sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	queue := make(chan string)
+	for i := 0; i < inventoryWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entityType := range queue {
+				count, err := c.countEntityType(ctx, entityType)
+				lock.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("counting %s: %w", entityType, err)
+						cancel()
+					}
+				} else {
+					res[entityType] = count
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	for _, entityType := range missing {
+		queue <- entityType
+	}
+	close(queue)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return res, nil
+}
+
+// inventoryTotals returns the counts of entities reported by the meta
+// endpoint of the workspace of the client on Kong Gateway Enterprise, or
+// nil if they are not available.
+func (c *Client) inventoryTotals(ctx context.Context) (map[string]int, error) {
+	info, err := c.Root(ctx)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := c.workspaceMeta(ctx, info)
+	if err != nil || meta == nil {
+		return nil, err
+	}
+	return meta.Counts, nil
+}
+
+// workspaceMeta returns the metadata of the workspace of the client on
+// Kong Gateway Enterprise, or nil if it is not available. info is the
+// response of Root.
+func (c *Client) workspaceMeta(ctx context.Context, info map[string]interface{}) (*WorkspaceMeta, error) {
+	version, err := ParseSemanticVersion(VersionFromInfo(info))
+	if err != nil {
+		return nil, err
+	}
+	if !version.IsKongGatewayEnterprise() {
+		return nil, nil
+	}
+	ws := c.Workspace()
+	if ws == "" {
+		ws = DefaultWorkspaceName
+	}
+	meta, err := c.Workspaces.Meta(ctx, &ws)
+	if IsForbiddenErr(err) || IsNotFoundErr(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// countEntityType returns the number of entities of entityType, e.g.
+// "services", listing them.
+func (c *Client) countEntityType(ctx context.Context, entityType string) (int, error) {
+	if entityType == "targets" {
+		return c.countTargets(ctx)
+	}
+	return c.countEntities(ctx, "/"+entityType)
+}
+
+// countEntities returns the number of entities listed at endpoint.
+func (c *Client) countEntities(ctx context.Context, endpoint string) (int, error) {
+	var count int
+	err := c.listIDs(ctx, endpoint, func(string) { count++ })
+	return count, err
+}
+
+// countTargets returns the number of targets of all upstreams.
+func (c *Client) countTargets(ctx context.Context) (int, error) {
+	var upstreams []string
+	if err := c.listIDs(ctx, "/upstreams", func(id string) { upstreams = append(upstreams, id) }); err != nil {
+		return 0, err
+	}
+	var count int
+	for _, id := range upstreams {
+		n, err := c.countEntities(ctx, "/upstreams/"+id+"/targets")
+		if err != nil {
+			return 0, err
+		}
+		count += n
+	}
+	return count, nil
+}
+
+// listIDs calls fn with the ID of every entity listed at endpoint. Only
+// the IDs of entities are requested.
+func (c *Client) listIDs(ctx context.Context, endpoint string, fn func(id string)) error {
+	opt := &ListOpt{Size: pageSize, Fields: []string{"id"}}
+	for opt != nil {
+		var data []json.RawMessage
+		var err error
+		data, opt, err = c.list(ctx, endpoint, opt)
+		if err != nil {
+			return err
+		}
+		for _, object := range data {
+			var entity struct {
+				ID string `json:"id"`
+			}
+			if err := json.Unmarshal(object, &entity); err != nil {
+				return err
+			}
+			fn(entity.ID)
+		}
+	}
+	return nil
+}
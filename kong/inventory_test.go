@@ -0,0 +1,92 @@
+package kong
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newInventoryServer returns a fake Kong listing counts[endpoint] entities
+// at each endpoint, one entity per page.
+func newInventoryServer(t *testing.T, rootResponse string, counts map[string]int) *fakeKong {
+	t.Helper()
+	responses := map[string]fakeResponse{
+		"/": {body: rootResponse},
+		"/workspaces/default/meta": {body: `{"counts": {"services": 10, "routes": 20, "consumers": 30,
+			"plugins": 40, "upstreams": 1, "targets": 3, "certificates": 5}}`},
+	}
+	for path, count := range counts {
+		responses[path] = fakeResponse{body: `{"data": []}`}
+		name := strings.Trim(path, "/")
+		for i := 0; i < count; i++ {
+			key := path
+			if i > 0 {
+				key = fmt.Sprintf("%s?offset=%d", path, i)
+			}
+			next := "null"
+			if i+1 < count {
+				next = fmt.Sprintf(`"%d"`, i+1)
+			}
+			responses[key] = fakeResponse{body: fmt.Sprintf(`{"data": [{"id": "%s-%d"}], "offset": %s}`, name, i, next)}
+		}
+	}
+	return newFakeKong(t, responses)
+}
+
+func TestInventory(t *testing.T) {
+	srv := newInventoryServer(t, ossRootResponse, map[string]int{
+		"/services":                      3,
+		"/routes":                        5,
+		"/consumers":                     2,
+		"/plugins":                       4,
+		"/upstreams":                     2,
+		"/upstreams/upstreams-0/targets": 3,
+		"/upstreams/upstreams-1/targets": 1,
+		"/certificates":                  0,
+		"/snis":                          1,
+	})
+	client := newFakeKongClient(t, srv)
+
+	inventory, err := client.Inventory(defaultCtx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		"services":     3,
+		"routes":       5,
+		"consumers":    2,
+		"plugins":      4,
+		"upstreams":    2,
+		"targets":      4,
+		"certificates": 0,
+		"snis":         1,
+	}, inventory)
+}
+
+func TestInventoryEnterprise(t *testing.T) {
+	srv := newInventoryServer(t, enterpriseRootResponse, map[string]int{"/snis": 2})
+	client := newFakeKongClient(t, srv)
+
+	inventory, err := client.Inventory(defaultCtx)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{
+		"services":     10,
+		"routes":       20,
+		"consumers":    30,
+		"plugins":      40,
+		"upstreams":    1,
+		"targets":      3,
+		"certificates": 5,
+		"snis":         2,
+	}, inventory)
+	assert.Zero(t, srv.count("/services"), "counts reported by Kong are used")
+}
+
+func TestInventoryError(t *testing.T) {
+	srv := newInventoryServer(t, ossRootResponse, map[string]int{"/services": 1})
+	client := newFakeKongClient(t, srv)
+
+	_, err := client.Inventory(defaultCtx)
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"strings"
 )
 
 // ListOpt aids in paginating through list endpoints
@@ -20,13 +21,25 @@ type ListOpt struct {
 	// If true, tags are ANDed, meaning only entities
 	// matching each tag in the Tags array are listed.
 	MatchAllTags bool
+
+	// Fields restricts the fields returned for each entity, e.g.
+	// []string{"id", "name", "tags"}, to reduce the size of responses.
+	// It is sent as the fields query parameter of list requests only; Get
+	// methods always return full entities. It is left out for Kong
+	// versions older than 3.4, which return full entities instead.
+	Fields []string
 }
 
+// fieldSelectionRange is the range of Kong versions whose list endpoints
+// accept the fields query parameter.
+const fieldSelectionRange = ">=3.4.0"
+
 // qs is used to construct query string for list endpoints
 type qs struct {
 	Size   int    `url:"size,omitempty"`
 	Offset string `url:"offset,omitempty"`
 	Tags   string `url:"tags,omitempty"`
+	Fields string `url:"fields,omitempty"`
 }
 
 // list fetches a list of an entity in Kong.
@@ -35,6 +48,12 @@ func (c *Client) list(ctx context.Context,
 	endpoint string, opt *ListOpt,
 ) ([]json.RawMessage, *ListOpt, error) {
 	q := constructQueryString(opt)
+	if maxSize := c.MaxPageSize(); q.Size > maxSize {
+		q.Size = maxSize
+	}
+	if q.Fields != "" && !c.supportsFieldSelection(ctx) {
+		q.Fields = ""
+	}
 	req, err := c.NewRequest("GET", endpoint, &q, nil)
 	if err != nil {
 		return nil, nil, err
@@ -59,12 +78,38 @@ func (c *Client) list(ctx context.Context,
 			next.Size = opt.Size
 			next.Tags = opt.Tags
 			next.MatchAllTags = opt.MatchAllTags
+			next.Fields = opt.Fields
 		}
 	}
 
 	return list.Data, next, nil
 }
 
+// supportsFieldSelection reports whether the list endpoints of Kong accept
+// the fields query parameter. The answer is cached once the version of
+// Kong is known; fields aren't selected while it can't be retrieved.
+func (c *Client) supportsFieldSelection(ctx context.Context) bool {
+	c.fieldSelectionLock.Lock()
+	defer c.fieldSelectionLock.Unlock()
+	if c.fieldSelection != nil {
+		return *c.fieldSelection
+	}
+	info, err := c.Root(ctx)
+	if err != nil {
+		return false
+	}
+	version, err := ParseSemanticVersion(VersionFromInfo(info))
+	if err != nil {
+		return false
+	}
+	supported, err := version.Satisfies(fieldSelectionRange)
+	if err != nil {
+		return false
+	}
+	c.fieldSelection = &supported
+	return supported
+}
+
 func constructQueryString(opt *ListOpt) qs {
 	var q qs
 	if opt == nil {
@@ -85,6 +130,7 @@ func constructQueryString(opt *ListOpt) qs {
 		}
 	}
 	q.Tags = tagQS.String()
+	q.Fields = strings.Join(opt.Fields, ",")
 
 	return q
 }
@@ -1,8 +1,12 @@
 package kong
 
 import (
+	"net/url"
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_constructQueryString(t *testing.T) {
@@ -44,6 +48,11 @@ func Test_constructQueryString(t *testing.T) {
 			}},
 			qs{Tags: "tag1,tag2,tag3"},
 		},
+		{
+			"fields",
+			args{opt: &ListOpt{Fields: []string{"id", "name", "tags"}}},
+			qs{Fields: "id,name,tags"},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -54,3 +63,64 @@ func Test_constructQueryString(t *testing.T) {
 		})
 	}
 }
+
+func TestListWithFields(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/services":                  {body: `{"data": [{"id": "s1", "name": "foo"}], "offset": "next-page"}`},
+		"/services?offset=next-page": {body: `{"data": [{"id": "s2", "name": "bar"}], "offset": null}`},
+	})
+	// queries returns the query strings of the listings
+	queries := func() []url.Values {
+		var res []url.Values
+		for _, r := range srv.requests() {
+			if r.path == "/services" {
+				res = append(res, r.query)
+			}
+		}
+		return res
+	}
+
+	t.Run("fields are selected on Kong 3.4", func(t *testing.T) {
+		srv.reset()
+		srv.set("/", fakeResponse{body: `{"version": "3.4.0"}`})
+		client := newFakeKongClient(t, srv)
+
+		services, next, err := client.Services.List(defaultCtx, &ListOpt{
+			Size:   1,
+			Fields: []string{"id", "name"},
+		})
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		assert.Equal(t, []*Service{{ID: String("s1"), Name: String("foo")}}, services)
+		assert.Equal(t, []string{"id", "name"}, next.Fields)
+
+		services, next, err = client.Services.List(defaultCtx, next)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+		assert.Equal(t, []*Service{{ID: String("s2"), Name: String("bar")}}, services)
+
+		require.Len(t, queries(), 2)
+		for _, q := range queries() {
+			assert.Equal(t, "id,name", q.Get("fields"))
+		}
+		assert.Equal(t, 1, srv.count("/"), "the version of Kong is only retrieved once")
+	})
+
+	t.Run("fields are left out before Kong 3.4", func(t *testing.T) {
+		srv.reset()
+		srv.set("/", fakeResponse{body: `{"version": "3.3.1"}`})
+		client := newFakeKongClient(t, srv)
+
+		services, err := client.Services.ListAll(defaultCtx)
+		require.NoError(t, err)
+		assert.Len(t, services, 2)
+		_, _, err = client.Services.List(defaultCtx, &ListOpt{Fields: []string{"id"}})
+		require.NoError(t, err)
+
+		require.Len(t, queries(), 3)
+		for _, q := range queries() {
+			assert.NotContains(t, q, "fields")
+		}
+		assert.Equal(t, 1, srv.count("/"))
+	})
+}
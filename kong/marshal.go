@@ -0,0 +1,148 @@
+package kong
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+
+// MarshalEntityFull returns the JSON encoding of v, like json.Marshal,
+// except that the omitempty option of struct fields is ignored: every
+// field of v and of its nested structs is emitted, nil pointers, slices
+// and maps being encoded as null.
+// Fields tagged with `json:"-"` are still skipped and types implementing
+// json.Marshaler are encoded using their MarshalJSON method.
+func MarshalEntityFull(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := marshalFull(&buf, reflect.ValueOf(v)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func marshalFull(buf *bytes.Buffer, v reflect.Value) error {
+	if !v.IsValid() {
+		buf.WriteString("null")
+		return nil
+	}
+	if v.Type().Implements(jsonMarshalerType) {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return marshalStd(buf, v.Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		return marshalFull(buf, v.Elem())
+	case reflect.Struct:
+		return marshalFullStruct(buf, v)
+	case reflect.Slice:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			// []byte are base64 encoded
+			return marshalStd(buf, v.Interface())
+		}
+		fallthrough
+	case reflect.Array:
+		buf.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := marshalFull(buf, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	case reflect.Map:
+		if v.IsNil() {
+			buf.WriteString("null")
+			return nil
+		}
+		if v.Type().Key().Kind() != reflect.String {
+			return marshalStd(buf, v.Interface())
+		}
+		keys := v.MapKeys()
+		sortedKeys := make([]string, 0, len(keys))
+		for _, k := range keys {
+			sortedKeys = append(sortedKeys, k.String())
+		}
+		sort.Strings(sortedKeys)
+		buf.WriteByte('{')
+		for i, k := range sortedKeys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			if err := marshalStd(buf, k); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+			value := v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key()))
+			if err := marshalFull(buf, value); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	default:
+		return marshalStd(buf, v.Interface())
+	}
+}
+
+func marshalFullStruct(buf *bytes.Buffer, v reflect.Value) error {
+	t := v.Type()
+	buf.WriteByte('{')
+	first := true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		if !first {
+			buf.WriteByte(',')
+		}
+		first = false
+		if err := marshalStd(buf, name); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := marshalFull(buf, v.Field(i)); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+func marshalStd(buf *bytes.Buffer, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(b)
+	return nil
+}
@@ -0,0 +1,72 @@
+package kong
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalEntityFull(t *testing.T) {
+	service := &Service{
+		Name:     String("foo"),
+		Host:     String("example.com"),
+		Port:     Int(0),
+		Enabled:  Bool(false),
+		Tags:     []*string{},
+		Protocol: String("http"),
+	}
+
+	b, err := json.Marshal(service)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "foo", "host": "example.com", "port": 0, "enabled": false,
+		"protocol": "http"}`, string(b))
+
+	b, err = MarshalEntityFull(service)
+	require.NoError(t, err)
+	var indented bytes.Buffer
+	require.NoError(t, json.Indent(&indented, b, "", "  "))
+	golden, err := os.ReadFile("testdata/serviceFull.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), indented.String()+"\n")
+
+	// the result can be decoded back
+	var decoded Service
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, service, &decoded)
+}
+
+func TestMarshalEntityFullNested(t *testing.T) {
+	plugin := &Plugin{
+		Name:   String("key-auth"),
+		Config: Configuration{"key_names": []string{"apikey"}, "anonymous": nil},
+		Ordering: &PluginOrdering{
+			Before: PluginOrderingPhase{"access": {"rate-limiting"}},
+		},
+	}
+	b, err := MarshalEntityFull(plugin)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"created_at": null,
+		"id": null,
+		"name": "key-auth",
+		"instance_name": null,
+		"route": null,
+		"service": null,
+		"consumer": null,
+		"consumer_group": null,
+		"config": {"anonymous": null, "key_names": ["apikey"]},
+		"enabled": null,
+		"run_on": null,
+		"ordering": {"before": {"access": ["rate-limiting"]}, "after": null},
+		"protocols": null,
+		"tags": null
+	}`, string(b))
+
+	b, err = MarshalEntityFull(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(b))
+}
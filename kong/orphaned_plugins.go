@@ -0,0 +1,125 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+)
+
+// FindOrphanedPlugins returns the plugins scoped to a Service, Route or
+// Consumer which doesn't exist in Kong anymore.
+// Kong deletes the plugins of an entity along with it, but plugins may
+// still be left behind when the database is edited by other tools.
+func (c *Client) FindOrphanedPlugins(ctx context.Context) ([]*Plugin, error) {
+	plugins, err := c.Plugins.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var services, routes, consumers map[string]bool
+	var orphans []*Plugin
+	for _, p := range plugins {
+		orphaned := false
+		if p.Service != nil {
+			if services == nil {
+				if services, err = c.existingServices(ctx); err != nil {
+					return nil, err
+				}
+			}
+			orphaned = orphaned || !referenceExists(services, p.Service.ID, p.Service.Name)
+		}
+		if p.Route != nil {
+			if routes == nil {
+				if routes, err = c.existingRoutes(ctx); err != nil {
+					return nil, err
+				}
+			}
+			orphaned = orphaned || !referenceExists(routes, p.Route.ID, p.Route.Name)
+		}
+		if p.Consumer != nil {
+			if consumers == nil {
+				if consumers, err = c.existingConsumers(ctx); err != nil {
+					return nil, err
+				}
+			}
+			orphaned = orphaned || !referenceExists(consumers, p.Consumer.ID, p.Consumer.Username)
+		}
+		if orphaned {
+			orphans = append(orphans, p)
+		}
+	}
+	return orphans, nil
+}
+
+// DeleteOrphanedPlugins deletes the plugins returned by FindOrphanedPlugins
+// and returns them. If an error occurs, the plugins deleted until then are
+// returned along with the error.
+func (c *Client) DeleteOrphanedPlugins(ctx context.Context) ([]*Plugin, error) {
+	orphans, err := c.FindOrphanedPlugins(ctx)
+	if err != nil {
+		return nil, err
+	}
+	deleted := make([]*Plugin, 0, len(orphans))
+	for _, p := range orphans {
+		if err := c.Plugins.Delete(ctx, p.ID); err != nil && !IsNotFoundErr(err) {
+			return deleted, fmt.Errorf("deleting plugin '%s': %w", p.FriendlyName(), err)
+		}
+		deleted = append(deleted, p)
+	}
+	return deleted, nil
+}
+
+// referenceExists returns true if the entity referenced by id or name is
+// in existing.
+func referenceExists(existing map[string]bool, id, name *string) bool {
+	if id != nil {
+		return existing[*id]
+	}
+	if name != nil {
+		return existing[*name]
+	}
+	return true
+}
+
+func (c *Client) existingServices(ctx context.Context) (map[string]bool, error) {
+	services, err := c.Services.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]bool)
+	for _, s := range services {
+		addExisting(res, s.ID, s.Name)
+	}
+	return res, nil
+}
+
+func (c *Client) existingRoutes(ctx context.Context) (map[string]bool, error) {
+	routes, err := c.Routes.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]bool)
+	for _, r := range routes {
+		addExisting(res, r.ID, r.Name)
+	}
+	return res, nil
+}
+
+func (c *Client) existingConsumers(ctx context.Context) (map[string]bool, error) {
+	consumers, err := c.Consumers.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]bool)
+	for _, consumer := range consumers {
+		addExisting(res, consumer.ID, consumer.Username)
+	}
+	return res, nil
+}
+
+func addExisting(existing map[string]bool, keys ...*string) {
+	for _, key := range keys {
+		if key != nil {
+			existing[*key] = true
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package kong
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindOrphanedPlugins(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"DELETE *": {status: http.StatusNoContent},
+		"GET /plugins": fakeList(`[
+			{"id": "p1", "name": "key-auth", "service": {"id": "s1"}},
+			{"id": "p2", "name": "cors", "service": {"id": "s-deleted"}},
+			{"id": "p3", "name": "prometheus"}
+		]`),
+		"GET /services": fakeList(`[{"id": "s1", "name": "svc1"}]`),
+	})
+	client := newFakeKongClient(t, srv)
+
+	orphans, err := client.FindOrphanedPlugins(defaultCtx)
+	require.NoError(t, err)
+	require.Len(t, orphans, 1)
+	assert.Equal(t, "p2", *orphans[0].ID)
+	assert.Zero(t, srv.count("DELETE /plugins/p2"))
+
+	orphans, err = client.DeleteOrphanedPlugins(defaultCtx)
+	require.NoError(t, err)
+	require.Len(t, orphans, 1)
+	assert.Equal(t, 1, srv.count("DELETE /plugins/p2"))
+}
@@ -0,0 +1,602 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// PlanAction is the action to take on an entity to reconcile it.
+type PlanAction string
+
+const (
+	// PlanActionCreate creates an entity missing from Kong.
+	PlanActionCreate PlanAction = "create"
+	// PlanActionUpdate updates an entity which differs from its desired state.
+	PlanActionUpdate PlanAction = "update"
+	// PlanActionDelete deletes an entity which isn't desired anymore.
+	PlanActionDelete PlanAction = "delete"
+)
+
+// PlanChange describes a change to apply to a single entity.
+type PlanChange struct {
+	Action PlanAction
+	// EntityType is the Admin API endpoint of the entity, e.g. "services".
+	EntityType string
+	// Key is the natural key identifying the entity, e.g. the name of
+	// a Service or the username of a Consumer.
+	Key string
+	// Desired is the entity to create, or the entity to update Current
+	// to, in which case it holds the ID of Current. It is nil for deletes.
+	Desired interface{}
+	// Current is the entity as it exists in Kong. It is nil for creates.
+	Current interface{}
+	// Fields holds the dotted paths of the fields which differ between
+	// Desired and Current, for updates.
+	Fields []string
+}
+
+// Plan lists the changes needed to reconcile the entities in Kong with
+// a desired configuration. Each list is in dependency order: parents are
+// created and updated before their children, and deleted after them.
+type Plan struct {
+	Creates []PlanChange
+	Updates []PlanChange
+	Deletes []PlanChange
+}
+
+// IsEmpty returns true if the Plan has no change.
+func (p *Plan) IsEmpty() bool {
+	return len(p.Creates) == 0 && len(p.Updates) == 0 && len(p.Deletes) == 0
+}
+
+// planEntityTypes are the entity types handled by Plan, in dependency order.
+var planEntityTypes = []string{
+	"services",
+	"routes",
+	"consumers",
+	"consumer_groups",
+	"upstreams",
+	"targets",
+	"plugins",
+}
+
+// planManagedFields are fields set by Kong which are never diffed.
+var planManagedFields = []string{"id", "created_at", "updated_at"}
+
+// planEntity is an entity along with its natural key.
+type planEntity struct {
+	key    string
+	entity interface{}
+}
+
+// planKeys maps the IDs of entities to their natural key, per entity type.
+type planKeys map[string]map[string]string
+
+func (k planKeys) set(entityType string, id *string, key string) {
+	if id == nil {
+		return
+	}
+	if k[entityType] == nil {
+		k[entityType] = make(map[string]string)
+	}
+	k[entityType][*id] = key
+}
+
+// ref returns the natural key of an entity referenced by name or ID.
+func (k planKeys) ref(entityType string, name, id *string) string {
+	if name != nil {
+		return *name
+	}
+	if id == nil {
+		return ""
+	}
+	if key, ok := k[entityType][*id]; ok {
+		return key
+	}
+	return *id
+}
+
+func planNameOrID(name, id *string) string {
+	if name != nil {
+		return *name
+	}
+	if id != nil {
+		return *id
+	}
+	return ""
+}
+
+// Plan reads the entities in Kong and computes the changes needed to
+// reconcile them with desired. Entities are matched using their natural
+// key: the name of Services, Routes, Upstreams and Consumer Groups, the
+// username (or custom_id) of Consumers, the upstream and target of Targets,
+// and the name and scope of Plugins. Consumer Groups, only supported by
+// Kong Gateway Enterprise, are planned along with their members and
+// plugin overrides, see ApplyPlan. Entities in Kong which aren't in desired are
+// deleted. If desired has SelectTags, the entities in Kong which don't
+// have all of them are ignored: they are neither updated nor deleted.
+// The SelectTags are added to the desired entities missing them, and
+// desired entities matching an entity in Kong outside of this scope are
+// reported as conflicts.
+// Entities are updated only if a field set in desired differs from Kong,
+// see EqualIgnoringDefaults, so that defaults filled by Kong don't cause
+// no-op updates. Route paths are compared once normalized for the version
+// of Kong, see NormalizeRoutePathForCompare.
+func (c *Client) Plan(ctx context.Context, desired *Content) (*Plan, error) {
+	if desired == nil {
+		return nil, fmt.Errorf("desired content cannot be nil")
+	}
+	version, err := c.planVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	current, err := c.currentContent(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// current entities are indexed first so that desired entities can
+	// refer to them by ID
+	keys := planKeys{}
+	currentEntities := planEntities(current, keys)
+	unselected := make(map[string]map[string]bool)
+	if len(desired.SelectTags) > 0 {
+		for entityType, entities := range currentEntities {
+			selected, others := withSelectTags(entities, desired.SelectTags)
+			unselected[entityType] = make(map[string]bool, len(others))
+			for _, e := range others {
+				unselected[entityType][e.key] = true
+			}
+			currentEntities[entityType] = selected
+		}
+	}
+	desiredEntities := planEntities(desired, keys)
+	if len(desired.SelectTags) > 0 {
+		for entityType, entities := range desiredEntities {
+			for i, e := range entities {
+				if unselected[entityType][e.key] {
+					return nil, fmt.Errorf("%s '%s' exists in Kong without the select tags %s",
+						entityType, e.key, strings.Join(desired.SelectTags, ", "))
+				}
+				entities[i].entity = withTags(e.entity, desired.SelectTags)
+			}
+		}
+	}
+
+	var plan Plan
+	for _, entityType := range planEntityTypes {
+		existing := make(map[string]interface{})
+		for _, e := range currentEntities[entityType] {
+			existing[e.key] = e.entity
+		}
+		seen := make(map[string]bool)
+		for _, e := range desiredEntities[entityType] {
+			if seen[e.key] {
+				return nil, fmt.Errorf("duplicate %s '%s' in desired content", entityType, e.key)
+			}
+			seen[e.key] = true
+
+			cur, ok := existing[e.key]
+			if !ok {
+				plan.Creates = append(plan.Creates, PlanChange{
+					Action:     PlanActionCreate,
+					EntityType: entityType,
+					Key:        e.key,
+					Desired:    e.entity,
+				})
+				continue
+			}
+			fields, err := planDiff(entityType, e.entity, cur, keys, version)
+			if err != nil {
+				return nil, err
+			}
+			if len(fields) > 0 {
+				plan.Updates = append(plan.Updates, PlanChange{
+					Action:     PlanActionUpdate,
+					EntityType: entityType,
+					Key:        e.key,
+					Desired:    withEntityID(e.entity, entityID(cur)),
+					Current:    cur,
+					Fields:     fields,
+				})
+			}
+		}
+		for _, e := range currentEntities[entityType] {
+			if !seen[e.key] {
+				plan.Deletes = append(plan.Deletes, PlanChange{
+					Action:     PlanActionDelete,
+					EntityType: entityType,
+					Key:        e.key,
+					Current:    e.entity,
+				})
+			}
+		}
+	}
+
+	// children are deleted before their parents
+	for i, j := 0, len(plan.Deletes)-1; i < j; i, j = i+1, j-1 {
+		plan.Deletes[i], plan.Deletes[j] = plan.Deletes[j], plan.Deletes[i]
+	}
+	return &plan, nil
+}
+
+// withSelectTags splits entities between the ones having all the
+// selectTags and the others.
+func withSelectTags(entities []planEntity, selectTags []string) (selected, others []planEntity) {
+	for _, e := range entities {
+		tags := entityTags(e.entity)
+		hasAll := true
+		for _, tag := range selectTags {
+			if !containsTag(tags, tag) {
+				hasAll = false
+				break
+			}
+		}
+		if hasAll {
+			selected = append(selected, e)
+		} else {
+			others = append(others, e)
+		}
+	}
+	return selected, others
+}
+
+// withTags returns a copy of entity having all the tags, which are
+// appended to the tags of entity missing them.
+func withTags(entity interface{}, tags []string) interface{} {
+	res := withEntityID(entity, entityID(entity))
+	resTags := append([]*string(nil), entityTags(res)...)
+	for _, tag := range tags {
+		if !containsTag(resTags, tag) {
+			resTags = append(resTags, String(tag))
+		}
+	}
+	setEntityTags(res, resTags)
+	return res
+}
+
+// planVersion returns the version of Kong, defaulting to the first
+// version requiring explicit regex paths if Kong doesn't report a version.
+func (c *Client) planVersion(ctx context.Context) (Version, error) {
+	info, err := c.Root(ctx)
+	if err != nil {
+		return Version{}, err
+	}
+	if version, ok := info["version"].(string); ok {
+		if v, err := ParseSemanticVersion(version); err == nil {
+			return v, nil
+		}
+	}
+	return minExplicitRegexPathVersion, nil
+}
+
+// currentContent fetches the entities handled by Plan from Kong.
+func (c *Client) currentContent(ctx context.Context) (*Content, error) {
+	var content Content
+	var err error
+	if content.Services, err = c.Services.ListAll(ctx); err != nil {
+		return nil, err
+	}
+	if content.Routes, err = c.Routes.ListAll(ctx); err != nil {
+		return nil, err
+	}
+	if content.Consumers, err = c.Consumers.ListAll(ctx); err != nil {
+		return nil, err
+	}
+	if content.Upstreams, err = c.Upstreams.ListAll(ctx); err != nil {
+		return nil, err
+	}
+	for _, u := range content.Upstreams {
+		targets, err := c.Targets.ListAll(ctx, u.ID)
+		if err != nil {
+			return nil, err
+		}
+		content.Targets = append(content.Targets, targets...)
+	}
+	// Kong OSS answers 404 here; the members and overrides of a group are
+	// only returned along with it
+	groups, err := c.ConsumerGroups.ListAll(ctx)
+	if err != nil && !IsNotFoundErr(err) {
+		return nil, err
+	}
+	for _, group := range groups {
+		obj, err := c.ConsumerGroups.Get(ctx, group.ID)
+		if err != nil {
+			return nil, err
+		}
+		content.ConsumerGroups = append(content.ConsumerGroups, obj)
+	}
+	if content.Plugins, err = c.Plugins.ListAll(ctx); err != nil {
+		return nil, err
+	}
+	return &content, nil
+}
+
+// planEntities returns the entities of content along with their natural
+// keys, per entity type. The IDs of the entities are recorded in keys so
+// that references by ID can be resolved.
+func planEntities(content *Content, keys planKeys) map[string][]planEntity {
+	res := make(map[string][]planEntity)
+	add := func(entityType string, id *string, key string, entity interface{}) {
+		keys.set(entityType, id, key)
+		res[entityType] = append(res[entityType], planEntity{key: key, entity: entity})
+	}
+
+	for _, s := range content.Services {
+		add("services", s.ID, planNameOrID(s.Name, s.ID), s)
+	}
+	for _, r := range content.Routes {
+		add("routes", r.ID, planNameOrID(r.Name, r.ID), r)
+	}
+	for _, c := range content.Consumers {
+		key := planNameOrID(c.Username, c.CustomID)
+		if key == "" {
+			key = planNameOrID(nil, c.ID)
+		}
+		add("consumers", c.ID, key, c)
+	}
+	for _, u := range content.Upstreams {
+		add("upstreams", u.ID, planNameOrID(u.Name, u.ID), u)
+	}
+	for _, t := range content.Targets {
+		var upstream string
+		if t.Upstream != nil {
+			upstream = keys.ref("upstreams", t.Upstream.Name, t.Upstream.ID)
+		}
+		add("targets", t.ID, upstream+"/"+planNameOrID(t.Target, nil), t)
+	}
+	for _, g := range content.ConsumerGroups {
+		if g == nil {
+			continue
+		}
+		var id *string
+		if g.ConsumerGroup != nil {
+			id = g.ConsumerGroup.ID
+		}
+		add("consumer_groups", id, planNameOrID(consumerGroupName(g), id), g)
+	}
+	for _, p := range content.Plugins {
+		key := planNameOrID(p.Name, nil)
+		if p.InstanceName != nil {
+			key = *p.InstanceName
+		}
+		if p.Service != nil {
+			key += " service:" + keys.ref("services", p.Service.Name, p.Service.ID)
+		}
+		if p.Route != nil {
+			key += " route:" + keys.ref("routes", p.Route.Name, p.Route.ID)
+		}
+		if p.Consumer != nil {
+			key += " consumer:" + keys.ref("consumers", p.Consumer.Username, p.Consumer.ID)
+		}
+		if p.ConsumerGroup != nil {
+			key += " consumer_group:" + keys.ref("consumer_groups", p.ConsumerGroup.Name, p.ConsumerGroup.ID)
+		}
+		add("plugins", p.ID, key, p)
+	}
+	return res
+}
+
+// planDiff returns the fields of desired which differ in current.
+// References to other entities are compared using their natural keys.
+func planDiff(entityType string, desired, current interface{}, keys planKeys,
+	version Version,
+) ([]string, error) {
+	if d, ok := desired.(*ConsumerGroupObject); ok {
+		return consumerGroupDiff(d, current.(*ConsumerGroupObject), keys)
+	}
+	// references of targets and plugins are part of their natural key
+	ignore := append([]string{"service", "route", "consumer", "consumer_group", "upstream"},
+		planManagedFields...)
+	fields, err := changedFields(desired, current, ignore)
+	if err != nil {
+		return nil, fmt.Errorf("comparing %s: %w", entityType, err)
+	}
+	if d, ok := desired.(*Route); ok && d.Paths != nil &&
+		routePathsEqual(d.Paths, current.(*Route).Paths, version) {
+		fields = removeString(fields, "paths")
+	}
+	if d, ok := desired.(*Route); ok && d.Service != nil {
+		c := current.(*Route)
+		if c.Service == nil || keys.ref("services", d.Service.Name, d.Service.ID) !=
+			keys.ref("services", c.Service.Name, c.Service.ID) {
+			fields = append(fields, "service")
+			sort.Strings(fields)
+		}
+	}
+	return fields, nil
+}
+
+// consumerGroupDiff returns the fields of the Consumer Group of desired
+// which differ in current, along with "consumers" if their members differ
+// and "plugins" if their plugin overrides differ. The members and the
+// overrides of desired are the exact ones wanted: a group without any is
+// emptied. Overrides are compared ignoring the config fields desired
+// leaves unset.
+func consumerGroupDiff(desired, current *ConsumerGroupObject, keys planKeys) ([]string, error) {
+	var fields []string
+	if desired.ConsumerGroup != nil && current.ConsumerGroup != nil {
+		var err error
+		fields, err = changedFields(desired.ConsumerGroup, current.ConsumerGroup, planManagedFields)
+		if err != nil {
+			return nil, fmt.Errorf("comparing consumer_groups: %w", err)
+		}
+	}
+
+	members := func(group *ConsumerGroupObject) map[string]bool {
+		res := make(map[string]bool, len(group.Consumers))
+		for _, c := range group.Consumers {
+			res[keys.ref("consumers", c.Username, c.ID)] = true
+		}
+		return res
+	}
+	if !reflect.DeepEqual(members(desired), members(current)) {
+		fields = append(fields, "consumers")
+	}
+
+	overrides := make(map[string]*ConsumerGroupPlugin, len(current.Plugins))
+	for _, p := range current.Plugins {
+		overrides[planNameOrID(p.Name, nil)] = p
+	}
+	pluginsChanged := len(desired.Plugins) != len(current.Plugins)
+	for _, p := range desired.Plugins {
+		cur, ok := overrides[planNameOrID(p.Name, nil)]
+		if !ok {
+			pluginsChanged = true
+			break
+		}
+		changed, err := changedFields(p, cur, append([]string{"consumer_group"}, planManagedFields...))
+		if err != nil {
+			return nil, fmt.Errorf("comparing consumer_groups: %w", err)
+		}
+		if len(changed) > 0 {
+			pluginsChanged = true
+			break
+		}
+	}
+	if pluginsChanged {
+		fields = append(fields, "plugins")
+	}
+	sort.Strings(fields)
+	return fields, nil
+}
+
+func consumerGroupName(group *ConsumerGroupObject) *string {
+	if group.ConsumerGroup == nil {
+		return nil
+	}
+	return group.ConsumerGroup.Name
+}
+
+func removeString(values []string, value string) []string {
+	res := values[:0]
+	for _, v := range values {
+		if v != value {
+			res = append(res, v)
+		}
+	}
+	return res
+}
+
+func entityID(entity interface{}) *string {
+	switch e := entity.(type) {
+	case *Service:
+		return e.ID
+	case *Route:
+		return e.ID
+	case *Consumer:
+		return e.ID
+	case *Upstream:
+		return e.ID
+	case *Target:
+		return e.ID
+	case *Plugin:
+		return e.ID
+	case *ConsumerGroupObject:
+		if e.ConsumerGroup != nil {
+			return e.ConsumerGroup.ID
+		}
+	}
+	return nil
+}
+
+// withEntityID returns a copy of entity using id.
+func withEntityID(entity interface{}, id *string) interface{} {
+	switch e := entity.(type) {
+	case *Service:
+		res := e.DeepCopy()
+		res.ID = id
+		return res
+	case *Route:
+		res := e.DeepCopy()
+		res.ID = id
+		return res
+	case *Consumer:
+		res := e.DeepCopy()
+		res.ID = id
+		return res
+	case *Upstream:
+		res := e.DeepCopy()
+		res.ID = id
+		return res
+	case *Target:
+		res := e.DeepCopy()
+		res.ID = id
+		return res
+	case *Plugin:
+		res := e.DeepCopy()
+		res.ID = id
+		return res
+	case *ConsumerGroupObject:
+		res := e.DeepCopy()
+		if res.ConsumerGroup != nil {
+			res.ConsumerGroup.ID = id
+		}
+		return res
+	}
+	return entity
+}
+
+// EqualIgnoringDefaults reports whether every field set in desired has the
+// same value in current. Fields which desired leaves unset, such as fields
+// Kong fills with their defaults, are ignored, as are the fields managed
+// by Kong (id, created_at and updated_at). Nested objects, such as plugin
+// configs, are compared the same way; arrays must be equal.
+// desired and current are compared using their JSON representation and
+// are never equal if either can't be encoded.
+func EqualIgnoringDefaults(desired, current interface{}) bool {
+	fields, err := changedFields(desired, current, planManagedFields)
+	return err == nil && len(fields) == 0
+}
+
+// changedFields returns the sorted dotted paths of the fields set in
+// desired which differ in current, skipping the top-level fields in ignore.
+func changedFields(desired, current interface{}, ignore []string) ([]string, error) {
+	d, err := toJSONObject(desired)
+	if err != nil {
+		return nil, err
+	}
+	c, err := toJSONObject(current)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range ignore {
+		delete(d, field)
+	}
+	fields := diffJSONObjects(d, c, "")
+	sort.Strings(fields)
+	return fields, nil
+}
+
+func toJSONObject(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var res map[string]interface{}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func diffJSONObjects(desired, current map[string]interface{}, prefix string) []string {
+	var res []string
+	for k, dv := range desired {
+		cv := current[k]
+		if dm, ok := dv.(map[string]interface{}); ok {
+			if cm, ok := cv.(map[string]interface{}); ok {
+				res = append(res, diffJSONObjects(dm, cm, prefix+k+".")...)
+				continue
+			}
+		}
+		if !reflect.DeepEqual(dv, cv) {
+			res = append(res, prefix+k)
+		}
+	}
+	return res
+}
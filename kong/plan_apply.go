@@ -0,0 +1,365 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ApplyOpt configures ApplyPlan.
+type ApplyOpt struct {
+	// Rollback reverts the changes applied by ApplyPlan if one of the
+	// changes fails. Entities deleted by Kong along with a deleted entity
+	// are not restored, see ApplyPlan.
+	Rollback bool
+}
+
+// ApplyError is returned by ApplyPlan when a change fails.
+type ApplyError struct {
+	// Change is the change which failed.
+	Change PlanChange
+	// Err is the error returned by Kong for Change.
+	Err error
+	// Applied is the number of changes applied before Change.
+	Applied int
+	// RollbackErrors holds the errors which occurred while reverting the
+	// applied changes, if a rollback was requested.
+	RollbackErrors []error
+}
+
+func (e *ApplyError) Error() string {
+	msg := fmt.Sprintf("%s %s '%s': %v", e.Change.Action, e.Change.EntityType, e.Change.Key, e.Err)
+	if len(e.RollbackErrors) == 0 {
+		return msg
+	}
+	errs := make([]string, 0, len(e.RollbackErrors))
+	for _, err := range e.RollbackErrors {
+		errs = append(errs, err.Error())
+	}
+	return fmt.Sprintf("%s (rollback failed: %s)", msg, strings.Join(errs, "; "))
+}
+
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
+
+// ApplyPlan applies the changes of plan in order: creates, then updates,
+// then deletes. It stops at the first change which fails and returns an
+// *ApplyError.
+// If opt.Rollback is set, the changes applied until then are reverted, in
+// reverse order: created entities are deleted, updated entities are
+// updated back to their state captured in the plan and deleted entities
+// are recreated with their original ID. Rollback is best-effort: errors
+// which occur while reverting are reported in ApplyError.RollbackErrors
+// and don't stop the rollback.
+// Rollback only reverts the changes of the plan. Kong deletes some
+// entities along with the ones they depend on, e.g. the credentials and
+// the plugins of a Consumer or the Targets of an Upstream; those which
+// aren't deleted by the plan itself are lost and are not recreated along
+// with the entity they belonged to, so the state of Kong after a rollback
+// may differ from the one before ApplyPlan.
+// Consumer Groups are created before their members and their plugin
+// overrides, in this order. Updating a Consumer Group adds and removes
+// members and sets and deletes overrides so that they match the desired
+// ones; deleting it deletes its memberships and overrides along with it.
+func (c *Client) ApplyPlan(ctx context.Context, plan *Plan, opt *ApplyOpt) error {
+	if plan == nil {
+		return fmt.Errorf("plan cannot be nil")
+	}
+	var changes []PlanChange
+	changes = append(changes, plan.Creates...)
+	changes = append(changes, plan.Updates...)
+	changes = append(changes, plan.Deletes...)
+
+	// undo holds the changes reverting the applied changes
+	var undo []PlanChange
+	for i, change := range changes {
+		revert, err := c.applyChange(ctx, change)
+		if err == nil {
+			undo = append(undo, revert)
+			continue
+		}
+
+		applyErr := &ApplyError{Change: change, Err: err, Applied: i}
+		if opt != nil && opt.Rollback {
+			for j := len(undo) - 1; j >= 0; j-- {
+				if _, err := c.applyChange(ctx, undo[j]); err != nil {
+					applyErr.RollbackErrors = append(applyErr.RollbackErrors,
+						fmt.Errorf("reverting %s %s '%s': %w",
+							changes[j].Action, changes[j].EntityType, changes[j].Key, err))
+				}
+			}
+		}
+		return applyErr
+	}
+	return nil
+}
+
+// applyChange applies change and returns the change reverting it.
+func (c *Client) applyChange(ctx context.Context, change PlanChange) (PlanChange, error) {
+	revert := PlanChange{EntityType: change.EntityType, Key: change.Key}
+	switch change.Action {
+	case PlanActionCreate:
+		created, err := c.createEntity(ctx, change.Desired)
+		if err != nil {
+			return revert, err
+		}
+		revert.Action, revert.Current = PlanActionDelete, created
+	case PlanActionUpdate:
+		updated, err := c.updateEntity(ctx, change.Desired)
+		if err != nil {
+			return revert, err
+		}
+		revert.Action, revert.Current = PlanActionUpdate, updated
+		revert.Desired = withoutTimestamps(change.Current)
+	case PlanActionDelete:
+		if err := c.deleteEntity(ctx, change.Current); err != nil {
+			return revert, err
+		}
+		revert.Action, revert.Desired = PlanActionCreate, withoutTimestamps(change.Current)
+	default:
+		return revert, fmt.Errorf("unknown plan action: '%s'", change.Action)
+	}
+	return revert, nil
+}
+
+// withoutTimestamps returns a copy of entity without the timestamps
+// managed by Kong, so that it can be sent back to Kong.
+func withoutTimestamps(entity interface{}) interface{} {
+	res := withEntityID(entity, entityID(entity))
+	switch e := res.(type) {
+	case *Service:
+		e.CreatedAt, e.UpdatedAt = nil, nil
+	case *Route:
+		e.CreatedAt, e.UpdatedAt = nil, nil
+	case *Consumer:
+		e.CreatedAt = nil
+	case *Upstream:
+		e.CreatedAt = nil
+	case *Target:
+		e.CreatedAt = nil
+	case *Plugin:
+		e.CreatedAt = nil
+	case *ConsumerGroupObject:
+		if e.ConsumerGroup != nil {
+			e.ConsumerGroup.CreatedAt = nil
+		}
+		for _, plugin := range e.Plugins {
+			plugin.CreatedAt = nil
+		}
+	}
+	return res
+}
+
+func targetUpstream(t *Target) (*string, error) {
+	if t.Upstream == nil {
+		return nil, fmt.Errorf("target '%s' has no upstream", t.FriendlyName())
+	}
+	if t.Upstream.ID != nil {
+		return t.Upstream.ID, nil
+	}
+	return t.Upstream.Name, nil
+}
+
+func (c *Client) createEntity(ctx context.Context, entity interface{}) (interface{}, error) {
+	switch e := entity.(type) {
+	case *Service:
+		return c.Services.Create(ctx, e)
+	case *Route:
+		return c.Routes.Create(ctx, e)
+	case *Consumer:
+		return c.Consumers.Create(ctx, e)
+	case *Upstream:
+		return c.Upstreams.Create(ctx, e)
+	case *Target:
+		upstream, err := targetUpstream(e)
+		if err != nil {
+			return nil, err
+		}
+		return c.Targets.Create(ctx, upstream, e)
+	case *Plugin:
+		return c.Plugins.Create(ctx, e)
+	case *ConsumerGroupObject:
+		return c.createConsumerGroupObject(ctx, e)
+	}
+	return nil, fmt.Errorf("unsupported entity type: %T", entity)
+}
+
+func (c *Client) updateEntity(ctx context.Context, entity interface{}) (interface{}, error) {
+	switch e := entity.(type) {
+	case *Service:
+		return c.Services.Update(ctx, e)
+	case *Route:
+		return c.Routes.Update(ctx, e)
+	case *Consumer:
+		return c.Consumers.Update(ctx, e)
+	case *Upstream:
+		return c.Upstreams.Update(ctx, e)
+	case *Target:
+		upstream, err := targetUpstream(e)
+		if err != nil {
+			return nil, err
+		}
+		return c.Targets.Upsert(ctx, upstream, e)
+	case *Plugin:
+		return c.Plugins.Update(ctx, e)
+	case *ConsumerGroupObject:
+		return c.updateConsumerGroupObject(ctx, e)
+	}
+	return nil, fmt.Errorf("unsupported entity type: %T", entity)
+}
+
+func (c *Client) deleteEntity(ctx context.Context, entity interface{}) error {
+	switch e := entity.(type) {
+	case *Service:
+		return c.Services.Delete(ctx, e.ID)
+	case *Route:
+		return c.Routes.Delete(ctx, e.ID)
+	case *Consumer:
+		return c.Consumers.Delete(ctx, e.ID)
+	case *Upstream:
+		return c.Upstreams.Delete(ctx, e.ID)
+	case *Target:
+		upstream, err := targetUpstream(e)
+		if err != nil {
+			return err
+		}
+		return c.Targets.Delete(ctx, upstream, e.ID)
+	case *Plugin:
+		return c.Plugins.Delete(ctx, e.ID)
+	case *ConsumerGroupObject:
+		// the members and overrides of the group are deleted along with it
+		if e.ConsumerGroup == nil {
+			return fmt.Errorf("consumer group cannot be empty")
+		}
+		return c.ConsumerGroups.Delete(ctx, e.ConsumerGroup.ID)
+	}
+	return fmt.Errorf("unsupported entity type: %T", entity)
+}
+
+// createConsumerGroupObject creates the Consumer Group of group, then adds
+// its members, referred to by username or ID, and sets its plugin
+// overrides, in this order.
+func (c *Client) createConsumerGroupObject(ctx context.Context,
+	group *ConsumerGroupObject,
+) (*ConsumerGroupObject, error) {
+	if group == nil || group.ConsumerGroup == nil {
+		return nil, fmt.Errorf("consumer group cannot be empty")
+	}
+	created, err := c.ConsumerGroups.Create(ctx, group.ConsumerGroup.DeepCopy())
+	if err != nil {
+		return nil, err
+	}
+	res := &ConsumerGroupObject{ConsumerGroup: created}
+	for _, member := range group.Consumers {
+		if err := c.addConsumerGroupMember(ctx, created, member); err != nil {
+			return nil, err
+		}
+		res.Consumers = append(res.Consumers, member)
+	}
+	for _, plugin := range group.Plugins {
+		set, err := c.setConsumerGroupPlugin(ctx, created, plugin)
+		if err != nil {
+			return nil, err
+		}
+		res.Plugins = append(res.Plugins, set)
+	}
+	return res, nil
+}
+
+// updateConsumerGroupObject updates the Consumer Group of group, which
+// must have an ID, then adds and removes members and sets and deletes
+// plugin overrides so that they match the ones of group.
+func (c *Client) updateConsumerGroupObject(ctx context.Context,
+	group *ConsumerGroupObject,
+) (*ConsumerGroupObject, error) {
+	if group == nil || group.ConsumerGroup == nil {
+		return nil, fmt.Errorf("consumer group cannot be empty")
+	}
+	updated, err := c.ConsumerGroups.Update(ctx, group.ConsumerGroup.DeepCopy())
+	if err != nil {
+		return nil, err
+	}
+	current, err := c.ConsumerGroups.Get(ctx, updated.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &ConsumerGroupObject{ConsumerGroup: updated}
+	for _, member := range group.Consumers {
+		if !containsConsumer(current.Consumers, member) {
+			if err := c.addConsumerGroupMember(ctx, updated, member); err != nil {
+				return nil, err
+			}
+		}
+		res.Consumers = append(res.Consumers, member)
+	}
+	for _, member := range current.Consumers {
+		if containsConsumer(group.Consumers, member) {
+			continue
+		}
+		if err := c.ConsumerGroupConsumers.Delete(ctx, updated.ID, member.ID); err != nil {
+			return nil, fmt.Errorf("removing consumer '%s' from consumer group '%s': %w",
+				member.FriendlyName(), updated.FriendlyName(), err)
+		}
+	}
+	overrides := make(map[string]bool)
+	for _, plugin := range group.Plugins {
+		set, err := c.setConsumerGroupPlugin(ctx, updated, plugin)
+		if err != nil {
+			return nil, err
+		}
+		overrides[*plugin.Name] = true
+		res.Plugins = append(res.Plugins, set)
+	}
+	for _, plugin := range current.Plugins {
+		if plugin.Name == nil || overrides[*plugin.Name] {
+			continue
+		}
+		if err := c.ConsumerGroups.DeletePlugin(ctx, updated.ID, plugin.Name); err != nil {
+			return nil, fmt.Errorf("deleting plugin override '%s' of consumer group '%s': %w",
+				*plugin.Name, updated.FriendlyName(), err)
+		}
+	}
+	return res, nil
+}
+
+func (c *Client) addConsumerGroupMember(ctx context.Context, group *ConsumerGroup, member *Consumer) error {
+	ref := planNameOrID(member.Username, member.ID)
+	if ref == "" {
+		return fmt.Errorf("consumer reference cannot be empty in consumer group '%s'",
+			group.FriendlyName())
+	}
+	if _, err := c.ConsumerGroupConsumers.Create(ctx, group.ID, &ref); err != nil {
+		return fmt.Errorf("adding consumer '%s' to consumer group '%s': %w",
+			ref, group.FriendlyName(), err)
+	}
+	return nil
+}
+
+// setConsumerGroupPlugin sets the plugin override of group, without
+// modifying plugin.
+func (c *Client) setConsumerGroupPlugin(ctx context.Context, group *ConsumerGroup,
+	plugin *ConsumerGroupPlugin,
+) (*ConsumerGroupPlugin, error) {
+	plugin = plugin.DeepCopy()
+	plugin.ID, plugin.CreatedAt, plugin.ConsumerGroup = nil, nil, nil
+	set, err := c.ConsumerGroups.SetPlugin(ctx, group.ID, plugin)
+	if err != nil {
+		return nil, fmt.Errorf("setting plugin override '%s' of consumer group '%s': %w",
+			planNameOrID(plugin.Name, nil), group.FriendlyName(), err)
+	}
+	return set, nil
+}
+
+// containsConsumer reports whether consumers holds consumer, matched by
+// ID or by username.
+func containsConsumer(consumers []*Consumer, consumer *Consumer) bool {
+	for _, c := range consumers {
+		if (c.ID != nil && consumer.ID != nil && *c.ID == *consumer.ID) ||
+			(c.Username != nil && consumer.Username != nil && *c.Username == *consumer.Username) {
+			return true
+		}
+	}
+	return false
+}
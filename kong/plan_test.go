@@ -0,0 +1,424 @@
+package kong
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPlanServer returns a fake Kong serving the listing endpoints of the
+// entities handled by Plan. Endpoints missing from lists return no entity.
+// JSON objects are served as is, e.g. a Consumer Group along with its
+// members.
+func newPlanServer(t *testing.T, lists map[string]string) *fakeKong {
+	t.Helper()
+	responses := map[string]fakeResponse{
+		"GET *": fakeList("[]"),
+		"*":     {status: http.StatusMethodNotAllowed},
+	}
+	for path, data := range lists {
+		if strings.HasPrefix(strings.TrimSpace(data), "{") {
+			responses["GET "+path] = fakeResponse{body: data}
+			continue
+		}
+		responses["GET "+path] = fakeList(data)
+	}
+	return newFakeKong(t, responses)
+}
+
+func TestPlan(t *testing.T) {
+	srv := newPlanServer(t, map[string]string{
+		"/services": `[
+			{"id": "s1", "name": "svc1", "host": "old.example.com", "port": 80, "protocol": "http", "retries": 5},
+			{"id": "s2", "name": "svc2", "host": "example.com", "port": 80, "protocol": "http", "retries": 5}
+		]`,
+		"/routes": `[
+			{"id": "r1", "name": "route1", "paths": ["/foo"], "service": {"id": "s1"},
+				"strip_path": true, "protocols": ["http", "https"]}
+		]`,
+		"/consumers": `[{"id": "c1", "username": "alice", "created_at": 1}]`,
+		"/plugins": `[
+			{"id": "p1", "name": "key-auth", "service": {"id": "s1"},
+				"config": {"key_names": ["apikey"], "hide_credentials": false}}
+		]`,
+	})
+	client := newFakeKongClient(t, srv)
+
+	desired := &Content{
+		Services: []*Service{
+			{Name: String("svc1"), Host: String("new.example.com")},
+			{Name: String("svc2"), Host: String("example.com"), Port: Int(80)},
+		},
+		Routes: []*Route{
+			{Name: String("route1"), Paths: StringSlice("/foo"), Service: &Service{Name: String("svc1")}},
+			{Name: String("route2"), Paths: StringSlice("/bar"), Service: &Service{Name: String("svc2")}},
+		},
+		Consumers: []*Consumer{
+			{Username: String("alice")},
+		},
+		Plugins: []*Plugin{
+			{
+				Name:    String("key-auth"),
+				Service: &Service{Name: String("svc1")},
+				Config:  Configuration{"key_names": []string{"apikey"}},
+			},
+		},
+	}
+
+	plan, err := client.Plan(defaultCtx, desired)
+	require.NoError(t, err)
+	assert.False(t, plan.IsEmpty())
+
+	require.Len(t, plan.Creates, 1)
+	assert.Equal(t, PlanChange{
+		Action:     PlanActionCreate,
+		EntityType: "routes",
+		Key:        "route2",
+		Desired:    desired.Routes[1],
+	}, plan.Creates[0])
+
+	require.Len(t, plan.Updates, 1)
+	update := plan.Updates[0]
+	assert.Equal(t, PlanActionUpdate, update.Action)
+	assert.Equal(t, "services", update.EntityType)
+	assert.Equal(t, "svc1", update.Key)
+	assert.Equal(t, []string{"host"}, update.Fields)
+	assert.Equal(t, "s1", *update.Desired.(*Service).ID)
+	assert.Equal(t, "new.example.com", *update.Desired.(*Service).Host)
+	assert.Equal(t, "old.example.com", *update.Current.(*Service).Host)
+	assert.Nil(t, desired.Services[0].ID, "desired content must not be modified")
+
+	assert.Empty(t, plan.Deletes)
+}
+
+func TestPlanDeletes(t *testing.T) {
+	srv := newPlanServer(t, map[string]string{
+		"/services": `[{"id": "s1", "name": "svc1", "host": "example.com"}]`,
+		"/routes":   `[{"id": "r1", "name": "route1", "paths": ["/foo"], "service": {"id": "s1"}}]`,
+		"/plugins":  `[{"id": "p1", "name": "cors", "route": {"id": "r1"}}]`,
+	})
+	client := newFakeKongClient(t, srv)
+
+	plan, err := client.Plan(defaultCtx, &Content{})
+	require.NoError(t, err)
+	assert.Empty(t, plan.Creates)
+	assert.Empty(t, plan.Updates)
+	require.Len(t, plan.Deletes, 3)
+	assert.Equal(t, "plugins", plan.Deletes[0].EntityType)
+	assert.Equal(t, "cors route:route1", plan.Deletes[0].Key)
+	assert.Equal(t, "routes", plan.Deletes[1].EntityType)
+	assert.Equal(t, "services", plan.Deletes[2].EntityType)
+
+	plan, err = client.Plan(defaultCtx, &Content{
+		Services: []*Service{{Name: String("svc1"), Host: String("example.com")}},
+		Routes: []*Route{
+			// trailing slashes are ignored
+			{Name: String("route1"), Paths: StringSlice("/foo/"), Service: &Service{ID: String("s1")}},
+		},
+		Plugins: []*Plugin{{Name: String("cors"), Route: &Route{Name: String("route1")}}},
+	})
+	require.NoError(t, err)
+	assert.True(t, plan.IsEmpty())
+
+	_, err = client.Plan(defaultCtx, &Content{
+		Services: []*Service{{Name: String("svc1")}, {Name: String("svc1")}},
+	})
+	assert.Error(t, err)
+}
+
+func TestPlanConsumerGroups(t *testing.T) {
+	srv := newPlanServer(t, map[string]string{
+		"/consumers":       `[{"id": "c1", "username": "alice"}, {"id": "c2", "username": "bob"}]`,
+		"/consumer_groups": `[{"id": "cg1", "name": "gold"}, {"id": "cg2", "name": "silver"}]`,
+		"/consumer_groups/cg1": `{
+			"consumer_group": {"id": "cg1", "name": "gold", "created_at": 1},
+			"consumers": [{"id": "c1", "username": "alice", "created_at": 1}],
+			"plugins": [{"id": "o1", "name": "rate-limiting-advanced",
+				"config": {"limit": [10], "window_size": [60], "window_type": "sliding"}}]
+		}`,
+		"/consumer_groups/cg2": `{"consumer_group": {"id": "cg2", "name": "silver"}}`,
+		"/plugins": `[
+			{"id": "p1", "name": "rate-limiting", "consumer_group": {"id": "cg1"}, "config": {"minute": 10}}
+		]`,
+	})
+	client := newFakeKongClient(t, srv)
+
+	consumers := []*Consumer{{Username: String("alice")}, {Username: String("bob")}}
+	gold := &ConsumerGroupObject{
+		ConsumerGroup: &ConsumerGroup{Name: String("gold")},
+		Consumers:     []*Consumer{{Username: String("alice")}},
+		Plugins: []*ConsumerGroupPlugin{{
+			Name:   String("rate-limiting-advanced"),
+			Config: Configuration{"limit": []int{10}, "window_size": []int{60}},
+		}},
+	}
+	silver := &ConsumerGroupObject{ConsumerGroup: &ConsumerGroup{Name: String("silver")}}
+	// plugins refer to their consumer group by name or by ID
+	plugins := []*Plugin{{
+		Name:          String("rate-limiting"),
+		ConsumerGroup: &ConsumerGroup{Name: String("gold")},
+		Config:        Configuration{"minute": 10},
+	}}
+
+	plan, err := client.Plan(defaultCtx, &Content{
+		Consumers:      consumers,
+		ConsumerGroups: []*ConsumerGroupObject{gold, silver},
+		Plugins:        plugins,
+	})
+	require.NoError(t, err)
+	assert.True(t, plan.IsEmpty())
+
+	platinum := &ConsumerGroupObject{
+		ConsumerGroup: &ConsumerGroup{Name: String("platinum")},
+		Consumers:     []*Consumer{{Username: String("bob")}},
+	}
+	gold.Consumers = []*Consumer{{Username: String("alice")}, {Username: String("bob")}}
+	gold.Plugins = nil
+	plan, err = client.Plan(defaultCtx, &Content{
+		Consumers:      consumers,
+		ConsumerGroups: []*ConsumerGroupObject{gold, platinum},
+		Plugins:        plugins,
+	})
+	require.NoError(t, err)
+	require.Len(t, plan.Creates, 1)
+	assert.Equal(t, "consumer_groups", plan.Creates[0].EntityType)
+	assert.Equal(t, "platinum", plan.Creates[0].Key)
+	require.Len(t, plan.Updates, 1)
+	assert.Equal(t, "gold", plan.Updates[0].Key)
+	assert.Equal(t, []string{"consumers", "plugins"}, plan.Updates[0].Fields)
+	assert.Equal(t, "cg1", *plan.Updates[0].Desired.(*ConsumerGroupObject).ConsumerGroup.ID)
+	require.Len(t, plan.Deletes, 1)
+	assert.Equal(t, "silver", plan.Deletes[0].Key)
+}
+
+func TestPlanSelectTags(t *testing.T) {
+	srv := newPlanServer(t, map[string]string{
+		"/services": `[
+			{"id": "s1", "name": "svc1", "host": "example.com", "tags": ["team-a"]},
+			{"id": "s2", "name": "svc2", "host": "example.com", "tags": ["team-b"]},
+			{"id": "s3", "name": "svc3", "host": "example.com"}
+		]`,
+	})
+	client := newFakeKongClient(t, srv)
+
+	plan, err := client.Plan(defaultCtx, &Content{SelectTags: []string{"team-a"}})
+	require.NoError(t, err)
+	assert.Empty(t, plan.Creates)
+	assert.Empty(t, plan.Updates)
+	require.Len(t, plan.Deletes, 1)
+	assert.Equal(t, "svc1", plan.Deletes[0].Key)
+
+	desired := &Content{
+		SelectTags: []string{"team-b"},
+		Services: []*Service{
+			{Name: String("svc2"), Host: String("example.com"), Tags: StringSlice("team-b")},
+		},
+	}
+	plan, err = client.Plan(defaultCtx, desired)
+	require.NoError(t, err)
+	assert.True(t, plan.IsEmpty())
+
+	desired = &Content{
+		SelectTags: []string{"team-b"},
+		Services: []*Service{
+			{Name: String("svc2"), Host: String("example.com")},
+			{Name: String("svc4"), Host: String("example.com"), Tags: StringSlice("new")},
+		},
+	}
+	plan, err = client.Plan(defaultCtx, desired)
+	require.NoError(t, err)
+	assert.Empty(t, plan.Updates)
+	require.Len(t, plan.Creates, 1)
+	assert.Equal(t, StringSlice("new", "team-b"), plan.Creates[0].Desired.(*Service).Tags)
+	assert.Equal(t, StringSlice("new"), desired.Services[1].Tags, "desired content is left as is")
+
+	_, err = client.Plan(defaultCtx, &Content{
+		SelectTags: []string{"team-b"},
+		Services:   []*Service{{Name: String("svc3"), Host: String("example.com")}},
+	})
+	assert.EqualError(t, err, "services 'svc3' exists in Kong without the select tags team-b")
+}
+
+func TestEqualIgnoringDefaults(t *testing.T) {
+	current := &Plugin{
+		ID:        String("p1"),
+		CreatedAt: Int(1),
+		Name:      String("rate-limiting"),
+		Enabled:   Bool(true),
+		Config:    Configuration{"minute": 10, "policy": "local", "redis": map[string]interface{}{"port": 6379}},
+		Protocols: StringSlice("http", "https"),
+	}
+
+	assert.True(t, EqualIgnoringDefaults(&Plugin{Name: String("rate-limiting")}, current))
+	assert.True(t, EqualIgnoringDefaults(&Plugin{
+		ID:     String("other"),
+		Name:   String("rate-limiting"),
+		Config: Configuration{"minute": 10, "redis": map[string]interface{}{}},
+	}, current))
+	assert.False(t, EqualIgnoringDefaults(&Plugin{
+		Name:   String("rate-limiting"),
+		Config: Configuration{"minute": 20},
+	}, current))
+	assert.False(t, EqualIgnoringDefaults(&Plugin{
+		Name:   String("rate-limiting"),
+		Config: Configuration{"redis": map[string]interface{}{"port": 6380}},
+	}, current))
+	assert.False(t, EqualIgnoringDefaults(&Plugin{Protocols: StringSlice("http")}, current))
+	assert.False(t, EqualIgnoringDefaults(&Plugin{Enabled: Bool(false)}, current))
+}
+
+func TestApplyPlanConsumerGroups(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"DELETE *":                            {status: http.StatusNoContent},
+		"POST /consumer_groups":               {status: http.StatusCreated, body: `{"id": "cg3", "name": "platinum"}`},
+		"POST *":                              {status: http.StatusCreated, body: `{}`},
+		"GET /schemas/consumer_group_plugins": {body: `{"fields": []}`},
+		"PUT *":                               {body: `{"plugin": "rate-limiting-advanced", "config": {"limit": [5]}}`},
+		"PATCH *":                             {body: `{"id": "cg1", "name": "gold"}`},
+		"GET /consumer_groups/cg1": {body: `{
+			"consumer_group": {"id": "cg1", "name": "gold"},
+			"consumers": [{"id": "c1", "username": "alice"}],
+			"plugins": [{"name": "rate-limiting-advanced", "config": {"limit": [10]}}]
+		}`},
+	})
+	client := newFakeKongClient(t, srv)
+
+	plan := &Plan{
+		Creates: []PlanChange{{
+			Action:     PlanActionCreate,
+			EntityType: "consumer_groups",
+			Key:        "platinum",
+			Desired: &ConsumerGroupObject{
+				ConsumerGroup: &ConsumerGroup{Name: String("platinum")},
+				Consumers:     []*Consumer{{Username: String("alice")}},
+				Plugins: []*ConsumerGroupPlugin{{
+					Name:   String("rate-limiting-advanced"),
+					Config: Configuration{"limit": []int{5}},
+				}},
+			},
+		}},
+		Updates: []PlanChange{{
+			Action:     PlanActionUpdate,
+			EntityType: "consumer_groups",
+			Key:        "gold",
+			Desired: &ConsumerGroupObject{
+				ConsumerGroup: &ConsumerGroup{ID: String("cg1"), Name: String("gold")},
+				Consumers:     []*Consumer{{Username: String("bob")}},
+			},
+			Fields: []string{"consumers", "plugins"},
+		}},
+		Deletes: []PlanChange{{
+			Action:     PlanActionDelete,
+			EntityType: "consumer_groups",
+			Key:        "silver",
+			Current:    &ConsumerGroupObject{ConsumerGroup: &ConsumerGroup{ID: String("cg2"), Name: String("silver")}},
+		}},
+	}
+	require.NoError(t, client.ApplyPlan(defaultCtx, plan, nil))
+	// groups are created before their members and their overrides
+	assert.Equal(t, []string{
+		`POST /consumer_groups {"name":"platinum"}`,
+		`POST /consumer_groups/cg3/consumers {"consumer":["alice"]}`,
+		`GET /schemas/consumer_group_plugins`,
+		`PUT /consumer_groups/cg3/overrides/plugins/rate-limiting-advanced {"config":{"limit":[5]}}`,
+		`PATCH /consumer_groups/cg1 {"id":"cg1","name":"gold"}`,
+		`GET /consumer_groups/cg1`,
+		`POST /consumer_groups/cg1/consumers {"consumer":["bob"]}`,
+		`DELETE /consumer_groups/cg1/consumers/c1`,
+		`DELETE /consumer_groups/cg1/overrides/plugins/rate-limiting-advanced`,
+		`DELETE /consumer_groups/cg2`,
+	}, srv.calls())
+}
+
+func TestApplyPlan(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"DELETE /consumers/c2": {
+			status: http.StatusInternalServerError,
+			body:   `{"message": "An unexpected error occurred"}`,
+		},
+		"DELETE *":           {status: http.StatusNoContent},
+		"POST /routes":       {status: http.StatusCreated, body: `{"id": "r2", "name": "route2"}`},
+		"PATCH /services/s1": {body: `{"id": "s1", "name": "svc1"}`},
+		"PUT /consumers/c1":  {body: `{"id": "c1", "username": "bob"}`},
+	})
+	client := newFakeKongClient(t, srv)
+
+	newPlan := func() *Plan {
+		return &Plan{
+			Creates: []PlanChange{{
+				Action:     PlanActionCreate,
+				EntityType: "routes",
+				Key:        "route2",
+				Desired:    &Route{Name: String("route2"), Service: &Service{Name: String("svc1")}},
+			}},
+			Updates: []PlanChange{{
+				Action:     PlanActionUpdate,
+				EntityType: "services",
+				Key:        "svc1",
+				Desired:    &Service{ID: String("s1"), Name: String("svc1"), Host: String("new")},
+				Current: &Service{
+					ID: String("s1"), Name: String("svc1"), Host: String("old"),
+					CreatedAt: Int(1), UpdatedAt: Int(2),
+				},
+				Fields: []string{"host"},
+			}},
+			Deletes: []PlanChange{
+				{
+					Action:     PlanActionDelete,
+					EntityType: "consumers",
+					Key:        "bob",
+					Current:    &Consumer{ID: String("c1"), Username: String("bob")},
+				},
+				{
+					Action:     PlanActionDelete,
+					EntityType: "consumers",
+					Key:        "carol",
+					Current:    &Consumer{ID: String("c2"), Username: String("carol")},
+				},
+			},
+		}
+	}
+	applied := []string{
+		`POST /routes {"name":"route2","service":{"name":"svc1"}}`,
+		`PATCH /services/s1 {"host":"new","id":"s1","name":"svc1"}`,
+		`DELETE /consumers/c1`,
+		`DELETE /consumers/c2`,
+	}
+
+	t.Run("without rollback", func(t *testing.T) {
+		srv.reset()
+		err := client.ApplyPlan(defaultCtx, newPlan(), nil)
+		var applyErr *ApplyError
+		require.ErrorAs(t, err, &applyErr)
+		assert.Equal(t, "carol", applyErr.Change.Key)
+		assert.Equal(t, 3, applyErr.Applied)
+		assert.Empty(t, applyErr.RollbackErrors)
+		assert.Equal(t, http.StatusInternalServerError, applyErr.Err.(*APIError).Code())
+		assert.Equal(t, applied, srv.calls())
+	})
+
+	t.Run("with rollback", func(t *testing.T) {
+		srv.reset()
+		err := client.ApplyPlan(defaultCtx, newPlan(), &ApplyOpt{Rollback: true})
+		var applyErr *ApplyError
+		require.ErrorAs(t, err, &applyErr)
+		assert.Equal(t, "carol", applyErr.Change.Key)
+		assert.Empty(t, applyErr.RollbackErrors)
+		assert.Equal(t, append(applied,
+			`PUT /consumers/c1 {"id":"c1","username":"bob"}`,
+			`PATCH /services/s1 {"host":"old","id":"s1","name":"svc1"}`,
+			`DELETE /routes/r2`,
+		), srv.calls())
+	})
+
+	t.Run("success", func(t *testing.T) {
+		srv.reset()
+		plan := newPlan()
+		plan.Deletes = plan.Deletes[:1]
+		require.NoError(t, client.ApplyPlan(defaultCtx, plan, &ApplyOpt{Rollback: true}))
+		assert.Equal(t, applied[:3], srv.calls())
+	})
+}
@@ -1,22 +1,27 @@
 package kong
 
+import "fmt"
+
 // Plugin represents a Plugin in Kong.
 // Read https://docs.konghq.com/gateway/latest/admin-api/#plugin-object
 // +k8s:deepcopy-gen=true
 type Plugin struct {
-	CreatedAt    *int            `json:"created_at,omitempty" yaml:"created_at,omitempty"`
-	ID           *string         `json:"id,omitempty" yaml:"id,omitempty"`
-	Name         *string         `json:"name,omitempty" yaml:"name,omitempty"`
-	InstanceName *string         `json:"instance_name,omitempty" yaml:"instance_name,omitempty"`
-	Route        *Route          `json:"route,omitempty" yaml:"route,omitempty"`
-	Service      *Service        `json:"service,omitempty" yaml:"service,omitempty"`
-	Consumer     *Consumer       `json:"consumer,omitempty" yaml:"consumer,omitempty"`
-	Config       Configuration   `json:"config,omitempty" yaml:"config,omitempty"`
-	Enabled      *bool           `json:"enabled,omitempty" yaml:"enabled,omitempty"`
-	RunOn        *string         `json:"run_on,omitempty" yaml:"run_on,omitempty"`
-	Ordering     *PluginOrdering `json:"ordering,omitempty" yaml:"ordering,omitempty"`
-	Protocols    []*string       `json:"protocols,omitempty" yaml:"protocols,omitempty"`
-	Tags         []*string       `json:"tags,omitempty" yaml:"tags,omitempty"`
+	CreatedAt    *int      `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	ID           *string   `json:"id,omitempty" yaml:"id,omitempty"`
+	Name         *string   `json:"name,omitempty" yaml:"name,omitempty"`
+	InstanceName *string   `json:"instance_name,omitempty" yaml:"instance_name,omitempty"`
+	Route        *Route    `json:"route,omitempty" yaml:"route,omitempty"`
+	Service      *Service  `json:"service,omitempty" yaml:"service,omitempty"`
+	Consumer     *Consumer `json:"consumer,omitempty" yaml:"consumer,omitempty"`
+	// ConsumerGroup scopes the Plugin to the consumers of a consumer group.
+	// This is only supported by Kong Gateway Enterprise.
+	ConsumerGroup *ConsumerGroup  `json:"consumer_group,omitempty" yaml:"consumer_group,omitempty"`
+	Config        Configuration   `json:"config,omitempty" yaml:"config,omitempty"`
+	Enabled       *bool           `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	RunOn         *string         `json:"run_on,omitempty" yaml:"run_on,omitempty"`
+	Ordering      *PluginOrdering `json:"ordering,omitempty" yaml:"ordering,omitempty"`
+	Protocols     []*string       `json:"protocols,omitempty" yaml:"protocols,omitempty"`
+	Tags          []*string       `json:"tags,omitempty" yaml:"tags,omitempty"`
 }
 
 // PluginOrdering contains before or after instructions for plugin execution order
@@ -45,3 +50,37 @@ func (p *Plugin) FriendlyName() string {
 	}
 	return ""
 }
+
+// ValidateScope returns the problems with the entities a Plugin is scoped
+// to which Kong rejects:
+//   - a reference to a Service, Route, Consumer or Consumer Group without
+//     an ID or a name,
+//   - a Plugin scoped to both a Consumer and a Consumer Group.
+//
+// It returns nil if the scope of the Plugin is valid. A Plugin without a
+// scope is global.
+func (p *Plugin) ValidateScope() []string {
+	if p == nil {
+		return nil
+	}
+	var problems []string
+	missingRef := func(field string) {
+		problems = append(problems, fmt.Sprintf("'%s' must have an id or a name", field))
+	}
+	if p.Service != nil && isEmptyString(p.Service.ID) && isEmptyString(p.Service.Name) {
+		missingRef("service")
+	}
+	if p.Route != nil && isEmptyString(p.Route.ID) && isEmptyString(p.Route.Name) {
+		missingRef("route")
+	}
+	if p.Consumer != nil && isEmptyString(p.Consumer.ID) && isEmptyString(p.Consumer.Username) {
+		missingRef("consumer")
+	}
+	if p.ConsumerGroup != nil && isEmptyString(p.ConsumerGroup.ID) && isEmptyString(p.ConsumerGroup.Name) {
+		missingRef("consumer_group")
+	}
+	if p.Consumer != nil && p.ConsumerGroup != nil {
+		problems = append(problems, "'consumer' and 'consumer_group' can't both be set")
+	}
+	return problems
+}
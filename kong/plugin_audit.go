@@ -0,0 +1,150 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/tidwall/gjson"
+)
+
+// PluginIssue describes a problem found in the stored configuration
+// of a Plugin.
+type PluginIssue struct {
+	// Plugin is the plugin in which the issue was found.
+	Plugin *Plugin
+	// Field is the dotted path of the offending field within the
+	// plugin's config, e.g. "redis.host".
+	Field string
+	// Message is a human readable description of the issue.
+	Message string
+}
+
+// AuditPlugins fetches all Plugins in Kong and reports the fields that
+// are marked as required in the plugin's schema but that are missing or
+// null in its stored config.
+// Each plugin schema is fetched at most once per call.
+func (c *Client) AuditPlugins(ctx context.Context) ([]PluginIssue, error) {
+	plugins, err := c.Plugins.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	configSchemas := make(map[string]gjson.Result)
+	var issues []PluginIssue
+	for _, p := range plugins {
+		if isEmptyString(p.Name) {
+			continue
+		}
+		configSchema, ok := configSchemas[*p.Name]
+		if !ok {
+			configSchema, err = c.pluginConfigSchema(ctx, p.Name)
+			if err != nil {
+				return nil, err
+			}
+			configSchemas[*p.Name] = configSchema
+		}
+		for _, field := range missingRequiredFields(configSchema, p.Config, "") {
+			issues = append(issues, PluginIssue{
+				Plugin:  p,
+				Field:   field,
+				Message: fmt.Sprintf("required field '%s' is missing or null", field),
+			})
+		}
+	}
+	return issues, nil
+}
+
+// CheckDeprecatedPluginConfig returns the dotted paths of the keys of the
+// config of p which are not fields of the config schema of the plugin, as
+// reported by Kong, e.g. "redis_host" for a rate-limiting plugin of a Kong
+// version which moved it to "redis.host". Such keys are likely deprecated,
+// removed or misspelled, and may be ignored by Kong.
+// Records which are present in the config are inspected recursively.
+func (c *Client) CheckDeprecatedPluginConfig(ctx context.Context, p *Plugin) ([]string, error) {
+	if p == nil || isEmptyString(p.Name) {
+		return nil, fmt.Errorf("plugin name cannot be empty")
+	}
+	configSchema, err := c.pluginConfigSchema(ctx, p.Name)
+	if err != nil {
+		return nil, err
+	}
+	unknown := unknownFields(configSchema, p.Config, "")
+	sort.Strings(unknown)
+	return unknown, nil
+}
+
+// pluginConfigSchema fetches the schema of the config of the plugin name.
+func (c *Client) pluginConfigSchema(ctx context.Context, name *string) (gjson.Result, error) {
+	schema, err := c.Plugins.GetFullSchema(ctx, name)
+	if err != nil {
+		return gjson.Result{}, fmt.Errorf("fetching schema for plugin %s: %w", *name, err)
+	}
+	jsonb, err := json.Marshal(&schema)
+	if err != nil {
+		return gjson.Result{}, err
+	}
+	configSchema, err := getConfigSchema(gjson.ParseBytes(jsonb))
+	if err != nil {
+		return gjson.Result{}, fmt.Errorf("plugin %s: %w", *name, err)
+	}
+	return configSchema, nil
+}
+
+// unknownFields returns the dotted paths of the keys of config which are
+// not fields of the record schema.
+func unknownFields(schema gjson.Result, config map[string]interface{}, prefix string) []string {
+	fields := make(map[string]gjson.Result)
+	schema.Get("fields").ForEach(func(_, value gjson.Result) bool {
+		for k, field := range value.Map() {
+			fields[k] = field
+		}
+		return true
+	})
+	var res []string
+	for k, v := range config {
+		field, ok := fields[k]
+		if !ok {
+			res = append(res, prefix+k)
+			continue
+		}
+		if field.Get("type").String() == "record" {
+			if subConfig, ok := v.(map[string]interface{}); ok {
+				res = append(res, unknownFields(field, subConfig, prefix+k+".")...)
+			}
+		}
+	}
+	return res
+}
+
+// missingRequiredFields walks a record schema and returns the dotted paths
+// of required fields which are absent or null in config.
+// Records which are present in config are inspected recursively.
+func missingRequiredFields(schema gjson.Result, config map[string]interface{}, prefix string) []string {
+	var res []string
+	schema.Get("fields").ForEach(func(_, value gjson.Result) bool {
+		fname := ""
+		for k := range value.Map() {
+			fname = k
+			break
+		}
+		field := value.Get(fname)
+		path := prefix + fname
+
+		v, ok := config[fname]
+		if !ok || v == nil {
+			if field.Get("required").Bool() {
+				res = append(res, path)
+			}
+			return true
+		}
+		if field.Get("type").String() == "record" {
+			if subConfig, ok := v.(map[string]interface{}); ok {
+				res = append(res, missingRequiredFields(field, subConfig, path+".")...)
+			}
+		}
+		return true
+	})
+	return res
+}
@@ -0,0 +1,93 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const auditRateLimitingSchema = `{
+	"fields": [
+		{
+			"config": {
+				"type": "record",
+				"fields": [
+					{ "minute": { "type": "number" } },
+					{ "policy": { "type": "string", "required": true, "default": "local" } },
+					{
+						"redis": {
+							"type": "record",
+							"fields": [
+								{ "host": { "type": "string", "required": true } },
+								{ "port": { "type": "integer", "default": 6379 } }
+							]
+						}
+					}
+				]
+			}
+		}
+	]
+}`
+
+func TestAuditPlugins(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/plugins": fakeList(`[
+			{"id": "p1", "name": "rate-limiting", "config": {"minute": 10, "policy": "local", "redis": {"host": "redis", "port": 6379}}},
+			{"id": "p2", "name": "rate-limiting", "config": {"minute": 10, "policy": null, "redis": {"port": 6379}}}
+		]`),
+		"/schemas/plugins/rate-limiting": {body: auditRateLimitingSchema},
+	})
+	client := newFakeKongClient(t, srv)
+
+	issues, err := client.AuditPlugins(defaultCtx)
+	require.NoError(t, err)
+	require.Len(t, issues, 2)
+
+	assert.Equal(t, "p2", *issues[0].Plugin.ID)
+	assert.Equal(t, "policy", issues[0].Field)
+	assert.Equal(t, "p2", *issues[1].Plugin.ID)
+	assert.Equal(t, "redis.host", issues[1].Field)
+	assert.NotEmpty(t, issues[1].Message)
+}
+
+func TestAuditPluginsSchemaError(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/plugins": fakeList(`[{"id": "p1", "name": "unknown"}]`),
+	})
+	client := newFakeKongClient(t, srv)
+
+	issues, err := client.AuditPlugins(defaultCtx)
+	assert.Nil(t, issues)
+	assert.True(t, IsNotFoundErr(err))
+}
+
+func TestCheckDeprecatedPluginConfig(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/schemas/plugins/rate-limiting": {body: auditRateLimitingSchema},
+	})
+	client := newFakeKongClient(t, srv)
+
+	unknown, err := client.CheckDeprecatedPluginConfig(defaultCtx, &Plugin{
+		Name: String("rate-limiting"),
+		Config: Configuration{
+			"minute":     10,
+			"redis_host": "redis",
+			"redis":      map[string]interface{}{"host": "redis", "timeout": 2000},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"redis.timeout", "redis_host"}, unknown)
+
+	unknown, err = client.CheckDeprecatedPluginConfig(defaultCtx, &Plugin{
+		Name:   String("rate-limiting"),
+		Config: Configuration{"minute": 10, "policy": "local"},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, unknown)
+
+	_, err = client.CheckDeprecatedPluginConfig(defaultCtx, &Plugin{Name: String("unknown")})
+	assert.Error(t, err)
+	_, err = client.CheckDeprecatedPluginConfig(defaultCtx, &Plugin{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,107 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultRouteProtocols are the protocols of a Route which doesn't set any.
+var defaultRouteProtocols = []string{"http", "https"}
+
+// ValidateProtocolsAgainstScope returns an error if the Plugin can never
+// run because none of its protocols is used by the requests of the entity
+// it is scoped to: the protocols of its Route, or the protocols of the
+// Routes of its Service.
+// Kong accepts such plugins but silently skips them on every request.
+// A Plugin without protocols gets the defaults of its schema and a global
+// Plugin applies to all requests: neither are checked. Neither is a Plugin
+// scoped to a Service without Routes.
+func (p *Plugin) ValidateProtocolsAgainstScope(ctx context.Context, client *Client) error {
+	if p == nil || len(p.Protocols) == 0 {
+		return nil
+	}
+	scope, protocols, err := client.pluginScopeProtocols(ctx, p)
+	if err != nil {
+		return err
+	}
+	if len(protocols) == 0 {
+		return nil
+	}
+
+	var pluginProtocols []string
+	for _, protocol := range p.Protocols {
+		if protocol == nil {
+			continue
+		}
+		if protocols[*protocol] {
+			return nil
+		}
+		pluginProtocols = append(pluginProtocols, *protocol)
+	}
+	scopeProtocols := make([]string, 0, len(protocols))
+	for protocol := range protocols {
+		scopeProtocols = append(scopeProtocols, protocol)
+	}
+	sort.Strings(scopeProtocols)
+	return fmt.Errorf("plugin '%s' never runs: its protocols [%s] don't match "+
+		"the protocols [%s] of %s", p.FriendlyName(), strings.Join(pluginProtocols, ", "),
+		strings.Join(scopeProtocols, ", "), scope)
+}
+
+// pluginScopeProtocols resolves the Route or Service plugin is scoped to
+// and returns a description of it along with the protocols of its
+// requests. No protocols are returned for a global Plugin.
+func (c *Client) pluginScopeProtocols(ctx context.Context,
+	plugin *Plugin,
+) (string, map[string]bool, error) {
+	protocols := make(map[string]bool)
+	switch {
+	case plugin.Route != nil:
+		route, err := c.Routes.Get(ctx, scopeKey(plugin.Route.ID, plugin.Route.Name))
+		if err != nil {
+			return "", nil, fmt.Errorf("fetching route of plugin '%s': %w", plugin.FriendlyName(), err)
+		}
+		addRouteProtocols(protocols, route)
+		return fmt.Sprintf("route '%s'", route.FriendlyName()), protocols, nil
+	case plugin.Service != nil:
+		nameOrID := scopeKey(plugin.Service.ID, plugin.Service.Name)
+		opt := &ListOpt{Size: pageSize}
+		for opt != nil {
+			var routes []*Route
+			var err error
+			routes, opt, err = c.Routes.ListForService(ctx, nameOrID, opt)
+			if err != nil {
+				return "", nil, fmt.Errorf("fetching routes of plugin '%s': %w", plugin.FriendlyName(), err)
+			}
+			for _, route := range routes {
+				addRouteProtocols(protocols, route)
+			}
+		}
+		return fmt.Sprintf("the routes of service '%s'", *nameOrID), protocols, nil
+	}
+	return "", nil, nil
+}
+
+// scopeKey returns the key used to fetch an entity a Plugin is scoped to.
+func scopeKey(id, name *string) *string {
+	if !isEmptyString(id) {
+		return id
+	}
+	return name
+}
+
+func addRouteProtocols(protocols map[string]bool, route *Route) {
+	if len(route.Protocols) == 0 {
+		for _, protocol := range defaultRouteProtocols {
+			protocols[protocol] = true
+		}
+		return
+	}
+	for _, protocol := range route.Protocols {
+		if protocol != nil {
+			protocols[*protocol] = true
+		}
+	}
+}
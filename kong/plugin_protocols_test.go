@@ -0,0 +1,131 @@
+package kong
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginValidateProtocolsAgainstScope(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/routes/web": {body: `{"id": "r1", "name": "web", "protocols": ["http", "https"]}`},
+		"/routes/r2":  {body: `{"id": "r2", "name": "default"}`},
+		"/services/stream/routes": fakeList(`[
+			{"id": "r3", "name": "tcp", "protocols": ["tcp"]},
+			{"id": "r4", "name": "tls", "protocols": ["tls"]}
+		]`),
+		"/services/empty/routes": fakeList(`[]`),
+	})
+	client := newFakeKongClient(t, srv)
+
+	tests := []struct {
+		name    string
+		plugin  *Plugin
+		wantErr string
+	}{
+		{
+			name: "compatible route",
+			plugin: &Plugin{
+				Name:      String("cors"),
+				Route:     &Route{Name: String("web")},
+				Protocols: StringSlice("grpc", "https"),
+			},
+		},
+		{
+			name: "incompatible route",
+			plugin: &Plugin{
+				Name:      String("cors"),
+				Route:     &Route{Name: String("web")},
+				Protocols: StringSlice("grpc", "grpcs"),
+			},
+			wantErr: "plugin 'cors' never runs: its protocols [grpc, grpcs] don't match " +
+				"the protocols [http, https] of route 'web'",
+		},
+		{
+			name: "route with default protocols",
+			plugin: &Plugin{
+				Name:      String("cors"),
+				Route:     &Route{ID: String("r2")},
+				Protocols: StringSlice("http"),
+			},
+		},
+		{
+			name: "compatible service",
+			plugin: &Plugin{
+				Name:      String("ip-restriction"),
+				Service:   &Service{Name: String("stream")},
+				Protocols: StringSlice("tls"),
+			},
+		},
+		{
+			name: "incompatible service",
+			plugin: &Plugin{
+				Name:      String("key-auth"),
+				Service:   &Service{Name: String("stream")},
+				Protocols: StringSlice("http", "https"),
+			},
+			wantErr: "plugin 'key-auth' never runs: its protocols [http, https] don't match " +
+				"the protocols [tcp, tls] of the routes of service 'stream'",
+		},
+		{
+			name: "service without routes",
+			plugin: &Plugin{
+				Name:      String("key-auth"),
+				Service:   &Service{ID: String("empty")},
+				Protocols: StringSlice("http"),
+			},
+		},
+		{
+			name:   "without protocols",
+			plugin: &Plugin{Name: String("cors"), Route: &Route{Name: String("web")}},
+		},
+		{
+			name:   "global",
+			plugin: &Plugin{Name: String("cors"), Protocols: StringSlice("tcp")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.plugin.ValidateProtocolsAgainstScope(defaultCtx, client)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	err := (&Plugin{
+		Name:      String("cors"),
+		Route:     &Route{Name: String("missing")},
+		Protocols: StringSlice("http"),
+	}).ValidateProtocolsAgainstScope(defaultCtx, client)
+	assert.True(t, IsNotFoundErr(err))
+}
+
+// serviceRoutes is an AbstractRouteService listing routes for any service.
+type serviceRoutes struct {
+	AbstractRouteService
+	routes []*Route
+}
+
+func (s serviceRoutes) ListForService(context.Context, *string, *ListOpt) ([]*Route, *ListOpt, error) {
+	return s.routes, nil, nil
+}
+
+func TestPluginValidateProtocolsUsesRouteService(t *testing.T) {
+	client, err := NewClient(String("http://localhost:1"), nil)
+	require.NoError(t, err)
+	client.Routes = serviceRoutes{routes: []*Route{{Name: String("grpc"), Protocols: StringSlice("grpc")}}}
+
+	plugin := &Plugin{
+		Name:      String("cors"),
+		Service:   &Service{Name: String("svc")},
+		Protocols: StringSlice("http"),
+	}
+	assert.EqualError(t, plugin.ValidateProtocolsAgainstScope(defaultCtx, client),
+		"plugin 'cors' never runs: its protocols [http] don't match "+
+			"the protocols [grpc] of the routes of service 'svc'")
+}
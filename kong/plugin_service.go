@@ -36,6 +36,12 @@ type AbstractPluginService interface {
 	ListAll(ctx context.Context) ([]*Plugin, error)
 	// ListAllForConsumer fetches all Plugins in Kong enabled for a consumer.
 	ListAllForConsumer(ctx context.Context, consumerIDorName *string) ([]*Plugin, error)
+	// ListAllForConsumerOnly fetches all Plugins in Kong scoped to a
+	// consumer only, and not to a service or a route as well.
+	ListAllForConsumerOnly(ctx context.Context, consumerIDorName *string) ([]*Plugin, error)
+	// ListAllForConsumerGroup fetches all Plugins in Kong enabled for a
+	// consumer group.
+	ListAllForConsumerGroup(ctx context.Context, consumerGroupIDorName *string) ([]*Plugin, error)
 	// ListAllForService fetches all Plugins in Kong enabled for a service.
 	ListAllForService(ctx context.Context, serviceIDorName *string) ([]*Plugin, error)
 	// ListAllForRoute fetches all Plugins in Kong enabled for a service.
@@ -374,6 +380,37 @@ func (s *PluginService) ListAllForConsumer(ctx context.Context,
 	return s.listAllByPath(ctx, "/consumers/"+*consumerIDorName+"/plugins")
 }
 
+// ListAllForConsumerOnly fetches all Plugins in Kong scoped to a consumer
+// only. Plugins returned by ListAllForConsumer which are also scoped to a
+// service or a route, e.g. a rate limit for a consumer on a given route,
+// are left out.
+func (s *PluginService) ListAllForConsumerOnly(ctx context.Context,
+	consumerIDorName *string,
+) ([]*Plugin, error) {
+	plugins, err := s.ListAllForConsumer(ctx, consumerIDorName)
+	if err != nil {
+		return nil, err
+	}
+	var res []*Plugin
+	for _, p := range plugins {
+		if p.Service == nil && p.Route == nil {
+			res = append(res, p)
+		}
+	}
+	return res, nil
+}
+
+// ListAllForConsumerGroup fetches all Plugins in Kong enabled for a
+// consumer group.
+func (s *PluginService) ListAllForConsumerGroup(ctx context.Context,
+	consumerGroupIDorName *string,
+) ([]*Plugin, error) {
+	if isEmptyString(consumerGroupIDorName) {
+		return nil, fmt.Errorf("consumerGroupIDorName cannot be nil")
+	}
+	return s.listAllByPath(ctx, "/consumer_groups/"+*consumerGroupIDorName+"/plugins")
+}
+
 // ListAllForService fetches all Plugins in Kong enabled for a service.
 func (s *PluginService) ListAllForService(ctx context.Context,
 	serviceIDorName *string,
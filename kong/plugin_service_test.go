@@ -1,6 +1,7 @@
 package kong
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -754,3 +755,116 @@ func comparePlugins(T *testing.T, expected, actual []*Plugin) bool {
 
 	return (compareSlices(expectedNames, actualNames))
 }
+
+func TestPluginListAllForConsumerGroup(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/consumer_groups/gold/plugins": fakeList(`[{"id": "p1", "name": "rate-limiting-advanced",
+			"consumer_group": {"id": "cg1"}, "config": {"limit": [100]}}]`),
+	})
+	client := newFakeKongClient(t, srv)
+
+	plugins, err := client.Plugins.ListAllForConsumerGroup(defaultCtx, String("gold"))
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	assert.Equal(t, &ConsumerGroup{ID: String("cg1")}, plugins[0].ConsumerGroup)
+	assert.Nil(t, plugins[0].Consumer)
+
+	_, err = client.Plugins.ListAllForConsumerGroup(defaultCtx, String("silver"))
+	assert.True(t, IsNotFoundErr(err))
+	_, err = client.Plugins.ListAllForConsumerGroup(defaultCtx, nil)
+	assert.Error(t, err)
+}
+
+func TestPluginScopedToConsumerGroup(T *testing.T) {
+	RunWhenEnterprise(T, ">=3.4.0", RequiredFeatures{})
+
+	assert := assert.New(T)
+	require := require.New(T)
+
+	client, err := NewTestClient(nil, nil)
+	require.NoError(err)
+	require.NotNil(client)
+
+	createdConsumerGroup, err := client.ConsumerGroups.Create(defaultCtx, &ConsumerGroup{
+		Name: String("foo"),
+	})
+	require.NoError(err)
+	require.NotNil(createdConsumerGroup)
+	T.Cleanup(func() {
+		assert.NoError(client.ConsumerGroups.Delete(defaultCtx, createdConsumerGroup.ID))
+	})
+
+	// global, not listed for the consumer group
+	globalPlugin, err := client.Plugins.Create(defaultCtx, &Plugin{
+		Name: String("key-auth"),
+	})
+	require.NoError(err)
+	T.Cleanup(func() {
+		assert.NoError(client.Plugins.Delete(defaultCtx, globalPlugin.ID))
+	})
+
+	createdPlugin, err := client.Plugins.Create(defaultCtx, &Plugin{
+		Name:          String("request-termination"),
+		ConsumerGroup: createdConsumerGroup,
+	})
+	require.NoError(err)
+	require.NotNil(createdPlugin)
+	T.Cleanup(func() {
+		assert.NoError(client.Plugins.Delete(defaultCtx, createdPlugin.ID))
+	})
+	require.NotNil(createdPlugin.ConsumerGroup)
+	assert.Equal(*createdConsumerGroup.ID, *createdPlugin.ConsumerGroup.ID)
+
+	plugin, err := client.Plugins.Get(defaultCtx, createdPlugin.ID)
+	require.NoError(err)
+	require.NotNil(plugin.ConsumerGroup)
+	assert.Equal(*createdConsumerGroup.ID, *plugin.ConsumerGroup.ID)
+
+	plugins, err := client.Plugins.ListAllForConsumerGroup(defaultCtx, createdConsumerGroup.Name)
+	require.NoError(err)
+	require.Len(plugins, 1)
+	assert.Equal(*createdPlugin.ID, *plugins[0].ID)
+
+	plugins, err = client.Plugins.ListAllForConsumerGroup(defaultCtx, createdConsumerGroup.ID)
+	require.NoError(err)
+	assert.Len(plugins, 1)
+}
+
+func TestPluginListAllForConsumerOnly(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/consumers/alice/plugins": fakeList(`[
+			{"id": "p1", "name": "rate-limiting", "consumer": {"id": "c1"}},
+			{"id": "p2", "name": "rate-limiting", "consumer": {"id": "c1"}, "route": {"id": "r1"}},
+			{"id": "p3", "name": "acl", "consumer": {"id": "c1"}, "service": {"id": "s1"}}
+		]`),
+	})
+	client := newFakeKongClient(t, srv)
+
+	plugins, err := client.Plugins.ListAllForConsumer(defaultCtx, String("alice"))
+	require.NoError(t, err)
+	assert.Len(t, plugins, 3)
+
+	plugins, err = client.Plugins.ListAllForConsumerOnly(defaultCtx, String("alice"))
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	assert.Equal(t, "p1", *plugins[0].ID)
+
+	_, err = client.Plugins.ListAllForConsumerOnly(defaultCtx, String("bob"))
+	assert.True(t, IsNotFoundErr(err))
+	_, err = client.Plugins.ListAllForConsumerOnly(defaultCtx, nil)
+	assert.Error(t, err)
+}
+
+func TestPluginConsumerGroupJSON(t *testing.T) {
+	plugin := &Plugin{
+		Name:          String("rate-limiting-advanced"),
+		ConsumerGroup: &ConsumerGroup{ID: String("cg1")},
+	}
+	b, err := json.Marshal(plugin)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "rate-limiting-advanced", "consumer_group": {"id": "cg1"}}`, string(b))
+
+	var decoded Plugin
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, plugin, &decoded)
+}
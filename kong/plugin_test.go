@@ -0,0 +1,65 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginValidateScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		plugin   *Plugin
+		expected []string
+	}{
+		{
+			name:   "global",
+			plugin: &Plugin{Name: String("cors")},
+		},
+		{
+			name: "service and route",
+			plugin: &Plugin{
+				Name:    String("cors"),
+				Service: &Service{Name: String("svc1")},
+				Route:   &Route{ID: String("r1")},
+			},
+		},
+		{
+			name: "consumer group and service",
+			plugin: &Plugin{
+				Name:          String("rate-limiting-advanced"),
+				Service:       &Service{ID: String("s1")},
+				ConsumerGroup: &ConsumerGroup{Name: String("gold")},
+			},
+		},
+		{
+			name: "consumer and consumer group",
+			plugin: &Plugin{
+				Name:          String("rate-limiting-advanced"),
+				Consumer:      &Consumer{Username: String("alice")},
+				ConsumerGroup: &ConsumerGroup{ID: String("cg1")},
+			},
+			expected: []string{"'consumer' and 'consumer_group' can't both be set"},
+		},
+		{
+			name: "empty references",
+			plugin: &Plugin{
+				Name:          String("rate-limiting-advanced"),
+				Route:         &Route{},
+				ConsumerGroup: &ConsumerGroup{Name: String("")},
+			},
+			expected: []string{
+				"'route' must have an id or a name",
+				"'consumer_group' must have an id or a name",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.plugin.ValidateScope())
+		})
+	}
+
+	var p *Plugin
+	assert.Nil(t, p.ValidateScope())
+}
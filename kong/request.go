@@ -32,6 +32,11 @@ func (c *Client) NewRequestRaw(method, baseURL string, endpoint string, qs inter
 			if err != nil {
 				return nil, err
 			}
+			if c.fieldAliases != nil {
+				if b, err = c.fieldAliases.encode(b); err != nil {
+					return nil, err
+				}
+			}
 			r = bytes.NewBuffer(b)
 		}
 	}
@@ -46,6 +51,9 @@ func (c *Client) NewRequestRaw(method, baseURL string, endpoint string, qs inter
 	if body != nil {
 		req.Header.Add("Content-Type", "application/json")
 	}
+	if c.accept != "" {
+		req.Header.Set("Accept", c.accept)
+	}
 
 	// add query string if any
 	if qs != nil {
@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -19,18 +20,37 @@ func newResponse(res *http.Response) *Response {
 	return &Response{Response: res}
 }
 
+// messageFromBody returns the message of an error response body.
+// Bodies which aren't JSON, such as HTML error pages of proxies or plain
+// text errors, are returned as is.
 func messageFromBody(b []byte) string {
 	s := struct {
 		Message string
 	}{}
 
 	if err := json.Unmarshal(b, &s); err != nil {
+		if raw := strings.TrimSpace(string(b)); raw != "" {
+			return raw
+		}
 		return fmt.Sprintf("<failed to parse response body: %v>", err)
 	}
 
 	return s.Message
 }
 
+// kongErrorFromBody returns the code and name of the error returned by
+// Kong's database layer in an error response body, if any.
+func kongErrorFromBody(b []byte) (int, string) {
+	s := struct {
+		Code int    `json:"code"`
+		Name string `json:"name"`
+	}{}
+	if err := json.Unmarshal(b, &s); err != nil {
+		return 0, ""
+	}
+	return s.Code, s.Name
+}
+
 func hasError(res *http.Response) error {
 	if res.StatusCode >= 200 && res.StatusCode <= 399 {
 		return nil
@@ -41,7 +61,12 @@ func hasError(res *http.Response) error {
 		return fmt.Errorf("failed to read error body: %w", err)
 	}
 
+	return apiErrorFromBody(res, body)
+}
+
+func apiErrorFromBody(res *http.Response, body []byte) *APIError {
 	apiErr := NewAPIError(res.StatusCode, messageFromBody(body))
+	apiErr.kongCode, apiErr.kongName = kongErrorFromBody(body)
 	if details, ok := extractErrDetails(res); ok {
 		apiErr.SetDetails(details)
 	}
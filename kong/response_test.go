@@ -57,14 +57,26 @@ func TestHasError(T *testing.T) {
 			},
 		},
 		{
-			name: "code 404, unparseable json",
+			name: "code 404, plain text body",
 			response: http.Response{
 				StatusCode: 404,
 				Body:       io.NopCloser(strings.NewReader(`This is not json`)),
 			},
 			want: &APIError{
 				httpCode: 404,
-				message:  "<failed to parse response body: invalid character 'T' looking for beginning of value>",
+				message:  "This is not json",
+			},
+		},
+		{
+			name: "code 502, html body",
+			response: http.Response{
+				StatusCode: http.StatusBadGateway,
+				Body: io.NopCloser(strings.NewReader(
+					"<html><body><h1>502 Bad Gateway</h1></body></html>\n")),
+			},
+			want: &APIError{
+				httpCode: http.StatusBadGateway,
+				message:  "<html><body><h1>502 Bad Gateway</h1></body></html>",
 			},
 		},
 		{
@@ -102,3 +114,18 @@ func TestHasError(T *testing.T) {
 		})
 	}
 }
+
+func TestHasErrorNonJSONBody(t *testing.T) {
+	err := hasError(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(strings.NewReader("no Route matched with those values")),
+	})
+	var apiErr *APIError
+	assert.ErrorAs(t, err, &apiErr)
+	assert.True(t, IsNotFoundErr(err))
+	assert.Equal(t, http.StatusNotFound, apiErr.Code())
+	assert.Equal(t, "no Route matched with those values", apiErr.Message())
+	assert.Zero(t, apiErr.ErrorCode())
+	assert.Empty(t, apiErr.ErrorName())
+	assert.Equal(t, `HTTP status 404 (message: "no Route matched with those values")`, err.Error())
+}
@@ -0,0 +1,211 @@
+package kong
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	// retryBaseBackoff is the delay before the first retry of a request.
+	// It doubles with each attempt, up to retryMaxBackoff.
+	retryBaseBackoff = 100 * time.Millisecond
+	retryMaxBackoff  = 5 * time.Second
+)
+
+// RetryConfig configures how a Client retries requests failing with a
+// connection error or with a response indicating that Kong is overloaded
+// or temporarily unavailable. It is set with the WithRetryConfig option of
+// NewClientWithOptions.
+type RetryConfig struct {
+	// Retries is the number of times a request is retried. Zero, the
+	// default, doesn't retry requests.
+	Retries int
+	// ShouldRetry, if set, decides whether error responses which aren't
+	// retried by default, e.g. a 400 response for a foreign key violation,
+	// are retried.
+	ShouldRetry func(*APIError) bool
+	// Jitter is the jitter applied to the delays between retries. It
+	// defaults to RetryJitterNone.
+	Jitter RetryJitter
+	// MaxElapsedTime caps the time spent on a request and its retries: a
+	// request isn't retried once waiting for the next attempt would take
+	// longer than MaxElapsedTime since it was first sent, even if retries
+	// remain. Zero, the default, doesn't cap it.
+	MaxElapsedTime time.Duration
+}
+
+// RetryJitter randomizes the delays between retries, so that clients
+// retrying at the same time don't keep hitting Kong in lockstep. It is set
+// with RetryConfig.Jitter.
+type RetryJitter int
+
+const (
+	// RetryJitterNone waits for the exponential backoff exactly.
+	RetryJitterNone RetryJitter = iota
+	// RetryJitterFull waits for a random delay between zero and the
+	// exponential backoff.
+	RetryJitterFull
+	// RetryJitterEqual waits for half of the exponential backoff plus a
+	// random delay up to the other half.
+	RetryJitterEqual
+)
+
+// withJitter returns the delay to wait for backoff with jitter applied.
+// randInt63n returns a random number in [0, n), as rand.Int63n does.
+func withJitter(backoff time.Duration, jitter RetryJitter, randInt63n func(n int64) int64) time.Duration {
+	switch jitter {
+	case RetryJitterFull:
+		return time.Duration(randInt63n(int64(backoff) + 1))
+	case RetryJitterEqual:
+		half := backoff / 2
+		return backoff - half + time.Duration(randInt63n(int64(half)+1))
+	}
+	return backoff
+}
+
+// isRetryable reports whether a request which resulted in resp and err
+// is worth retrying: connection errors and responses indicating that
+// Kong is overloaded or temporarily unavailable are.
+func isRetryable(resp *http.Response, err error) bool {
+	if err != nil {
+		// Cancellation and deadlines are the caller's decision.
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isIdempotentMethod reports whether sending a request using method
+// several times has the same effect as sending it once.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// canRetry reports whether req can be sent again after it resulted in
+// resp and err. Requests with a body which can't be rewound are never
+// retried, and neither are POST and PATCH requests unless
+// retryNonIdempotent is set: Kong could have applied them before failing,
+// and sending them again could create duplicate entities. Error
+// responses which aren't retryable by default are retried if shouldRetry,
+// when set, returns true for them.
+func canRetry(req *http.Request, resp *http.Response, err error,
+	shouldRetry func(*APIError) bool, retryNonIdempotent bool,
+) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	if !retryNonIdempotent && !isIdempotentMethod(req.Method) {
+		return false
+	}
+	if isRetryable(resp, err) {
+		return true
+	}
+	return err == nil && shouldRetry != nil && shouldRetryAPIError(resp, shouldRetry)
+}
+
+// shouldRetryAPIError calls shouldRetry with the APIError of resp, if it
+// is an error response. The body of resp is buffered so that it can still
+// be read by the caller.
+func shouldRetryAPIError(resp *http.Response, shouldRetry func(*APIError) bool) bool {
+	if resp.StatusCode < http.StatusBadRequest {
+		return false
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	return shouldRetry(apiErrorFromBody(resp, body))
+}
+
+// rewindBody resets the body of req so that it can be sent again.
+func rewindBody(req *http.Request) error {
+	if req.GetBody == nil {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
+// retryBackoff returns the delay to wait before retrying a request
+// for the attempt-th time (starting at 0), with jitter applied. The
+// Retry-After header of a 429 response takes precedence over the
+// exponential backoff and is never jittered.
+func retryBackoff(resp *http.Response, attempt int, jitter RetryJitter) time.Duration {
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		if details, ok := extractErrTooManyRequestsDetails(resp); ok {
+			return details.RetryAfter
+		}
+	}
+	backoff := retryBaseBackoff << attempt
+	if backoff <= 0 || backoff > retryMaxBackoff {
+		backoff = retryMaxBackoff
+	}
+	// jitter doesn't need a cryptographically secure generator
+	return withJitter(backoff, jitter, rand.Int63n)
+}
+
+// retryClock measures the time spent retrying a request and waits between
+// its attempts. Tests replace it so that they don't depend on the wall
+// clock.
+type retryClock interface {
+	Now() time.Time
+	// Wait waits for delay, returning early with an error if ctx is done.
+	Wait(ctx context.Context, delay time.Duration) error
+}
+
+// systemRetryClock is the retryClock using the wall clock.
+type systemRetryClock struct{}
+
+func (systemRetryClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemRetryClock) Wait(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// exceedsBudget reports whether waiting for delay before retrying a
+// request after elapsed would exceed maxElapsedTime. A zero
+// maxElapsedTime never does.
+func exceedsBudget(elapsed, delay, maxElapsedTime time.Duration) bool {
+	return maxElapsedTime > 0 && elapsed+delay > maxElapsedTime
+}
+
+// waitForRetry discards resp, if any, and waits for delay using clock
+// before the next attempt of a request, returning early with an error if
+// ctx is done.
+func waitForRetry(ctx context.Context, clock retryClock, resp *http.Response, delay time.Duration) error {
+	if resp != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+	return clock.Wait(ctx, delay)
+}
@@ -1,5 +1,12 @@
 package kong
 
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
 // Route represents a Route in Kong.
 // Read https://docs.konghq.com/gateway/latest/admin-api/#route-object
 // +k8s:deepcopy-gen=true
@@ -59,3 +66,404 @@ func (r *Route) FriendlyName() string {
 	}
 	return ""
 }
+
+// Issues reports inconsistencies between the protocols of a Route and the
+// matching fields set on it, e.g. an http Route without any matcher or a tcp
+// Route with paths, as well as methods which aren't uppercase standard HTTP
+// methods, see ValidateHTTPMethods. The Route is not modified.
+// Routes using an expression are not checked since their matchers live in
+// Expression.
+func (r *Route) Issues() []string {
+	if r == nil || r.Expression != nil {
+		return nil
+	}
+
+	var httpLike, streamLike, grpcLike bool
+	for _, p := range r.Protocols {
+		if p == nil {
+			continue
+		}
+		switch *p {
+		case "http", "https":
+			httpLike = true
+		case "grpc", "grpcs":
+			grpcLike = true
+		case "tcp", "tls", "udp", "tls_passthrough":
+			streamLike = true
+		}
+	}
+
+	var issues []string
+	if httpLike && streamLike {
+		issues = append(issues, "http and stream protocols cannot be mixed")
+	}
+
+	hasHTTPMatchers := len(r.Methods) > 0 || len(r.Hosts) > 0 ||
+		len(r.Headers) > 0 || len(r.Paths) > 0 || len(r.SNIs) > 0
+	hasStreamMatchers := len(r.Sources) > 0 || len(r.Destinations) > 0 || len(r.SNIs) > 0
+
+	if (httpLike || grpcLike) && !hasHTTPMatchers {
+		issues = append(issues,
+			"one of 'methods', 'hosts', 'headers', 'paths' or 'snis' must be set for http protocols")
+	}
+	if streamLike {
+		if !hasStreamMatchers {
+			issues = append(issues,
+				"one of 'sources', 'destinations' or 'snis' must be set for stream protocols")
+		}
+		for _, f := range []struct {
+			name string
+			set  bool
+		}{
+			{"methods", len(r.Methods) > 0},
+			{"hosts", len(r.Hosts) > 0},
+			{"headers", len(r.Headers) > 0},
+			{"paths", len(r.Paths) > 0},
+		} {
+			if f.set {
+				issues = append(issues, fmt.Sprintf("'%s' cannot be set for stream protocols", f.name))
+			}
+		}
+	}
+	if (httpLike || grpcLike) && !streamLike {
+		if len(r.Sources) > 0 {
+			issues = append(issues, "'sources' cannot be set for http protocols")
+		}
+		if len(r.Destinations) > 0 {
+			issues = append(issues, "'destinations' cannot be set for http protocols")
+		}
+	}
+	if err := ValidateHTTPMethods(r.Methods); err != nil {
+		issues = append(issues, err.Error())
+	} else {
+		for _, m := range r.Methods {
+			if *m != strings.ToUpper(*m) || *m != strings.TrimSpace(*m) {
+				issues = append(issues, fmt.Sprintf("method '%s' must be uppercase, without spaces", *m))
+			}
+		}
+	}
+	if grpcLike {
+		if len(r.Methods) > 0 {
+			issues = append(issues, "'methods' cannot be set for grpc protocols")
+		}
+		if r.StripPath != nil && *r.StripPath {
+			issues = append(issues, "'strip_path' cannot be enabled for grpc protocols")
+		}
+	}
+	return issues
+}
+
+// Router flavors supported by Kong, see the router_flavor configuration
+// property.
+const (
+	RouterFlavorTraditional           = "traditional"
+	RouterFlavorTraditionalCompatible = "traditional_compatible"
+	RouterFlavorExpressions           = "expressions"
+)
+
+// ValidateForFlavor checks that a Route can be used with the router flavor
+// a Kong gateway runs with: expression Routes are rejected by the
+// traditional flavors, and Routes without an expression are rejected by
+// the expressions flavor.
+func (r *Route) ValidateForFlavor(flavor string) error {
+	if r == nil {
+		return fmt.Errorf("route is nil")
+	}
+	hasExpression := !isEmptyString(r.Expression)
+	switch flavor {
+	case RouterFlavorTraditional, RouterFlavorTraditionalCompatible:
+		if hasExpression {
+			return fmt.Errorf("route '%s' uses an expression which isn't supported by the %s router",
+				r.FriendlyName(), flavor)
+		}
+	case RouterFlavorExpressions:
+		if !hasExpression {
+			return fmt.Errorf("route '%s' has no expression which is required by the %s router",
+				r.FriendlyName(), flavor)
+		}
+	default:
+		return fmt.Errorf("unknown router flavor: '%s'", flavor)
+	}
+	return nil
+}
+
+// DetectRouteOverlaps groups the routes whose matchers overlap, i.e. which
+// could both match the same request, making routing ambiguous.
+// The detection is best-effort and only considers hosts, paths, methods
+// and protocols. Two routes overlap if, for each of these matchers, either
+// route leaves it unset, matching any value, or both routes share a value;
+// paths are shared when one is a prefix of the other. Regex paths and wildcard hosts are only compared
+// for equality, and routes using an expression are skipped.
+// Each returned group holds at least two routes, in the order of routes.
+func DetectRouteOverlaps(routes []*Route) [][]*Route {
+	parent := make([]int, len(routes))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range routes {
+		for j := i + 1; j < len(routes); j++ {
+			if routesOverlap(routes[i], routes[j]) {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	groups := make(map[int][]*Route)
+	var roots []int
+	for i, r := range routes {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], r)
+	}
+	var res [][]*Route
+	for _, root := range roots {
+		if len(groups[root]) > 1 {
+			res = append(res, groups[root])
+		}
+	}
+	return res
+}
+
+func routesOverlap(a, b *Route) bool {
+	if a == nil || b == nil || a.Expression != nil || b.Expression != nil {
+		return false
+	}
+	return matchersOverlap(a.Hosts, b.Hosts, strings.EqualFold) &&
+		matchersOverlap(a.Paths, b.Paths, pathsOverlap) &&
+		matchersOverlap(a.Methods, b.Methods, strings.EqualFold) &&
+		matchersOverlap(a.Protocols, b.Protocols, strings.EqualFold)
+}
+
+// matchersOverlap returns true if either matcher is unset or if any of
+// their values overlap.
+func matchersOverlap(a, b []*string, overlap func(a, b string) bool) bool {
+	if len(a) == 0 || len(b) == 0 {
+		return true
+	}
+	for _, va := range a {
+		for _, vb := range b {
+			if va != nil && vb != nil && overlap(*va, *vb) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func pathsOverlap(a, b string) bool {
+	if strings.HasPrefix(a, "~") || strings.HasPrefix(b, "~") {
+		return a == b
+	}
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// ValidateRoutePriorities reports regex_priority misconfigurations of the
+// traditional router of Kong 3.x, where regex paths start with "~":
+//   - Routes with regex paths on the same host, or without hosts, which
+//     share the same regex_priority: the order in which their regexes are
+//     evaluated is undefined
+//   - Routes with a non-zero regex_priority but no regex path, for which
+//     regex_priority is ignored
+//
+// regex_priority defaults to 0. Routes using an expression are skipped.
+func ValidateRoutePriorities(routes []*Route) []string {
+	type priorityKey struct {
+		host     string
+		priority int
+	}
+	groups := make(map[priorityKey][]string)
+	var keys []priorityKey
+	var issues []string
+	for _, r := range routes {
+		if r == nil || r.Expression != nil {
+			continue
+		}
+		hasRegex := false
+		for _, p := range r.Paths {
+			if p != nil && strings.HasPrefix(*p, "~") {
+				hasRegex = true
+				break
+			}
+		}
+		priority := 0
+		if r.RegexPriority != nil {
+			priority = *r.RegexPriority
+		}
+		if !hasRegex {
+			if priority != 0 {
+				issues = append(issues, fmt.Sprintf(
+					"route '%s' sets regex_priority %d but has no regex path, regex_priority is ignored",
+					r.FriendlyName(), priority))
+			}
+			continue
+		}
+
+		hosts := []string{""}
+		if len(r.Hosts) > 0 {
+			hosts = hosts[:0]
+			for _, h := range r.Hosts {
+				if h != nil {
+					hosts = append(hosts, strings.ToLower(*h))
+				}
+			}
+		}
+		for _, host := range hosts {
+			key := priorityKey{host: host, priority: priority}
+			if _, ok := groups[key]; !ok {
+				keys = append(keys, key)
+			}
+			groups[key] = append(groups[key], "'"+r.FriendlyName()+"'")
+		}
+	}
+
+	for _, key := range keys {
+		names := groups[key]
+		if len(names) < 2 {
+			continue
+		}
+		host := "without hosts"
+		if key.host != "" {
+			host = fmt.Sprintf("on host '%s'", key.host)
+		}
+		issues = append(issues, fmt.Sprintf(
+			"routes %s have regex paths %s with the same regex_priority %d, their matching order is undefined",
+			strings.Join(names, ", "), host, key.priority))
+	}
+	return issues
+}
+
+// ValidateHeaders returns the problems with the header matches of a Route
+// which Kong rejects:
+//   - a Host header, which is matched with 'hosts' instead,
+//   - an empty header name,
+//   - a header without values, or with an empty value.
+//
+// Header names are reported in lexical order. It returns nil if the
+// header matches are valid.
+func (r *Route) ValidateHeaders() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, 0, len(r.Headers))
+	for name := range r.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var problems []string
+	for _, name := range names {
+		switch {
+		case strings.TrimSpace(name) == "":
+			problems = append(problems, "'headers' cannot match a header with an empty name")
+			continue
+		case strings.EqualFold(name, "host"):
+			problems = append(problems,
+				fmt.Sprintf("'headers' cannot match the '%s' header, use 'hosts' instead", name))
+			continue
+		}
+		values := r.Headers[name]
+		if len(values) == 0 {
+			problems = append(problems, fmt.Sprintf("header '%s' must match at least one value", name))
+		}
+		for _, value := range values {
+			if value == "" {
+				problems = append(problems, fmt.Sprintf("header '%s' cannot match an empty value", name))
+				break
+			}
+		}
+	}
+	return problems
+}
+
+// minBufferingVersion is the first Kong version supporting the
+// request_buffering and response_buffering fields of Routes.
+var minBufferingVersion = MustNewVersion("2.3.0")
+
+// BufferingValid returns false if request or response buffering is
+// disabled on a Route which only proxies stream protocols (tcp, tls, udp
+// or tls_passthrough): buffering only applies to the bodies of HTTP
+// requests and responses.
+func (r *Route) BufferingValid() bool {
+	if r == nil {
+		return true
+	}
+	disabled := (r.RequestBuffering != nil && !*r.RequestBuffering) ||
+		(r.ResponseBuffering != nil && !*r.ResponseBuffering)
+	if !disabled {
+		return true
+	}
+	for _, p := range r.Protocols {
+		if p == nil {
+			continue
+		}
+		switch *p {
+		case "tcp", "tls", "udp", "tls_passthrough":
+		default:
+			return true
+		}
+	}
+	// Kong defaults to http and https
+	return len(r.Protocols) == 0
+}
+
+// minExplicitRegexPathVersion is the first Kong version requiring regex
+// paths of Routes to start with "~".
+var minExplicitRegexPathVersion = MustNewVersion("3.0.0")
+
+// plainPathPattern matches the paths which Kong versions before 3.0 don't
+// treat as regexes.
+var plainPathPattern = regexp.MustCompile(`^[a-zA-Z0-9.\-_~/%]*$`)
+
+// NormalizeRoutePathForCompare returns the canonical form of a Route path
+// as interpreted by the given Kong version, so that paths can be compared
+// across Kong versions without reporting spurious differences:
+//   - regex paths start with "~". Before Kong 3.0, paths containing
+//     characters reserved for regexes are regexes and are prefixed with "~".
+//   - trailing slashes are stripped from prefix paths, except for "/".
+//
+// Regex paths are otherwise returned as is.
+func NormalizeRoutePathForCompare(path string, version Version) string {
+	if strings.HasPrefix(path, "~") {
+		return path
+	}
+	if version.Compare(minExplicitRegexPathVersion) < 0 && !plainPathPattern.MatchString(path) {
+		return "~" + path
+	}
+	if trimmed := strings.TrimRight(path, "/"); trimmed != "" {
+		return trimmed
+	}
+	if path != "" {
+		return "/"
+	}
+	return path
+}
+
+// routePathsEqual returns true if the paths of a and b are the same once
+// normalized for version, see NormalizeRoutePathForCompare.
+func routePathsEqual(a, b []*string, version Version) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] == nil || b[i] == nil {
+			if a[i] != b[i] {
+				return false
+			}
+			continue
+		}
+		if NormalizeRoutePathForCompare(*a[i], version) != NormalizeRoutePathForCompare(*b[i], version) {
+			return false
+		}
+	}
+	return true
+}
@@ -39,6 +39,9 @@ func (s *RouteService) Create(ctx context.Context,
 	if route == nil {
 		return nil, fmt.Errorf("cannot create a nil route")
 	}
+	if err := s.client.validateEntityName(route.Name); err != nil {
+		return nil, err
+	}
 
 	endpoint := "/routes"
 	method := "POST"
@@ -107,6 +110,9 @@ func (s *RouteService) Update(ctx context.Context,
 	if isEmptyString(route.ID) {
 		return nil, fmt.Errorf("ID cannot be nil for Update operation")
 	}
+	if err := s.client.validateEntityName(route.Name); err != nil {
+		return nil, err
+	}
 
 	endpoint := fmt.Sprintf("/routes/%v", *route.ID)
 	req, err := s.client.NewRequest("PATCH", endpoint, nil, route)
@@ -0,0 +1,275 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteIssues(t *testing.T) {
+	tests := []struct {
+		name     string
+		route    *Route
+		expected []string
+	}{
+		{
+			name: "consistent http route",
+			route: &Route{
+				Protocols: StringSlice("http", "https"),
+				Paths:     StringSlice("/foo"),
+			},
+		},
+		{
+			name: "http route with no matchers",
+			route: &Route{
+				Protocols: StringSlice("http"),
+			},
+			expected: []string{
+				"one of 'methods', 'hosts', 'headers', 'paths' or 'snis' must be set for http protocols",
+			},
+		},
+		{
+			name: "http route with empty paths",
+			route: &Route{
+				Protocols: StringSlice("http"),
+				Paths:     []*string{},
+			},
+			expected: []string{
+				"one of 'methods', 'hosts', 'headers', 'paths' or 'snis' must be set for http protocols",
+			},
+		},
+		{
+			name: "tcp route with paths",
+			route: &Route{
+				Protocols:    StringSlice("tcp"),
+				Paths:        StringSlice("/foo"),
+				Destinations: []*CIDRPort{{IP: String("10.0.0.1"), Port: Int(80)}},
+			},
+			expected: []string{
+				"'paths' cannot be set for stream protocols",
+			},
+		},
+		{
+			name: "grpc route with methods and strip_path",
+			route: &Route{
+				Protocols: StringSlice("grpc"),
+				Paths:     StringSlice("/foo"),
+				Methods:   StringSlice("GET"),
+				StripPath: Bool(true),
+			},
+			expected: []string{
+				"'methods' cannot be set for grpc protocols",
+				"'strip_path' cannot be enabled for grpc protocols",
+			},
+		},
+		{
+			name: "http route with invalid methods",
+			route: &Route{
+				Protocols: StringSlice("http"),
+				Methods:   StringSlice("GET", "FETCH"),
+			},
+			expected: []string{
+				"invalid HTTP method: 'FETCH'",
+			},
+		},
+		{
+			name: "http route with lowercase methods",
+			route: &Route{
+				Protocols: StringSlice("http"),
+				Methods:   StringSlice("get", "POST"),
+			},
+			expected: []string{
+				"method 'get' must be uppercase, without spaces",
+			},
+		},
+		{
+			name: "expression routes are not checked",
+			route: &Route{
+				Protocols:  StringSlice("http"),
+				Expression: String(`http.path == "/foo"`),
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			route := tc.route.DeepCopy()
+			assert.Equal(t, tc.expected, tc.route.Issues())
+			assert.Equal(t, route, tc.route, "Issues must not mutate the route")
+		})
+	}
+}
+
+func TestRouteValidateForFlavor(t *testing.T) {
+	traditional := &Route{
+		Name:  String("traditional"),
+		Paths: StringSlice("/foo"),
+	}
+	expression := &Route{
+		Name:       String("expression"),
+		Expression: String(`http.path ^= "/foo"`),
+	}
+
+	assert.NoError(t, traditional.ValidateForFlavor(RouterFlavorTraditional))
+	assert.NoError(t, traditional.ValidateForFlavor(RouterFlavorTraditionalCompatible))
+	assert.Error(t, traditional.ValidateForFlavor(RouterFlavorExpressions))
+
+	assert.Error(t, expression.ValidateForFlavor(RouterFlavorTraditional))
+	assert.Error(t, expression.ValidateForFlavor(RouterFlavorTraditionalCompatible))
+	assert.NoError(t, expression.ValidateForFlavor(RouterFlavorExpressions))
+
+	assert.Error(t, traditional.ValidateForFlavor("unknown"))
+}
+
+func TestDetectRouteOverlaps(t *testing.T) {
+	foo := &Route{Name: String("foo"), Hosts: StringSlice("example.com"), Paths: StringSlice("/api")}
+	fooV1 := &Route{Name: String("foo-v1"), Hosts: StringSlice("EXAMPLE.com"), Paths: StringSlice("/api/v1")}
+	otherHost := &Route{Name: String("other-host"), Hosts: StringSlice("other.com"), Paths: StringSlice("/api")}
+	otherPath := &Route{Name: String("other-path"), Hosts: StringSlice("example.com"), Paths: StringSlice("/web")}
+	postOnly := &Route{
+		Name:    String("post-only"),
+		Hosts:   StringSlice("other.com"),
+		Paths:   StringSlice("/api/v2"),
+		Methods: StringSlice("POST"),
+	}
+	getOnly := &Route{
+		Name:    String("get-only"),
+		Hosts:   StringSlice("other.com"),
+		Paths:   StringSlice("/api/v2"),
+		Methods: StringSlice("GET"),
+	}
+	regex := &Route{Name: String("regex"), Hosts: StringSlice("example.com"), Paths: StringSlice("~/api$")}
+	expression := &Route{Name: String("expression"), Expression: String(`http.path ^= "/api"`)}
+
+	t.Run("routes sharing a path prefix on the same host are grouped", func(t *testing.T) {
+		groups := DetectRouteOverlaps([]*Route{foo, otherHost, otherPath, fooV1})
+		assert.Equal(t, [][]*Route{{foo, fooV1}}, groups)
+	})
+
+	t.Run("distinct routes are not grouped", func(t *testing.T) {
+		groups := DetectRouteOverlaps([]*Route{foo, otherPath, postOnly, getOnly, regex, expression})
+		assert.Nil(t, groups)
+	})
+
+	t.Run("overlaps are transitive", func(t *testing.T) {
+		groups := DetectRouteOverlaps([]*Route{otherHost, postOnly, getOnly})
+		assert.Equal(t, [][]*Route{{otherHost, postOnly, getOnly}}, groups)
+	})
+}
+
+func TestNormalizeRoutePathForCompare(t *testing.T) {
+	v2 := MustNewVersion("2.8.0")
+	v3 := MustNewVersion("3.4.0")
+	for _, tc := range []struct {
+		path     string
+		version  Version
+		expected string
+	}{
+		{path: "/foo", version: v3, expected: "/foo"},
+		{path: "/foo/", version: v3, expected: "/foo"},
+		{path: "/foo//", version: v2, expected: "/foo"},
+		{path: "/", version: v3, expected: "/"},
+		{path: "//", version: v3, expected: "/"},
+		{path: "", version: v3, expected: ""},
+		{path: "~/foo/(?<id>\\d+)$", version: v3, expected: "~/foo/(?<id>\\d+)$"},
+		{path: "/foo/(?<id>\\d+)$", version: v2, expected: "~/foo/(?<id>\\d+)$"},
+		{path: "/foo/(?<id>\\d+)$", version: v3, expected: "/foo/(?<id>\\d+)$"},
+		{path: "/foo/", version: v2, expected: "/foo"},
+		{path: "/foo-bar_baz.v1/%20", version: v2, expected: "/foo-bar_baz.v1/%20"},
+	} {
+		t.Run(tc.version.String()+" "+tc.path, func(t *testing.T) {
+			assert.Equal(t, tc.expected, NormalizeRoutePathForCompare(tc.path, tc.version))
+		})
+	}
+
+	// a regex path of Kong 2.x is the same as its 3.x form
+	assert.Equal(t,
+		NormalizeRoutePathForCompare("~/v1/.*", v3),
+		NormalizeRoutePathForCompare("/v1/.*", v2),
+	)
+}
+
+func TestValidateRoutePriorities(t *testing.T) {
+	routes := []*Route{
+		{Name: String("users"), Hosts: StringSlice("api.example.com"), Paths: StringSlice(`~/users/\d+$`)},
+		{
+			Name:          String("users-any"),
+			Hosts:         StringSlice("API.example.com"),
+			Paths:         StringSlice(`~/users/.*$`),
+			RegexPriority: Int(0),
+		},
+		{
+			Name:          String("orders"),
+			Hosts:         StringSlice("api.example.com"),
+			Paths:         StringSlice(`~/orders/\d+$`),
+			RegexPriority: Int(10),
+		},
+		{Name: String("other-host"), Hosts: StringSlice("www.example.com"), Paths: StringSlice(`~/users/\d+$`)},
+		{Name: String("static"), Paths: StringSlice("/static"), RegexPriority: Int(5)},
+		{Name: String("static-default"), Paths: StringSlice("/assets"), RegexPriority: Int(0)},
+		{Name: String("expression"), Expression: String(`http.path ~ "^/foo"`), RegexPriority: Int(5)},
+	}
+	assert.Equal(t, []string{
+		"route 'static' sets regex_priority 5 but has no regex path, regex_priority is ignored",
+		"routes 'users', 'users-any' have regex paths on host 'api.example.com' with the same regex_priority 0, " +
+			"their matching order is undefined",
+	}, ValidateRoutePriorities(routes))
+
+	assert.Empty(t, ValidateRoutePriorities(routes[2:4]))
+	assert.Equal(t, []string{
+		"routes 'a', 'b' have regex paths without hosts with the same regex_priority 1, " +
+			"their matching order is undefined",
+	}, ValidateRoutePriorities([]*Route{
+		{Name: String("a"), Paths: StringSlice(`~/a$`), RegexPriority: Int(1)},
+		{Name: String("b"), Paths: StringSlice(`~/b$`), RegexPriority: Int(1)},
+	}))
+}
+
+func TestRouteBufferingValid(t *testing.T) {
+	assert.True(t, (&Route{}).BufferingValid())
+	assert.True(t, (&Route{RequestBuffering: Bool(false)}).BufferingValid())
+	assert.True(t, (&Route{
+		Protocols:         StringSlice("grpc"),
+		ResponseBuffering: Bool(false),
+	}).BufferingValid())
+	assert.True(t, (&Route{
+		Protocols:         StringSlice("tcp"),
+		RequestBuffering:  Bool(true),
+		ResponseBuffering: Bool(true),
+	}).BufferingValid())
+	assert.False(t, (&Route{
+		Protocols:        StringSlice("tcp", "tls"),
+		RequestBuffering: Bool(false),
+	}).BufferingValid())
+	assert.False(t, (&Route{
+		Protocols:         StringSlice("tls_passthrough"),
+		ResponseBuffering: Bool(false),
+	}).BufferingValid())
+
+	var r *Route
+	assert.True(t, r.BufferingValid())
+}
+
+func TestRouteValidateHeaders(t *testing.T) {
+	assert.Nil(t, (&Route{}).ValidateHeaders())
+	assert.Nil(t, (&Route{Headers: map[string][]string{
+		"X-Version": {"v1", "v2"},
+		"X-Region":  {"~*^eu-"},
+	}}).ValidateHeaders())
+
+	assert.Equal(t, []string{"'headers' cannot match the 'Host' header, use 'hosts' instead"},
+		(&Route{Headers: map[string][]string{"Host": {"example.com"}}}).ValidateHeaders())
+	assert.Equal(t, []string{
+		"'headers' cannot match a header with an empty name",
+		"'headers' cannot match the 'host' header, use 'hosts' instead",
+		"header 'x-empty' cannot match an empty value",
+		"header 'x-none' must match at least one value",
+	}, (&Route{Headers: map[string][]string{
+		"":        {"foo"},
+		"host":    {"example.com"},
+		"x-empty": {"a", "", ""},
+		"x-none":  {},
+	}}).ValidateHeaders())
+
+	var r *Route
+	assert.Nil(t, r.ValidateHeaders())
+}
@@ -2,13 +2,32 @@ package kong
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // AbstractSchemaService handles schemas in Kong.
 type AbstractSchemaService interface {
 	// Get fetches an entity schema from Kong.
 	Get(ctx context.Context, entity string) (Schema, error)
+	// GetForEntity fetches the schema of an entity subtype from Kong,
+	// such as a plugin or vault schema, caching the result.
+	GetForEntity(ctx context.Context, entity string, subtype string) (Schema, error)
+	// RefreshForEntity fetches the schema of an entity subtype from Kong,
+	// bypassing and updating the cache of GetForEntity.
+	RefreshForEntity(ctx context.Context, entity string, subtype string) (Schema, error)
+	// GetMany fetches several schemas concurrently, caching the results.
+	GetMany(ctx context.Context, names []string, workers int) (map[string]Schema, []error)
+	// Fingerprint returns a hash of the schemas of Kong, which changes when
+	// Kong is upgraded.
+	Fingerprint(ctx context.Context) (string, error)
 }
 
 // SchemaService handles schemas in Kong.
@@ -17,6 +36,98 @@ type SchemaService service
 // Schema represents an entity schema in Kong.
 type Schema map[string]interface{}
 
+// SchemaVersion returns the version metadata carried by schema, if any:
+// its "version" field, or else its "supported_versions" field, joined
+// with commas. Custom plugins may declare these to let tooling detect a
+// schema differing from the one it expects.
+// It returns false if schema carries no version metadata.
+func SchemaVersion(schema Schema) (string, bool) {
+	switch v := schema["version"].(type) {
+	case string:
+		if v != "" {
+			return v, true
+		}
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	}
+	versions, ok := schema["supported_versions"].([]interface{})
+	if !ok {
+		return "", false
+	}
+	var res []string
+	for _, v := range versions {
+		if s, ok := v.(string); ok && s != "" {
+			res = append(res, s)
+		}
+	}
+	if len(res) == 0 {
+		return "", false
+	}
+	return strings.Join(res, ","), true
+}
+
+// CacheStats holds statistics about a cache.
+type CacheStats struct {
+	// Hits is the number of lookups served from the cache.
+	Hits uint64
+	// Misses is the number of lookups which weren't found in the cache.
+	Misses uint64
+	// Entries is the number of entries in the cache.
+	Entries int
+}
+
+// schemaCache holds schemas fetched by SchemaService.GetForEntity,
+// keyed by entity and subtype.
+type schemaCache struct {
+	lock    sync.RWMutex
+	schemas map[string]Schema
+	hits    atomic.Uint64
+	misses  atomic.Uint64
+}
+
+func schemaCacheKey(entity, subtype string) string {
+	return entity + "/" + subtype
+}
+
+func (c *schemaCache) get(key string) (Schema, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	schema, ok := c.schemas[key]
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return schema, ok
+}
+
+func (c *schemaCache) set(key string, schema Schema) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.schemas == nil {
+		c.schemas = make(map[string]Schema)
+	}
+	c.schemas[key] = schema
+}
+
+func (c *schemaCache) stats() CacheStats {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return CacheStats{
+		Hits:    c.hits.Load(),
+		Misses:  c.misses.Load(),
+		Entries: len(c.schemas),
+	}
+}
+
+func (c *schemaCache) reset() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.schemas = nil
+	c.hits.Store(0)
+	c.misses.Store(0)
+}
+
 // Get retrieves the full schema of kong entities.
 func (s *SchemaService) Get(ctx context.Context, entity string) (Schema, error) {
 	req, err := s.client.NewRequest("GET", fmt.Sprintf("/schemas/%s", entity), nil, nil)
@@ -30,3 +141,175 @@ func (s *SchemaService) Get(ctx context.Context, entity string) (Schema, error)
 	}
 	return schema, nil
 }
+
+// GetForEntity retrieves the full schema of an entity subtype, e.g. the
+// schema of a plugin ("plugins", "rate-limiting") or of a vault
+// ("vaults", "env"). subtype is escaped before being used in the path.
+// An empty subtype fetches the schema of the entity itself.
+//
+// Schemas are cached per (entity, subtype) for the lifetime of the client.
+// The returned Schema is shared and must not be modified.
+func (s *SchemaService) GetForEntity(ctx context.Context,
+	entity string, subtype string,
+) (Schema, error) {
+	if entity == "" {
+		return nil, fmt.Errorf("entity cannot be empty")
+	}
+	if schema, ok := s.client.schemaCache.get(schemaCacheKey(entity, subtype)); ok {
+		return schema, nil
+	}
+	return s.RefreshForEntity(ctx, entity, subtype)
+}
+
+// RefreshForEntity retrieves the full schema of an entity subtype, as
+// GetForEntity does, but always fetches it from Kong, e.g. after the
+// schema of a custom plugin changed. The fetched schema replaces the one
+// cached by GetForEntity, if any; the cached schemas of other subtypes are
+// kept.
+// The returned Schema is shared and must not be modified.
+func (s *SchemaService) RefreshForEntity(ctx context.Context,
+	entity string, subtype string,
+) (Schema, error) {
+	if entity == "" {
+		return nil, fmt.Errorf("entity cannot be empty")
+	}
+	endpoint := fmt.Sprintf("/schemas/%s", entity)
+	if subtype != "" {
+		endpoint += "/" + url.PathEscape(subtype)
+	}
+	req, err := s.client.NewRequest("GET", endpoint, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var schema Schema
+	_, err = s.client.Do(ctx, req, &schema)
+	if err != nil {
+		return nil, err
+	}
+	s.client.schemaCache.set(schemaCacheKey(entity, subtype), schema)
+	return schema, nil
+}
+
+// GetMany retrieves the schemas names, such as "services" or
+// "plugins/rate-limiting", using workers goroutines to fetch them
+// concurrently. Each name is the entity, optionally followed by a "/" and
+// the subtype, and is fetched as GetForEntity does, from and into its
+// cache.
+// The result is keyed by name. A schema which can't be fetched doesn't stop
+// the others from being fetched: it is missing from the result and a
+// single error is returned for it, in the order of names.
+// The returned Schemas are shared and must not be modified.
+func (s *SchemaService) GetMany(ctx context.Context,
+	names []string, workers int,
+) (map[string]Schema, []error) {
+	if workers < 1 {
+		workers = 1
+	}
+	var (
+		lock   sync.Mutex
+		res    = make(map[string]Schema, len(names))
+		errs   = make(map[string]error)
+		queued = make(map[string]bool, len(names))
+		wg     sync.WaitGroup
+	)
+	queue := make(chan string)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range queue {
+				entity, subtype, _ := strings.Cut(name, "/")
+				schema, err := s.GetForEntity(ctx, entity, subtype)
+				lock.Lock()
+				if err != nil {
+					errs[name] = fmt.Errorf("fetching schema %s: %w", name, err)
+				} else {
+					res[name] = schema
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	for _, name := range names {
+		if !queued[name] {
+			queued[name] = true
+			queue <- name
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	var errList []error
+	for _, name := range names {
+		if err, ok := errs[name]; ok {
+			errList = append(errList, err)
+			delete(errs, name)
+		}
+	}
+	return res, errList
+}
+
+// Fingerprint returns a hash summarizing the schemas of Kong: the version
+// of Kong, the versions of the plugins available on the node and the
+// schemas of the Services, Routes, Consumers, Upstreams, Targets and
+// Plugins, fetched bypassing the cache of GetForEntity.
+// Kong doesn't send ETags for schemas: comparing fingerprints obtained
+// before and after a while tells whether Kong, or one of its plugins, was
+// upgraded in the meantime, e.g. to reset the schema cache with
+// Client.ResetSchemaCache.
+func (s *SchemaService) Fingerprint(ctx context.Context) (string, error) {
+	info, err := s.client.Root(ctx)
+	if err != nil {
+		return "", err
+	}
+	summary := struct {
+		Version string            `json:"version"`
+		Plugins map[string]string `json:"plugins"`
+		Schemas map[string]Schema `json:"schemas"`
+	}{
+		Plugins: make(map[string]string),
+		Schemas: make(map[string]Schema, len(planEntityTypes)),
+	}
+	summary.Version, _ = info["version"].(string)
+	if plugins, ok := info["plugins"].(map[string]interface{}); ok {
+		available, _ := plugins["available_on_server"].(map[string]interface{})
+		for name, plugin := range available {
+			var version string
+			if p, ok := plugin.(map[string]interface{}); ok {
+				version, _ = p["version"].(string)
+			}
+			summary.Plugins[name] = version
+		}
+	}
+	for _, entityType := range planEntityTypes {
+		schema, err := s.Get(ctx, entityType)
+		// Kong OSS has no consumer_groups schema
+		if entityType == "consumer_groups" && IsNotFoundErr(err) {
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("fetching schema %s: %w", entityType, err)
+		}
+		summary.Schemas[entityType] = schema
+	}
+
+	// maps are encoded with sorted keys
+	b, err := json.Marshal(summary)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// SchemaCacheStats returns statistics about the cache of schemas fetched
+// with SchemaService.GetForEntity.
+func (c *Client) SchemaCacheStats() CacheStats {
+	return c.schemaCache.stats()
+}
+
+// ResetSchemaCache empties the cache of schemas fetched with
+// SchemaService.GetForEntity and resets its statistics.
+func (c *Client) ResetSchemaCache() {
+	c.schemaCache.reset()
+}
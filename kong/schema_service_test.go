@@ -1,9 +1,14 @@
 package kong
 
 import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestSchemaService(T *testing.T) {
@@ -32,3 +37,185 @@ func TestSchemaService(T *testing.T) {
 		assert.NoError(err)
 	}
 }
+
+func TestSchemaServiceGetForEntity(t *testing.T) {
+	fields := fakeResponse{body: `{"fields": [{"path": {"type": "string"}}]}`}
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/schemas/plugins/rate-limiting-advanced": fields,
+		"/schemas/vaults/env":                     fields,
+		"/schemas/vaults/foo%2Fbar":               fields,
+		"/schemas/services":                       fields,
+	})
+	client := newFakeKongClient(t, srv)
+
+	tests := []struct {
+		entity, subtype string
+		expectedPath    string
+	}{
+		{entity: "plugins", subtype: "rate-limiting-advanced", expectedPath: "/schemas/plugins/rate-limiting-advanced"},
+		{entity: "vaults", subtype: "env", expectedPath: "/schemas/vaults/env"},
+		{entity: "vaults", subtype: "foo/bar", expectedPath: "/schemas/vaults/foo%2Fbar"},
+		{entity: "services", expectedPath: "/schemas/services"},
+	}
+	for _, tc := range tests {
+		for i := 0; i < 2; i++ {
+			schema, err := client.Schemas.GetForEntity(defaultCtx, tc.entity, tc.subtype)
+			require.NoError(t, err)
+			assert.Contains(t, schema, "fields")
+		}
+		assert.Equal(t, 1, srv.count(tc.expectedPath), "schema for %s should be fetched once", tc.expectedPath)
+	}
+
+	_, err := client.Schemas.GetForEntity(defaultCtx, "plugins", "does-not-exist")
+	assert.True(t, IsNotFoundErr(err))
+	// errors are not cached
+	_, err = client.Schemas.GetForEntity(defaultCtx, "plugins", "does-not-exist")
+	assert.True(t, IsNotFoundErr(err))
+	assert.Equal(t, 2, srv.count("/schemas/plugins/does-not-exist"))
+
+	_, err = client.Schemas.GetForEntity(defaultCtx, "", "env")
+	assert.Error(t, err)
+}
+
+func TestSchemaServiceGetMany(t *testing.T) {
+	fields := fakeResponse{body: `{"fields": [{"path": {"type": "string"}}]}`}
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/schemas/services":         fields,
+		"/schemas/routes":           fields,
+		"/schemas/plugins/key-auth": fields,
+		"/schemas/plugins/cors":     fields,
+	})
+	client := newFakeKongClient(t, srv)
+
+	names := []string{
+		"services", "routes", "plugins/key-auth", "plugins/does-not-exist",
+		"plugins/cors", "services",
+	}
+	schemas, errs := client.Schemas.GetMany(defaultCtx, names, 3)
+	require.Len(t, errs, 1)
+	assert.True(t, IsNotFoundErr(errs[0]))
+	assert.Contains(t, errs[0].Error(), "plugins/does-not-exist")
+	assert.Len(t, schemas, 4)
+	for _, name := range []string{"services", "routes", "plugins/key-auth", "plugins/cors"} {
+		assert.Contains(t, schemas[name], "fields", name)
+	}
+	assert.Len(t, srv.requests(), 5)
+
+	// schemas are cached
+	schema, err := client.Schemas.GetForEntity(defaultCtx, "plugins", "cors")
+	require.NoError(t, err)
+	assert.Equal(t, schemas["plugins/cors"], schema)
+	schemas, errs = client.Schemas.GetMany(defaultCtx, []string{"routes", "plugins/key-auth"}, 0)
+	assert.Empty(t, errs)
+	assert.Len(t, schemas, 2)
+	assert.Len(t, srv.requests(), 5)
+}
+
+func TestSchemaServiceFingerprint(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/": {body: `{"version": "3.3.0", "plugins": {"available_on_server": {
+			"key-auth": {"version": "3.3.0", "priority": 1250},
+			"custom": {"version": "1.0.0", "priority": 10}
+		}}}`},
+		"/schemas/services":  {body: `{"fields": [{"name": {"type": "string"}}]}`},
+		"/schemas/routes":    {body: `{"fields": [{"paths": {"type": "array"}}]}`},
+		"/schemas/consumers": {body: `{"fields": [{"username": {"type": "string"}}]}`},
+		"/schemas/upstreams": {body: `{"fields": [{"name": {"type": "string"}}]}`},
+		"/schemas/targets":   {body: `{"fields": [{"target": {"type": "string"}}]}`},
+		"/schemas/plugins":   {body: `{"fields": [{"name": {"type": "string"}}]}`},
+	})
+	client := newFakeKongClient(t, srv)
+	fingerprint, err := client.Schemas.Fingerprint(defaultCtx)
+	require.NoError(t, err)
+	require.Len(t, fingerprint, 64)
+
+	again, err := client.Schemas.Fingerprint(defaultCtx)
+	require.NoError(t, err)
+	assert.Equal(t, fingerprint, again)
+
+	// the new field of the upgraded route schema changes the fingerprint
+	srv.set("/schemas/routes", fakeResponse{
+		body: `{"fields": [{"paths": {"type": "array"}}, {"expression": {"type": "string"}}]}`,
+	})
+	upgraded, err := client.Schemas.Fingerprint(defaultCtx)
+	require.NoError(t, err)
+	assert.NotEqual(t, fingerprint, upgraded)
+
+	srv.set("/", fakeResponse{body: `{"version": "3.3.0", "plugins": {"available_on_server": {
+		"key-auth": {"version": "3.3.0", "priority": 1250},
+		"custom": {"version": "1.1.0", "priority": 10}
+	}}}`})
+	pluginUpgraded, err := client.Schemas.Fingerprint(defaultCtx)
+	require.NoError(t, err)
+	assert.NotEqual(t, upgraded, pluginUpgraded)
+
+	srv.remove("/schemas/targets")
+	_, err = client.Schemas.Fingerprint(defaultCtx)
+	assert.Error(t, err)
+}
+
+func TestSchemaCacheStats(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/schemas/plugins/does-not-exist": {status: http.StatusNotFound},
+		"*":                               {body: `{"fields": []}`},
+	})
+	client := newFakeKongClient(t, srv)
+	assert.Equal(t, CacheStats{}, client.SchemaCacheStats())
+
+	for _, subtype := range []string{"key-auth", "key-auth", "cors", "key-auth", "cors"} {
+		_, err := client.Schemas.GetForEntity(defaultCtx, "plugins", subtype)
+		require.NoError(t, err)
+	}
+	_, err := client.Schemas.GetForEntity(defaultCtx, "plugins", "does-not-exist")
+	require.Error(t, err)
+	assert.Equal(t, CacheStats{Hits: 3, Misses: 3, Entries: 2}, client.SchemaCacheStats())
+
+	client.ResetSchemaCache()
+	assert.Equal(t, CacheStats{}, client.SchemaCacheStats())
+	_, err = client.Schemas.GetForEntity(defaultCtx, "plugins", "key-auth")
+	require.NoError(t, err)
+	assert.Equal(t, CacheStats{Misses: 1, Entries: 1}, client.SchemaCacheStats())
+
+	t.Run("concurrent use", func(t *testing.T) {
+		client.ResetSchemaCache()
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := client.Schemas.GetForEntity(defaultCtx, "plugins", "key-auth")
+				assert.NoError(t, err)
+				client.SchemaCacheStats()
+			}()
+		}
+		wg.Wait()
+		stats := client.SchemaCacheStats()
+		assert.Equal(t, uint64(10), stats.Hits+stats.Misses)
+		assert.Equal(t, 1, stats.Entries)
+	})
+}
+
+func TestSchemaVersion(t *testing.T) {
+	b, err := os.ReadFile("testdata/customPluginSchema.json")
+	require.NoError(t, err)
+	var schema Schema
+	require.NoError(t, json.Unmarshal(b, &schema))
+
+	version, ok := SchemaVersion(schema)
+	assert.True(t, ok)
+	assert.Equal(t, "1.2.0", version)
+
+	delete(schema, "version")
+	version, ok = SchemaVersion(schema)
+	assert.True(t, ok)
+	assert.Equal(t, "1.1.0,1.2.0", version)
+
+	version, ok = SchemaVersion(Schema{"version": 2.0})
+	assert.True(t, ok)
+	assert.Equal(t, "2", version)
+
+	_, ok = SchemaVersion(Schema{"fields": []interface{}{}})
+	assert.False(t, ok)
+	_, ok = SchemaVersion(Schema{"version": "", "supported_versions": []interface{}{}})
+	assert.False(t, ok)
+}
@@ -1,5 +1,11 @@
 package kong
 
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
 // Service represents a Service in Kong.
 // Read https://docs.konghq.com/gateway/latest/admin-api/#service-object
 // +k8s:deepcopy-gen=true
@@ -35,3 +41,67 @@ func (s *Service) FriendlyName() string {
 	}
 	return ""
 }
+
+// tlsProtocols maps the protocols of Services to whether Kong connects to
+// the upstream service over TLS with them.
+var tlsProtocols = map[string]bool{
+	"http":  false,
+	"grpc":  false,
+	"tcp":   false,
+	"udp":   false,
+	"https": true,
+	"grpcs": true,
+	"tls":   true,
+}
+
+// Impact holds the entities deleted along with a Service by
+// DeleteCascade, as returned by DeletionImpact.
+type Impact struct {
+	// Routes are the Routes of the Service.
+	Routes []*Route
+	// RoutePlugins are the Plugins attached to the Routes.
+	RoutePlugins []*Plugin
+	// ServicePlugins are the Plugins attached to the Service.
+	ServicePlugins []*Plugin
+}
+
+// Validate returns warnings about the fields of a Service which are
+// accepted by Kong but are likely mistakes: a TLS protocol used with port
+// 80, a plaintext protocol used with port 443, or a missing host when no
+// url is set. The protocol and port of url are checked when it is set.
+// The Service is not modified.
+func (s *Service) Validate() []string {
+	if s == nil {
+		return nil
+	}
+	var warnings []string
+	protocol, port := s.Protocol, s.Port
+	if s.URL != nil {
+		u, err := url.Parse(*s.URL)
+		if err != nil {
+			return []string{fmt.Sprintf("'url' is invalid: %v", err)}
+		}
+		protocol = String(u.Scheme)
+		port = nil
+		if p, err := strconv.Atoi(u.Port()); err == nil {
+			port = Int(p)
+		}
+	} else if isEmptyString(s.Host) {
+		warnings = append(warnings, "'host' must be set when 'url' is not set")
+	}
+
+	// Kong defaults to http and to the port matching the protocol
+	if protocol == nil || port == nil {
+		return warnings
+	}
+	tls, ok := tlsProtocols[*protocol]
+	switch {
+	case ok && tls && *port == 80:
+		warnings = append(warnings,
+			fmt.Sprintf("protocol '%s' uses TLS but port 80 is usually plaintext", *protocol))
+	case ok && !tls && *port == 443:
+		warnings = append(warnings,
+			fmt.Sprintf("protocol '%s' is plaintext but port 443 usually expects TLS", *protocol))
+	}
+	return warnings
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 )
 
 // AbstractSvcService handles services in Kong.
@@ -18,10 +19,19 @@ type AbstractSvcService interface {
 	Update(ctx context.Context, service *Service) (*Service, error)
 	// Delete deletes an Service in Kong
 	Delete(ctx context.Context, nameOrID *string) error
+	// DeleteCascade deletes a Service in Kong along with its Routes
+	// and the Plugins attached to either of them.
+	DeleteCascade(ctx context.Context, nameOrID *string) error
+	// DeletionImpact fetches the Routes and Plugins which DeleteCascade
+	// deletes along with a Service.
+	DeletionImpact(ctx context.Context, nameOrID *string) (*Impact, error)
 	// List fetches a list of Services in Kong.
 	List(ctx context.Context, opt *ListOpt) ([]*Service, *ListOpt, error)
 	// ListAll fetches all Services in Kong.
 	ListAll(ctx context.Context) ([]*Service, error)
+	// ListUpdatedSince fetches all Services in Kong created or updated
+	// after since.
+	ListUpdatedSince(ctx context.Context, since time.Time) ([]*Service, error)
 }
 
 // Svcservice handles services in Kong.
@@ -37,6 +47,9 @@ func (s *Svcservice) Create(ctx context.Context,
 	if service == nil {
 		return nil, fmt.Errorf("cannot create a nil service")
 	}
+	if err := s.client.validateEntityName(service.Name); err != nil {
+		return nil, err
+	}
 
 	endpoint := "/services"
 	method := "POST"
@@ -112,6 +125,9 @@ func (s *Svcservice) Update(ctx context.Context,
 	if isEmptyString(service.ID) {
 		return nil, fmt.Errorf("ID cannot be nil for Update operation")
 	}
+	if err := s.client.validateEntityName(service.Name); err != nil {
+		return nil, err
+	}
 
 	endpoint := fmt.Sprintf("/services/%v", *service.ID)
 	req, err := s.client.NewRequest("PATCH", endpoint, nil, service)
@@ -143,6 +159,95 @@ func (s *Svcservice) Delete(ctx context.Context, nameOrID *string) error {
 	return err
 }
 
+// DeleteCascade deletes a Service in Kong along with its Routes and the
+// Plugins attached to the Service or its Routes.
+// Entities are deleted in dependency order: the Plugins of each Route,
+// then the Route itself, then the Plugins of the Service and finally
+// the Service. Entities which were already deleted concurrently
+// (404 responses) are skipped.
+func (s *Svcservice) DeleteCascade(ctx context.Context, nameOrID *string) error {
+	if isEmptyString(nameOrID) {
+		return fmt.Errorf("nameOrID cannot be nil for Delete operation")
+	}
+
+	routes, err := s.listAllRoutes(ctx, nameOrID)
+	if err != nil {
+		return ignoreNotFoundErr(err)
+	}
+
+	for _, route := range routes {
+		plugins, err := s.client.Plugins.ListAllForRoute(ctx, route.ID)
+		if err != nil {
+			if IsNotFoundErr(err) {
+				continue
+			}
+			return err
+		}
+		for _, plugin := range plugins {
+			if err := ignoreNotFoundErr(s.client.Plugins.Delete(ctx, plugin.ID)); err != nil {
+				return fmt.Errorf("deleting plugin %s of route %s: %w", *plugin.ID, *route.ID, err)
+			}
+		}
+		if err := ignoreNotFoundErr(s.client.Routes.Delete(ctx, route.ID)); err != nil {
+			return fmt.Errorf("deleting route %s: %w", *route.ID, err)
+		}
+	}
+
+	plugins, err := s.client.Plugins.ListAllForService(ctx, nameOrID)
+	if err != nil {
+		return ignoreNotFoundErr(err)
+	}
+	for _, plugin := range plugins {
+		if err := ignoreNotFoundErr(s.client.Plugins.Delete(ctx, plugin.ID)); err != nil {
+			return fmt.Errorf("deleting plugin %s of service %s: %w", *plugin.ID, *nameOrID, err)
+		}
+	}
+
+	return ignoreNotFoundErr(s.Delete(ctx, nameOrID))
+}
+
+// DeletionImpact fetches the entities which DeleteCascade deletes along
+// with the Service identified by nameOrID: its Routes, the Plugins of
+// these Routes and the Plugins of the Service. Nothing is deleted.
+func (s *Svcservice) DeletionImpact(ctx context.Context, nameOrID *string) (*Impact, error) {
+	if isEmptyString(nameOrID) {
+		return nil, fmt.Errorf("nameOrID cannot be nil for DeletionImpact operation")
+	}
+
+	routes, err := s.listAllRoutes(ctx, nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	impact := &Impact{Routes: routes}
+	for _, route := range routes {
+		plugins, err := s.client.Plugins.ListAllForRoute(ctx, route.ID)
+		if err != nil {
+			return nil, err
+		}
+		impact.RoutePlugins = append(impact.RoutePlugins, plugins...)
+	}
+	impact.ServicePlugins, err = s.client.Plugins.ListAllForService(ctx, nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	return impact, nil
+}
+
+// listAllRoutes fetches all Routes of the Service identified by nameOrID.
+func (s *Svcservice) listAllRoutes(ctx context.Context, nameOrID *string) ([]*Route, error) {
+	var routes, data []*Route
+	var err error
+	opt := &ListOpt{Size: pageSize}
+	for opt != nil {
+		data, opt, err = s.client.Routes.ListForService(ctx, nameOrID, opt)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, data...)
+	}
+	return routes, nil
+}
+
 // List fetches a list of Services in Kong.
 // opt can be used to control pagination.
 func (s *Svcservice) List(ctx context.Context,
@@ -186,3 +291,28 @@ func (s *Svcservice) ListAll(ctx context.Context) ([]*Service, error) {
 	}
 	return services, nil
 }
+
+// ListUpdatedSince fetches all Services in Kong which were updated after
+// since, using updated_at or created_at for Services without the former.
+// Kong can't filter Services by time, so all Services are fetched and
+// filtered client-side. Timestamps have a precision of one second.
+func (s *Svcservice) ListUpdatedSince(ctx context.Context,
+	since time.Time,
+) ([]*Service, error) {
+	services, err := s.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*Service
+	for _, service := range services {
+		ts := service.UpdatedAt
+		if ts == nil {
+			ts = service.CreatedAt
+		}
+		if ts != nil && int64(*ts) > since.Unix() {
+			res = append(res, service)
+		}
+	}
+	return res, nil
+}
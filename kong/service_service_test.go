@@ -1,7 +1,9 @@
 package kong
 
 import (
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -237,3 +239,90 @@ func TestServiceWithClientCert(T *testing.T) {
 	err = client.Certificates.Delete(defaultCtx, createdCertificate.ID)
 	assert.NoError(err)
 }
+
+func TestServiceDeleteCascade(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		// deleted concurrently by someone else
+		"DELETE /routes/r2":        {status: http.StatusNotFound},
+		"DELETE *":                 {status: http.StatusNoContent},
+		"GET /services/s1/routes":  fakeList(`[{"id": "r1"}, {"id": "r2"}]`),
+		"GET /routes/r1/plugins":   fakeList(`[{"id": "p1", "name": "key-auth"}]`),
+		"GET /routes/r2/plugins":   fakeList(`[]`),
+		"GET /services/s1/plugins": fakeList(`[{"id": "p2", "name": "cors"}]`),
+	})
+	client := newFakeKongClient(t, srv)
+
+	require.NoError(t, client.Services.DeleteCascade(defaultCtx, String("s1")))
+	assert.Equal(t, []string{
+		"/plugins/p1",
+		"/routes/r1",
+		"/routes/r2",
+		"/plugins/p2",
+		"/services/s1",
+	}, srv.pathsFor(http.MethodDelete))
+
+	// a service which no longer exists is not an error
+	srv.reset()
+	require.NoError(t, client.Services.DeleteCascade(defaultCtx, String("s2")))
+	assert.Empty(t, srv.pathsFor(http.MethodDelete))
+
+	assert.Error(t, client.Services.DeleteCascade(defaultCtx, nil))
+}
+
+func TestServiceDeletionImpact(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"GET /services/s1/routes":  fakeList(`[{"id": "r1"}, {"id": "r2"}]`),
+		"GET /routes/r1/plugins":   fakeList(`[{"id": "p1", "name": "key-auth"}]`),
+		"GET /routes/r2/plugins":   fakeList(`[{"id": "p2", "name": "acl"}]`),
+		"GET /services/s1/plugins": fakeList(`[{"id": "p3", "name": "cors"}]`),
+		"GET *":                    {status: http.StatusNotFound},
+		"*":                        {status: http.StatusMethodNotAllowed},
+	})
+	client := newFakeKongClient(t, srv)
+
+	impact, err := client.Services.DeletionImpact(defaultCtx, String("s1"))
+	require.NoError(t, err)
+	assert.Equal(t, &Impact{
+		Routes: []*Route{{ID: String("r1")}, {ID: String("r2")}},
+		RoutePlugins: []*Plugin{
+			{ID: String("p1"), Name: String("key-auth")},
+			{ID: String("p2"), Name: String("acl")},
+		},
+		ServicePlugins: []*Plugin{{ID: String("p3"), Name: String("cors")}},
+	}, impact)
+
+	_, err = client.Services.DeletionImpact(defaultCtx, String("s2"))
+	assert.True(t, IsNotFoundErr(err))
+	_, err = client.Services.DeletionImpact(defaultCtx, nil)
+	assert.Error(t, err)
+
+	for _, r := range srv.requests() {
+		assert.Equal(t, http.MethodGet, r.method, "nothing must be deleted")
+	}
+}
+
+func TestServiceListUpdatedSince(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/services": fakeList(`[
+			{"id": "old", "created_at": 1000, "updated_at": 1500},
+			{"id": "updated", "created_at": 1000, "updated_at": 2500},
+			{"id": "created", "created_at": 3000},
+			{"id": "not-updated", "created_at": 1000},
+			{"id": "exact", "created_at": 1000, "updated_at": 2000},
+			{"id": "no-timestamps"}
+		]`),
+	})
+	client := newFakeKongClient(t, srv)
+
+	services, err := client.Services.ListUpdatedSince(defaultCtx, time.Unix(2000, 0))
+	require.NoError(t, err)
+	var ids []string
+	for _, s := range services {
+		ids = append(ids, *s.ID)
+	}
+	assert.Equal(t, []string{"updated", "created"}, ids)
+
+	services, err = client.Services.ListUpdatedSince(defaultCtx, time.Unix(5000, 0))
+	require.NoError(t, err)
+	assert.Empty(t, services)
+}
@@ -0,0 +1,87 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServiceValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		service  *Service
+		expected []string
+	}{
+		{
+			name:    "defaults",
+			service: &Service{Host: String("example.com")},
+		},
+		{
+			name: "https on 443",
+			service: &Service{
+				Host:     String("example.com"),
+				Protocol: String("https"),
+				Port:     Int(443),
+			},
+		},
+		{
+			name: "https on 80",
+			service: &Service{
+				Host:     String("example.com"),
+				Protocol: String("https"),
+				Port:     Int(80),
+			},
+			expected: []string{"protocol 'https' uses TLS but port 80 is usually plaintext"},
+		},
+		{
+			name: "grpc on 443",
+			service: &Service{
+				Host:     String("example.com"),
+				Protocol: String("grpc"),
+				Port:     Int(443),
+			},
+			expected: []string{"protocol 'grpc' is plaintext but port 443 usually expects TLS"},
+		},
+		{
+			name: "grpcs on 80",
+			service: &Service{
+				Host:     String("example.com"),
+				Protocol: String("grpcs"),
+				Port:     Int(80),
+			},
+			expected: []string{"protocol 'grpcs' uses TLS but port 80 is usually plaintext"},
+		},
+		{
+			name: "grpcs on a custom port",
+			service: &Service{
+				Host:     String("example.com"),
+				Protocol: String("grpcs"),
+				Port:     Int(9443),
+			},
+		},
+		{
+			name:     "https url on 80",
+			service:  &Service{URL: String("https://example.com:80/foo")},
+			expected: []string{"protocol 'https' uses TLS but port 80 is usually plaintext"},
+		},
+		{
+			name:    "url without port",
+			service: &Service{URL: String("https://example.com/foo")},
+		},
+		{
+			name:    "missing host",
+			service: &Service{Protocol: String("http"), Port: Int(443)},
+			expected: []string{
+				"'host' must be set when 'url' is not set",
+				"protocol 'http' is plaintext but port 443 usually expects TLS",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			service := tc.service.DeepCopy()
+			assert.Equal(t, tc.expected, tc.service.Validate())
+			assert.Equal(t, service, tc.service, "Validate must not mutate the service")
+		})
+	}
+}
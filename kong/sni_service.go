@@ -22,6 +22,8 @@ type AbstractSNIService interface {
 	ListForCertificate(ctx context.Context, certificateID *string, opt *ListOpt) ([]*SNI, *ListOpt, error)
 	// ListAll fetches all SNIs in Kong.
 	ListAll(ctx context.Context) ([]*SNI, error)
+	// ListAllByTags fetches all SNIs in Kong matching tags.
+	ListAllByTags(ctx context.Context, tags []*string, matchAllTags bool) ([]*SNI, error)
 }
 
 // SNIService handles SNIs in Kong.
@@ -181,3 +183,23 @@ func (s *SNIService) ListAll(ctx context.Context) ([]*SNI, error) {
 	}
 	return snis, nil
 }
+
+// ListAllByTags fetches all SNIs in Kong matching tags.
+// If matchAllTags is true, only SNIs having every tag are returned,
+// otherwise SNIs having at least one of the tags are.
+func (s *SNIService) ListAllByTags(ctx context.Context,
+	tags []*string, matchAllTags bool,
+) ([]*SNI, error) {
+	var snis, data []*SNI
+	var err error
+	opt := &ListOpt{Size: pageSize, Tags: tags, MatchAllTags: matchAllTags}
+
+	for opt != nil {
+		data, opt, err = s.List(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		snis = append(snis, data...)
+	}
+	return snis, nil
+}
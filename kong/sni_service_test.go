@@ -214,3 +214,27 @@ func compareSNIs(T *testing.T, expected, actual []*SNI) bool {
 
 	return (compareSlices(expectedUsernames, actualUsernames))
 }
+
+func TestSNIsListAllByTags(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/snis":           {body: `{"data": [{"id": "1"}], "offset": "o1"}`},
+		"/snis?offset=o1": fakeList(`[{"id": "2"}]`),
+	})
+	client := newFakeKongClient(t, srv)
+
+	t.Run("any tag", func(t *testing.T) {
+		srv.reset()
+		res, err := client.SNIs.ListAllByTags(defaultCtx, StringSlice("foo", "bar"), false)
+		require.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, []string{"foo/bar", "foo/bar"}, srv.queries("tags"))
+	})
+
+	t.Run("all tags", func(t *testing.T) {
+		srv.reset()
+		res, err := client.SNIs.ListAllByTags(defaultCtx, StringSlice("foo", "bar"), true)
+		require.NoError(t, err)
+		assert.Len(t, res, 2)
+		assert.Equal(t, []string{"foo,bar", "foo,bar"}, srv.queries("tags"))
+	})
+}
@@ -0,0 +1,262 @@
+package kong
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// BulkAddTag adds tag to every entity of entityType matching filter.
+// entityType is the name of a top-level Admin API endpoint, such as
+// "services" or "consumers". Entities which already have the tag are
+// skipped. Each entity is updated with a PATCH request containing only its
+// tags, so that other fields are left untouched.
+// It returns how many entities were updated. If an error occurs, the
+// entities updated until then are counted and keep the tag.
+func (c *Client) BulkAddTag(ctx context.Context,
+	entityType string, filter ListOpt, tag string,
+) (int, error) {
+	if entityType == "" {
+		return 0, fmt.Errorf("entityType cannot be empty")
+	}
+	if err := ValidateTag(tag); err != nil {
+		return 0, err
+	}
+
+	type taggedEntity struct {
+		ID   *string   `json:"id"`
+		Tags []*string `json:"tags"`
+	}
+	endpoint := "/" + entityType
+	if filter.Size == 0 {
+		filter.Size = pageSize
+	}
+	if len(filter.Fields) > 0 {
+		filter.Fields = append(filter.Fields[:len(filter.Fields):len(filter.Fields)], "id", "tags")
+	}
+
+	// collect matching entities before updating them, so that updates
+	// don't interfere with pagination
+	var entities []taggedEntity
+	opt := &filter
+	for opt != nil {
+		var data []json.RawMessage
+		var err error
+		data, opt, err = c.list(ctx, endpoint, opt)
+		if err != nil {
+			return 0, err
+		}
+		for _, object := range data {
+			var entity taggedEntity
+			if err := json.Unmarshal(object, &entity); err != nil {
+				return 0, err
+			}
+			entities = append(entities, entity)
+		}
+	}
+
+	var updated int
+	for _, entity := range entities {
+		if isEmptyString(entity.ID) || containsTag(entity.Tags, tag) {
+			continue
+		}
+		body := struct {
+			Tags []*string `json:"tags"`
+		}{Tags: append(entity.Tags, String(tag))}
+		req, err := c.NewRequest("PATCH", endpoint+"/"+*entity.ID, nil, &body)
+		if err != nil {
+			return updated, err
+		}
+		_, err = c.Do(ctx, req, nil)
+		if err != nil {
+			return updated, fmt.Errorf("tagging %s %s: %w", entityType, *entity.ID, err)
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+func containsTag(tags []*string, tag string) bool {
+	for _, t := range tags {
+		if t != nil && *t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ReconcileTags returns the tags to add to and remove from entity so that
+// the tags of entity starting with managedPrefix are exactly required.
+// Tags without managedPrefix are never removed, so that tags owned by
+// other tools or by users are left alone. Required tags are added if
+// missing even if they don't start with managedPrefix.
+// entity must be a pointer to an entity with a Tags field, such as
+// *Service; other values have no tags.
+func ReconcileTags(entity interface{}, required []string, managedPrefix string) (add, remove []string) {
+	current := entityTags(entity)
+	want := make(map[string]bool, len(required))
+	for _, tag := range required {
+		if !want[tag] && !containsTag(current, tag) {
+			add = append(add, tag)
+		}
+		want[tag] = true
+	}
+	for _, tag := range current {
+		if tag != nil && strings.HasPrefix(*tag, managedPrefix) && !want[*tag] {
+			remove = append(remove, *tag)
+		}
+	}
+	return add, remove
+}
+
+// taggedEntityTypes maps top-level Admin API endpoints to the type of their
+// entities, for FindUntagged.
+var taggedEntityTypes = map[string]func() interface{}{
+	"ca_certificates": func() interface{} { return &CACertificate{} },
+	"certificates":    func() interface{} { return &Certificate{} },
+	"consumer_groups": func() interface{} { return &ConsumerGroup{} },
+	"consumers":       func() interface{} { return &Consumer{} },
+	"key-sets":        func() interface{} { return &KeySet{} },
+	"keys":            func() interface{} { return &Key{} },
+	"plugins":         func() interface{} { return &Plugin{} },
+	"routes":          func() interface{} { return &Route{} },
+	"services":        func() interface{} { return &Service{} },
+	"snis":            func() interface{} { return &SNI{} },
+	"upstreams":       func() interface{} { return &Upstream{} },
+	"vaults":          func() interface{} { return &Vault{} },
+}
+
+// FindUntagged returns the entities of entityType without any tag starting
+// with one of requiredPrefixes, e.g. the services without a "team:" tag.
+// entityType is the name of a top-level Admin API endpoint, such as
+// "services" or "consumers". Entities are returned as pointers to their
+// type, e.g. *Service, or as map[string]interface{} for the endpoints which
+// have no type in this package.
+func (c *Client) FindUntagged(ctx context.Context,
+	entityType string, requiredPrefixes []string,
+) ([]interface{}, error) {
+	if entityType == "" {
+		return nil, fmt.Errorf("entityType cannot be empty")
+	}
+
+	var res []interface{}
+	opt := &ListOpt{Size: pageSize}
+	for opt != nil {
+		var data []json.RawMessage
+		var err error
+		data, opt, err = c.list(ctx, "/"+entityType, opt)
+		if err != nil {
+			return nil, err
+		}
+		for _, object := range data {
+			var raw struct {
+				Tags []*string `json:"tags"`
+			}
+			if err := json.Unmarshal(object, &raw); err != nil {
+				return nil, err
+			}
+			if hasTagWithPrefix(raw.Tags, requiredPrefixes) {
+				continue
+			}
+			var entity interface{} = &map[string]interface{}{}
+			if newEntity, ok := taggedEntityTypes[entityType]; ok {
+				entity = newEntity()
+			}
+			if err := json.Unmarshal(object, entity); err != nil {
+				return nil, err
+			}
+			if m, ok := entity.(*map[string]interface{}); ok {
+				entity = *m
+			}
+			res = append(res, entity)
+		}
+	}
+	return res, nil
+}
+
+func hasTagWithPrefix(tags []*string, prefixes []string) bool {
+	for _, tag := range tags {
+		for _, prefix := range prefixes {
+			if tag != nil && strings.HasPrefix(*tag, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// entityTags returns the value of the Tags field of entity, if any.
+func entityTags(entity interface{}) []*string {
+	// the tags of a Consumer Group are the ones of the group itself
+	if group, ok := entity.(*ConsumerGroupObject); ok && group != nil {
+		entity = group.ConsumerGroup
+	}
+	v := reflect.ValueOf(entity)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	field := v.Elem().FieldByName("Tags")
+	if !field.IsValid() || !field.CanInterface() {
+		return nil
+	}
+	tags, _ := field.Interface().([]*string)
+	return tags
+}
+
+// setEntityTags sets the tags of entity, a pointer to an entity with a Tags
+// field, and does nothing for other values.
+func setEntityTags(entity interface{}, tags []*string) {
+	if group, ok := entity.(*ConsumerGroupObject); ok && group != nil {
+		entity = group.ConsumerGroup
+	}
+	v := reflect.ValueOf(entity)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return
+	}
+	field := v.Elem().FieldByName("Tags")
+	if field.IsValid() && field.CanSet() && field.Type() == reflect.TypeOf(tags) {
+		field.Set(reflect.ValueOf(tags))
+	}
+}
+
+// maxTagLength is the maximum length of a tag, in bytes.
+const maxTagLength = 128
+
+// ValidateTag returns an error if tag would be rejected by Kong. Tags must
+// be non-empty, valid UTF-8 strings made of printable ASCII characters
+// other than spaces, commas and slashes, or of non-ASCII characters, and
+// must not be longer than 128 bytes.
+func ValidateTag(tag string) error {
+	if tag == "" {
+		return fmt.Errorf("tag cannot be empty")
+	}
+	if len(tag) > maxTagLength {
+		return fmt.Errorf("invalid tag %q: longer than %d bytes", tag, maxTagLength)
+	}
+	if !utf8.ValidString(tag) {
+		return fmt.Errorf("invalid tag %q: not a valid UTF-8 string", tag)
+	}
+	for _, r := range tag {
+		if r == ',' || r == '/' || (r < utf8.RuneSelf && (r <= ' ' || r == 0x7f)) {
+			return fmt.Errorf("invalid tag %q: expected printable characters except space, ',' and '/'", tag)
+		}
+	}
+	return nil
+}
+
+// ValidateTags returns an error if one of tags would be rejected by Kong,
+// see ValidateTag.
+func ValidateTags(tags []*string) error {
+	for _, tag := range tags {
+		if tag == nil {
+			return fmt.Errorf("tag cannot be nil")
+		}
+		if err := ValidateTag(*tag); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package kong
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkAddTag(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"GET /services": {body: `{"data": [
+			{"id": "s1", "name": "foo", "tags": null},
+			{"id": "s2", "name": "bar", "tags": ["team-a", "managed-by:legacy"]}
+		], "offset": "o1"}`},
+		"GET /services?offset=o1": fakeList(`[{"id": "s3", "name": "baz", "tags": ["team-a"]}]`),
+		"PATCH *":                 {body: `{}`},
+	})
+	client := newFakeKongClient(t, srv)
+
+	t.Run("only entities missing the tag are updated", func(t *testing.T) {
+		count, err := client.BulkAddTag(defaultCtx, "services",
+			ListOpt{Tags: StringSlice("team-a", "team-b")}, "managed-by:legacy")
+		require.NoError(t, err)
+		assert.Equal(t, 2, count)
+
+		var listQueries []string
+		patches := make(map[string]string)
+		for _, r := range srv.requests() {
+			switch r.method {
+			case http.MethodGet:
+				listQueries = append(listQueries, r.query.Get("tags"))
+			case http.MethodPatch:
+				patches[r.path] = r.body
+			}
+		}
+		assert.Equal(t, []string{"team-a/team-b", "team-a/team-b"}, listQueries)
+		require.Len(t, patches, 2)
+		assert.JSONEq(t, `{"tags": ["managed-by:legacy"]}`, patches["/services/s1"])
+		assert.JSONEq(t, `{"tags": ["team-a", "managed-by:legacy"]}`, patches["/services/s3"])
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := client.BulkAddTag(defaultCtx, "", ListOpt{}, "foo")
+		assert.Error(t, err)
+		_, err = client.BulkAddTag(defaultCtx, "services", ListOpt{}, "")
+		assert.Error(t, err)
+		_, err = client.BulkAddTag(defaultCtx, "services", ListOpt{}, "foo bar")
+		assert.Error(t, err)
+	})
+
+	t.Run("list errors are returned", func(t *testing.T) {
+		count, err := client.BulkAddTag(defaultCtx, "routes", ListOpt{}, "foo")
+		assert.True(t, IsNotFoundErr(err))
+		assert.Equal(t, 0, count)
+	})
+}
+
+func TestReconcileTags(t *testing.T) {
+	service := &Service{
+		Name: String("foo"),
+		Tags: StringSlice("team-a", "env:old", "region:eu", "env:prod"),
+	}
+
+	add, remove := ReconcileTags(service, []string{"env:new", "env:prod"}, "env:")
+	assert.Equal(t, []string{"env:new"}, add)
+	assert.Equal(t, []string{"env:old"}, remove)
+
+	add, remove = ReconcileTags(service, []string{"env:old", "env:prod", "team-a"}, "env:")
+	assert.Empty(t, add)
+	assert.Empty(t, remove)
+
+	add, remove = ReconcileTags(&Route{}, []string{"env:new", "env:new"}, "env:")
+	assert.Equal(t, []string{"env:new"}, add)
+	assert.Empty(t, remove)
+
+	add, remove = ReconcileTags(struct{}{}, []string{"env:new"}, "env:")
+	assert.Equal(t, []string{"env:new"}, add)
+	assert.Empty(t, remove)
+}
+
+func TestValidateTag(t *testing.T) {
+	for _, tag := range []string{
+		"team-a",
+		"managed-by:legacy",
+		"env.prod_v1~",
+		"équipe",
+		"タグ",
+		strings.Repeat("a", 128),
+	} {
+		assert.NoError(t, ValidateTag(tag), tag)
+	}
+
+	for _, tag := range []string{
+		"",
+		"team a",
+		"team,a",
+		"team/a",
+		"tab\tstop",
+		"new\nline",
+		"\xff",
+		strings.Repeat("a", 129),
+	} {
+		assert.Error(t, ValidateTag(tag), tag)
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	assert.NoError(t, ValidateTags(nil))
+	assert.NoError(t, ValidateTags(StringSlice("foo", "bar")))
+	assert.EqualError(t, ValidateTags(StringSlice("foo", "b,ar")),
+		`invalid tag "b,ar": expected printable characters except space, ',' and '/'`)
+	assert.Error(t, ValidateTags([]*string{nil}))
+}
+
+func TestFindUntagged(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/services": {body: `{"data": [
+			{"id": "s1", "name": "svc1", "tags": ["team:payments", "prod"]},
+			{"id": "s2", "name": "svc2", "tags": ["prod"]}
+		], "offset": "next"}`},
+		"/services?offset=next": fakeList(`[
+			{"id": "s3", "name": "svc3"},
+			{"id": "s4", "name": "svc4", "tags": ["owner:alice"]}
+		]`),
+		"/partials": fakeList(`[{"id": "x1", "tags": []}]`),
+	})
+	client := newFakeKongClient(t, srv)
+
+	untagged, err := client.FindUntagged(defaultCtx, "services", []string{"team:", "owner:"})
+	require.NoError(t, err)
+	require.Len(t, untagged, 2)
+	assert.Equal(t, "s2", *untagged[0].(*Service).ID)
+	assert.Equal(t, "svc3", *untagged[1].(*Service).Name)
+
+	untagged, err = client.FindUntagged(defaultCtx, "partials", []string{"team:"})
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{map[string]interface{}{"id": "x1", "tags": []interface{}{}}}, untagged)
+
+	_, err = client.FindUntagged(defaultCtx, "unknown", []string{"team:"})
+	assert.True(t, IsNotFoundErr(err))
+	_, err = client.FindUntagged(defaultCtx, "", nil)
+	assert.Error(t, err)
+}
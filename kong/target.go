@@ -1,5 +1,19 @@
 package kong
 
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultTargetPort is the port used by Kong for targets without one.
+const defaultTargetPort = 8000
+
+// hostnameLabel matches a label of a hostname.
+var hostnameLabel = regexp.MustCompile(`^[a-zA-Z0-9_]([a-zA-Z0-9_-]{0,61}[a-zA-Z0-9_])?$`)
+
 // Target represents a Target in Kong.
 // +k8s:deepcopy-gen=true
 type Target struct {
@@ -21,3 +35,45 @@ func (t *Target) FriendlyName() string {
 	}
 	return ""
 }
+
+// TargetFromString returns a Target for s, a host and a port such as
+// "10.0.0.1:8080", "api.internal:443" or "[::1]:80". The host is an IP
+// address or a hostname. The port defaults to 8000, the way Kong does,
+// and is set explicitly on the returned Target along with the default
+// weight of 100. An error is returned if s is malformed.
+func TargetFromString(s string) (*Target, error) {
+	host, port, err := net.SplitHostPort(s)
+	if err != nil {
+		// a bare host, IPv6 addresses included
+		host, port = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]"), strconv.Itoa(defaultTargetPort)
+		if strings.Contains(host, ":") && net.ParseIP(host) == nil {
+			return nil, fmt.Errorf("invalid target '%s': %w", s, err)
+		}
+	}
+	if !validTargetHost(host) {
+		return nil, fmt.Errorf("invalid target '%s': invalid host '%s'", s, host)
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil || n < 1 || n > 65535 {
+		return nil, fmt.Errorf("invalid target '%s': invalid port '%s'", s, port)
+	}
+	return &Target{
+		Target: String(net.JoinHostPort(host, strconv.Itoa(n))),
+		Weight: Int(defaultTargetWeight),
+	}, nil
+}
+
+func validTargetHost(host string) bool {
+	if net.ParseIP(host) != nil {
+		return true
+	}
+	if host == "" || len(host) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(host, "."), ".") {
+		if !hostnameLabel.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
@@ -3,13 +3,19 @@ package kong
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"strings"
 )
 
 // AbstractTargetService handles Targets in Kong.
 type AbstractTargetService interface {
 	// Create creates a Target in Kong under upstreamID.
 	Create(ctx context.Context, upstreamNameOrID *string, target *Target) (*Target, error)
+	// Upsert creates a Target in Kong under upstreamID, unless an identical
+	// Target already exists.
+	Upsert(ctx context.Context, upstreamNameOrID *string, target *Target) (*Target, error)
 	// Delete deletes a Target in Kong
 	Delete(ctx context.Context, upstreamNameOrID *string, targetOrID *string) error
 	// List fetches a list of Targets in Kong.
@@ -22,6 +28,13 @@ type AbstractTargetService interface {
 	// MarkUnhealthy marks target belonging to upstreamNameOrID as unhealthy in
 	// Kong's load balancer.
 	MarkUnhealthy(ctx context.Context, upstreamNameOrID *string, target *Target) error
+	// ValidateDNS checks that the host of target resolves.
+	ValidateDNS(ctx context.Context, target *Target) error
+}
+
+// HostResolver resolves host names, as net.Resolver does.
+type HostResolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
 }
 
 // TargetService handles Targets in Kong.
@@ -59,6 +72,58 @@ func (s *TargetService) Create(ctx context.Context,
 	return &createdTarget, nil
 }
 
+// defaultTargetWeight is the weight Kong assigns to a Target created
+// without one.
+const defaultTargetWeight = 100
+
+// Upsert creates a Target in Kong under upstreamID, keyed on
+// (upstream, target.Target). If the Target already exists with the same
+// weight, it is returned as is and no new revision is created.
+// A weight of 0 disables a Target: upserting a Target with a weight of 0
+// which doesn't exist is a no-op and returns nil.
+func (s *TargetService) Upsert(ctx context.Context,
+	upstreamNameOrID *string, target *Target,
+) (*Target, error) {
+	if isEmptyString(upstreamNameOrID) {
+		return nil, fmt.Errorf("upstreamNameOrID can not be nil")
+	}
+	if target == nil || isEmptyString(target.Target) {
+		return nil, fmt.Errorf("target can not be nil")
+	}
+
+	weight := defaultTargetWeight
+	if target.Weight != nil {
+		weight = *target.Weight
+	}
+
+	targets, err := s.ListAll(ctx, upstreamNameOrID)
+	if err != nil {
+		return nil, err
+	}
+	var current *Target
+	for _, t := range targets {
+		if t.Target != nil && *t.Target == *target.Target {
+			current = t
+			break
+		}
+	}
+
+	if current == nil {
+		if weight == 0 {
+			return nil, nil
+		}
+	} else {
+		currentWeight := defaultTargetWeight
+		if current.Weight != nil {
+			currentWeight = *current.Weight
+		}
+		if currentWeight == weight {
+			return current, nil
+		}
+	}
+	return s.Create(ctx, upstreamNameOrID, target)
+}
+
 // Delete deletes a Target in Kong
 func (s *TargetService) Delete(ctx context.Context,
 	upstreamNameOrID *string, targetOrID *string,
@@ -195,3 +260,36 @@ func (s *TargetService) MarkUnhealthy(ctx context.Context,
 	_, err = s.client.Do(ctx, req, nil)
 	return err
 }
+
+// ValidateDNS checks that the host of target resolves, using the resolver
+// set with WithResolver or net.DefaultResolver, so that typos in the
+// hostnames of targets are caught before Kong marks them unhealthy.
+// Targets using an IP address are not resolved. Results depend on the DNS
+// configuration of the environment running the client, which may differ
+// from the one of Kong.
+func (s *TargetService) ValidateDNS(ctx context.Context, target *Target) error {
+	if target == nil || isEmptyString(target.Target) {
+		return fmt.Errorf("target cannot be empty for ValidateDNS operation")
+	}
+	host, _, err := net.SplitHostPort(*target.Target)
+	if err != nil {
+		// targets default to port 8000
+		host = strings.Trim(*target.Target, "[]")
+	}
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+
+	var resolver HostResolver = net.DefaultResolver
+	if s.client.resolver != nil {
+		resolver = s.client.resolver
+	}
+	if _, err := resolver.LookupHost(ctx, host); err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return fmt.Errorf("target '%s': host '%s' not found (NXDOMAIN)", *target.Target, host)
+		}
+		return fmt.Errorf("target '%s': resolving host '%s': %w", *target.Target, host, err)
+	}
+	return nil
+}
@@ -1,6 +1,12 @@
 package kong
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -332,3 +338,147 @@ func TestTargetMarkUnhealthy(T *testing.T) {
 
 	assert.NoError(client.Upstreams.Delete(defaultCtx, createdUpstream.ID))
 }
+
+func TestTargetsUpsert(t *testing.T) {
+	targets := map[string]int{"10.0.0.1:80": 100, "10.0.0.2:80": 50}
+	var creates int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/upstreams/u1/targets" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			var data []*Target
+			for target, weight := range targets {
+				data = append(data, &Target{Target: String(target), Weight: Int(weight)})
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+		case http.MethodPost:
+			creates++
+			var target Target
+			_ = json.NewDecoder(r.Body).Decode(&target)
+			weight := 100
+			if target.Weight != nil {
+				weight = *target.Weight
+			}
+			targets[*target.Target] = weight
+			target.ID = String(uuid.NewString())
+			target.Weight = Int(weight)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(&target)
+		}
+	}))
+	defer srv.Close()
+	client, err := NewClient(String(srv.URL), nil)
+	require.NoError(t, err)
+	upstream := String("u1")
+
+	t.Run("unchanged weight does not create a revision", func(t *testing.T) {
+		creates = 0
+		target, err := client.Targets.Upsert(defaultCtx, upstream, &Target{
+			Target: String("10.0.0.2:80"),
+			Weight: Int(50),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 50, *target.Weight)
+		assert.Equal(t, 0, creates)
+
+		// an unset weight means the default weight
+		_, err = client.Targets.Upsert(defaultCtx, upstream, &Target{
+			Target: String("10.0.0.1:80"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0, creates)
+	})
+
+	t.Run("changed weight creates a revision", func(t *testing.T) {
+		creates = 0
+		target, err := client.Targets.Upsert(defaultCtx, upstream, &Target{
+			Target: String("10.0.0.2:80"),
+			Weight: Int(70),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 70, *target.Weight)
+		assert.Equal(t, 1, creates)
+	})
+
+	t.Run("new target is created", func(t *testing.T) {
+		creates = 0
+		target, err := client.Targets.Upsert(defaultCtx, upstream, &Target{
+			Target: String("10.0.0.3:80"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.3:80", *target.Target)
+		assert.Equal(t, 1, creates)
+	})
+
+	t.Run("weight 0 disables an existing target", func(t *testing.T) {
+		creates = 0
+		target, err := client.Targets.Upsert(defaultCtx, upstream, &Target{
+			Target: String("10.0.0.1:80"),
+			Weight: Int(0),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 0, *target.Weight)
+		assert.Equal(t, 1, creates)
+	})
+
+	t.Run("weight 0 for a missing target is a no-op", func(t *testing.T) {
+		creates = 0
+		target, err := client.Targets.Upsert(defaultCtx, upstream, &Target{
+			Target: String("10.0.0.4:80"),
+			Weight: Int(0),
+		})
+		require.NoError(t, err)
+		assert.Nil(t, target)
+		assert.Equal(t, 0, creates)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		_, err := client.Targets.Upsert(defaultCtx, nil, &Target{Target: String("10.0.0.1:80")})
+		assert.Error(t, err)
+		_, err = client.Targets.Upsert(defaultCtx, upstream, &Target{})
+		assert.Error(t, err)
+	})
+}
+
+// fakeResolver resolves the hosts it knows and returns an error for the
+// others.
+type fakeResolver map[string][]string
+
+func (r fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if host == "timeout.example.com" {
+		return nil, errors.New("i/o timeout")
+	}
+	addrs, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+func TestTargetValidateDNS(t *testing.T) {
+	client, err := NewClientWithOptions("http://localhost:8001",
+		WithResolver(fakeResolver{"api.example.com": {"10.0.0.1"}}))
+	require.NoError(t, err)
+
+	for _, target := range []string{
+		"api.example.com:8080",
+		"api.example.com",
+		"10.0.0.2:80",
+		"10.0.0.2",
+		"[::1]:80",
+		"[::1]",
+	} {
+		assert.NoError(t, client.Targets.ValidateDNS(defaultCtx, &Target{Target: String(target)}), target)
+	}
+
+	err = client.Targets.ValidateDNS(defaultCtx, &Target{Target: String("api.exmaple.com:8080")})
+	assert.EqualError(t, err, "target 'api.exmaple.com:8080': host 'api.exmaple.com' not found (NXDOMAIN)")
+
+	err = client.Targets.ValidateDNS(defaultCtx, &Target{Target: String("timeout.example.com:80")})
+	assert.ErrorContains(t, err, "resolving host 'timeout.example.com': i/o timeout")
+
+	assert.Error(t, client.Targets.ValidateDNS(defaultCtx, &Target{}))
+}
@@ -0,0 +1,48 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetFromString(t *testing.T) {
+	tests := []struct {
+		in       string
+		expected string
+	}{
+		{in: "10.0.0.1:8080", expected: "10.0.0.1:8080"},
+		{in: "api.internal:443", expected: "api.internal:443"},
+		{in: "api.internal", expected: "api.internal:8000"},
+		{in: "10.0.0.1", expected: "10.0.0.1:8000"},
+		{in: "[::1]:80", expected: "[::1]:80"},
+		{in: "::1", expected: "[::1]:8000"},
+		{in: "[2001:db8::1]", expected: "[2001:db8::1]:8000"},
+		{in: "my_host.example.com.:8443", expected: "my_host.example.com.:8443"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			target, err := TargetFromString(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, &Target{Target: String(tt.expected), Weight: Int(100)}, target)
+		})
+	}
+
+	for _, in := range []string{
+		"",
+		":8080",
+		"api.internal:",
+		"api.internal:http",
+		"api.internal:0",
+		"api.internal:65536",
+		"api..internal:80",
+		"-api.internal:80",
+		"http://api.internal:80",
+		"api internal:80",
+		"1:2:3:80",
+	} {
+		_, err := TargetFromString(in)
+		assert.Error(t, err, in)
+	}
+}
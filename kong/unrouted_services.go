@@ -0,0 +1,72 @@
+package kong
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// unroutedServicesWorkers is the number of Services whose Routes are
+// fetched concurrently by FindServicesWithoutRoutes.
+const unroutedServicesWorkers = 4
+
+// FindServicesWithoutRoutes returns the Services which have no Route, in
+// the order they are listed by Kong. Such Services receive no traffic and
+// are often left behind by past configurations.
+// Services deleted while their Routes are fetched are skipped.
+func (c *Client) FindServicesWithoutRoutes(ctx context.Context) ([]*Service, error) {
+	services, err := c.Services.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		lock     sync.Mutex
+		unrouted = make([]bool, len(services))
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	queue := make(chan int)
+	for i := 0; i < unroutedServicesWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				// a single Route is enough to tell that a Service is routed
+				routes, _, err := c.Routes.ListForService(ctx, services[i].ID, &ListOpt{Size: 1})
+				if IsNotFoundErr(err) {
+					continue
+				}
+				lock.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("listing routes of service %s: %w",
+							services[i].FriendlyName(), err)
+						cancel()
+					}
+				} else {
+					unrouted[i] = len(routes) == 0
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	for i := range services {
+		queue <- i
+	}
+	close(queue)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	var res []*Service
+	for i, service := range services {
+		if unrouted[i] {
+			res = append(res, service)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,39 @@
+package kong
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindServicesWithoutRoutes(t *testing.T) {
+	// s3 was deleted after being listed
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/services": fakeList(`[
+			{"id": "s1", "name": "routed"},
+			{"id": "s2", "name": "unrouted"},
+			{"id": "s3", "name": "deleted"}
+		]`),
+		"/services/s1/routes": {body: `{"data": [{"id": "r1"}], "next": "/services/s1/routes?offset=x"}`},
+		"/services/s2/routes": fakeList(`[]`),
+	})
+	client := newFakeKongClient(t, srv)
+
+	services, err := client.FindServicesWithoutRoutes(defaultCtx)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	assert.Equal(t, "s2", *services[0].ID)
+}
+
+func TestFindServicesWithoutRoutesError(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/services": fakeList(`[{"id": "s1", "name": "svc1"}]`),
+		"*":         {status: http.StatusInternalServerError},
+	})
+	client := newFakeKongClient(t, srv)
+
+	_, err := client.FindServicesWithoutRoutes(defaultCtx)
+	assert.ErrorContains(t, err, "listing routes of service svc1")
+}
@@ -1,5 +1,7 @@
 package kong
 
+import "fmt"
+
 // Upstream represents an Upstream in Kong.
 // +k8s:deepcopy-gen=true
 type Upstream struct {
@@ -127,6 +129,13 @@ type UpstreamNodeHealth struct {
 	Tags      []*string   `json:"tags,omitempty" yaml:"tags,omitempty"`
 }
 
+// UpstreamHealth represents the health of the targets of an upstream
+// +k8s:deepcopy-gen=true
+type UpstreamHealth struct {
+	Upstream *Upstream             `json:"upstream,omitempty" yaml:"upstream,omitempty"`
+	Targets  []*UpstreamNodeHealth `json:"targets,omitempty" yaml:"targets,omitempty"`
+}
+
 // FriendlyName returns the endpoint key name or ID.
 func (u *Upstream) FriendlyName() string {
 	if u.Name != nil {
@@ -137,3 +146,107 @@ func (u *Upstream) FriendlyName() string {
 	}
 	return ""
 }
+
+// hashInput returns the value of hash_on, or of hash_fallback if fallback
+// is true, along with the name of the header, cookie, query argument or
+// URI capture it refers to. Kong defaults both fields to "none".
+func (u *Upstream) hashInput(fallback bool) (string, *string) {
+	hash := u.HashOn
+	if fallback {
+		hash = u.HashFallback
+	}
+	if hash == nil {
+		return "none", nil
+	}
+	switch *hash {
+	case "header":
+		if fallback {
+			return *hash, u.HashFallbackHeader
+		}
+		return *hash, u.HashOnHeader
+	case "cookie":
+		return *hash, u.HashOnCookie
+	case "query_arg":
+		if fallback {
+			return *hash, u.HashFallbackQueryArg
+		}
+		return *hash, u.HashOnQueryArg
+	case "uri_capture":
+		if fallback {
+			return *hash, u.HashFallbackURICapture
+		}
+		return *hash, u.HashOnURICapture
+	}
+	return *hash, nil
+}
+
+// hashNameFields are the fields holding the name of the input of hash_on
+// and hash_fallback, if Kong requires one.
+var hashNameFields = map[string][2]string{
+	"header":      {"hash_on_header", "hash_fallback_header"},
+	"cookie":      {"hash_on_cookie", "hash_on_cookie"},
+	"query_arg":   {"hash_on_query_arg", "hash_fallback_query_arg"},
+	"uri_capture": {"hash_on_uri_capture", "hash_fallback_uri_capture"},
+}
+
+// ValidateHashing returns the combinations of the hash_* fields of an
+// Upstream which Kong rejects:
+//   - hash_fallback is set while hash_on is "none" or "cookie",
+//   - hash_fallback is the same as hash_on, unless both hash on a header,
+//     query argument or URI capture with different names,
+//   - the header, cookie, query argument or URI capture to hash on is
+//     missing.
+//
+// It returns nil if the Upstream is valid.
+func (u *Upstream) ValidateHashing() []string {
+	if u == nil {
+		return nil
+	}
+	var warnings []string
+	hashOn, onName := u.hashInput(false)
+	fallback, fallbackName := u.hashInput(true)
+	switch {
+	case fallback == "none":
+	case hashOn == "none" || hashOn == "cookie":
+		warnings = append(warnings,
+			fmt.Sprintf("'hash_fallback' must be 'none' when 'hash_on' is '%s'", hashOn))
+	case fallback == hashOn && (onName == nil || fallbackName == nil || *onName == *fallbackName):
+		warnings = append(warnings,
+			fmt.Sprintf("'hash_fallback' must not hash on the same input as 'hash_on' ('%s')", hashOn))
+	}
+	if fields, ok := hashNameFields[hashOn]; ok && isEmptyString(onName) {
+		warnings = append(warnings,
+			fmt.Sprintf("'%s' must be set when 'hash_on' is '%s'", fields[0], hashOn))
+	}
+	// both hash on hash_on_cookie when hash_on and hash_fallback are "cookie"
+	if fields, ok := hashNameFields[fallback]; ok && isEmptyString(fallbackName) &&
+		!(fallback == "cookie" && hashOn == "cookie") {
+		warnings = append(warnings,
+			fmt.Sprintf("'%s' must be set when 'hash_fallback' is '%s'", fields[1], fallback))
+	}
+	return warnings
+}
+
+// NormalizeHashing fixes the hash_* combinations reported by
+// ValidateHashing which have an obvious fix: hash_fallback is reset to
+// "none" if hash_on doesn't allow a fallback or if it hashes on the same
+// input as hash_on, and the name of the fallback input is cleared along
+// with it. Missing header, cookie, query argument or URI capture names
+// can't be guessed and are left for ValidateHashing to report.
+func (u *Upstream) NormalizeHashing() {
+	if u == nil {
+		return
+	}
+	hashOn, onName := u.hashInput(false)
+	fallback, fallbackName := u.hashInput(true)
+	if fallback == "none" {
+		return
+	}
+	if hashOn == "none" || hashOn == "cookie" ||
+		(fallback == hashOn && (onName == nil || fallbackName == nil || *onName == *fallbackName)) {
+		u.HashFallback = String("none")
+		u.HashFallbackHeader = nil
+		u.HashFallbackQueryArg = nil
+		u.HashFallbackURICapture = nil
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 )
 
 // AbstractUpstreamNodeHealthService handles Upstream Node Healths in Kong.
@@ -67,3 +68,57 @@ func (s *UpstreamNodeHealthService) ListAll(
 	}
 	return upstreamNodeHealths, nil
 }
+
+// AllUpstreamHealth fetches the health of the targets of every upstream in
+// Kong, using workers goroutines to fetch the health of upstreams
+// concurrently. The result is keyed by the name of the upstreams, or by
+// their ID for upstreams without a name. Upstreams without targets have
+// an empty health.
+// The first error which occurs is returned.
+func (c *Client) AllUpstreamHealth(ctx context.Context, workers int) (map[string]*UpstreamHealth, error) {
+	upstreams, err := c.Upstreams.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		lock     sync.Mutex
+		res      = make(map[string]*UpstreamHealth, len(upstreams))
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	queue := make(chan *Upstream)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for u := range queue {
+				targets, err := c.UpstreamNodeHealth.ListAll(ctx, u.ID)
+				lock.Lock()
+				if err != nil {
+					if firstErr == nil {
+						firstErr = fmt.Errorf("fetching health of upstream %s: %w", u.FriendlyName(), err)
+						cancel()
+					}
+				} else {
+					res[u.FriendlyName()] = &UpstreamHealth{Upstream: u, Targets: targets}
+				}
+				lock.Unlock()
+			}
+		}()
+	}
+	for _, u := range upstreams {
+		queue <- u
+	}
+	close(queue)
+	wg.Wait()
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	return res, nil
+}
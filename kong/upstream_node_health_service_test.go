@@ -1,6 +1,7 @@
 package kong
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -56,3 +57,41 @@ func TestUpstreamNodeHealthService(T *testing.T) {
 	err = client.Upstreams.Delete(defaultCtx, fixtureUpstream.ID)
 	assert.NoError(err)
 }
+
+func TestAllUpstreamHealth(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/upstreams": fakeList(`[
+			{"id": "u1", "name": "foo"},
+			{"id": "u2", "name": "bar"},
+			{"id": "u3"}
+		]`),
+		"/upstreams/u1/health": fakeList(`[
+			{"id": "t1", "target": "10.0.0.1:80", "health": "HEALTHY"},
+			{"id": "t2", "target": "10.0.0.2:80", "health": "UNHEALTHY"}
+		]`),
+		"/upstreams/u2/health": fakeList(`[{"id": "t3", "target": "10.0.0.3:80", "health": "HEALTHY"}]`),
+		"/upstreams/u3/health": fakeList(`[]`),
+	})
+	client := newFakeKongClient(t, srv)
+
+	health, err := client.AllUpstreamHealth(defaultCtx, 2)
+	require.NoError(t, err)
+	require.Len(t, health, 3)
+	require.Len(t, health["foo"].Targets, 2)
+	assert.Equal(t, "UNHEALTHY", *health["foo"].Targets[1].Health)
+	require.Len(t, health["bar"].Targets, 1)
+	assert.Equal(t, "10.0.0.3:80", *health["bar"].Targets[0].Target)
+	assert.Equal(t, "u3", *health["u3"].Upstream.ID)
+	assert.Empty(t, health["u3"].Targets)
+}
+
+func TestAllUpstreamHealthError(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/upstreams": fakeList(`[{"id": "u1", "name": "foo"}]`),
+		"*":          {status: http.StatusInternalServerError},
+	})
+	client := newFakeKongClient(t, srv)
+
+	_, err := client.AllUpstreamHealth(defaultCtx, 0)
+	assert.ErrorContains(t, err, "fetching health of upstream foo")
+}
@@ -0,0 +1,127 @@
+package kong
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpstreamValidateHashing(t *testing.T) {
+	tests := []struct {
+		name       string
+		upstream   *Upstream
+		expected   []string
+		normalized *Upstream
+	}{
+		{
+			name:     "defaults",
+			upstream: &Upstream{Name: String("foo")},
+		},
+		{
+			name: "consumer with ip fallback",
+			upstream: &Upstream{
+				HashOn:       String("consumer"),
+				HashFallback: String("ip"),
+			},
+		},
+		{
+			name: "fallback equal to hash_on",
+			upstream: &Upstream{
+				HashOn:       String("ip"),
+				HashFallback: String("ip"),
+			},
+			expected: []string{"'hash_fallback' must not hash on the same input as 'hash_on' ('ip')"},
+			normalized: &Upstream{
+				HashOn:       String("ip"),
+				HashFallback: String("none"),
+			},
+		},
+		{
+			name: "fallback on the same header",
+			upstream: &Upstream{
+				HashOn:             String("header"),
+				HashOnHeader:       String("X-Tenant"),
+				HashFallback:       String("header"),
+				HashFallbackHeader: String("X-Tenant"),
+			},
+			expected: []string{"'hash_fallback' must not hash on the same input as 'hash_on' ('header')"},
+			normalized: &Upstream{
+				HashOn:       String("header"),
+				HashOnHeader: String("X-Tenant"),
+				HashFallback: String("none"),
+			},
+		},
+		{
+			name: "fallback on another header",
+			upstream: &Upstream{
+				HashOn:             String("header"),
+				HashOnHeader:       String("X-Tenant"),
+				HashFallback:       String("header"),
+				HashFallbackHeader: String("X-User"),
+			},
+		},
+		{
+			name: "fallback without hash_on",
+			upstream: &Upstream{
+				HashFallback:   String("query_arg"),
+				HashOnQueryArg: String("tenant"),
+			},
+			expected: []string{
+				"'hash_fallback' must be 'none' when 'hash_on' is 'none'",
+				"'hash_fallback_query_arg' must be set when 'hash_fallback' is 'query_arg'",
+			},
+			normalized: &Upstream{
+				HashOnQueryArg: String("tenant"),
+				HashFallback:   String("none"),
+			},
+		},
+		{
+			name: "cookie without name",
+			upstream: &Upstream{
+				HashOn:           String("cookie"),
+				HashOnCookiePath: String("/"),
+			},
+			expected: []string{"'hash_on_cookie' must be set when 'hash_on' is 'cookie'"},
+		},
+		{
+			name: "cookie with fallback",
+			upstream: &Upstream{
+				HashOn:       String("cookie"),
+				HashOnCookie: String("session"),
+				HashFallback: String("cookie"),
+			},
+			expected: []string{"'hash_fallback' must be 'none' when 'hash_on' is 'cookie'"},
+			normalized: &Upstream{
+				HashOn:       String("cookie"),
+				HashOnCookie: String("session"),
+				HashFallback: String("none"),
+			},
+		},
+		{
+			name: "cookie fallback without name",
+			upstream: &Upstream{
+				HashOn:       String("consumer"),
+				HashFallback: String("cookie"),
+			},
+			expected: []string{"'hash_on_cookie' must be set when 'hash_fallback' is 'cookie'"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.upstream.ValidateHashing())
+
+			normalized := tt.upstream.DeepCopy()
+			normalized.NormalizeHashing()
+			if tt.normalized == nil {
+				assert.Equal(t, tt.upstream, normalized)
+				return
+			}
+			assert.Equal(t, tt.normalized, normalized)
+			assert.Empty(t, normalized.ValidateHashing())
+		})
+	}
+
+	var u *Upstream
+	assert.Nil(t, u.ValidateHashing())
+	u.NormalizeHashing()
+}
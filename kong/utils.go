@@ -8,6 +8,8 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/imdario/mergo"
 	"github.com/tidwall/gjson"
@@ -52,6 +54,119 @@ func StringSlice(elements ...string) []*string {
 	return res
 }
 
+// StringSliceFromInterface converts v, a []string or a []interface{}
+// holding strings such as arrays decoded from JSON or YAML, to a []*string
+// as used by the fields of entities. A nil v returns a nil slice.
+// An error is returned if v or one of its elements isn't a string.
+func StringSliceFromInterface(v interface{}) ([]*string, error) {
+	switch elements := v.(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return StringSlice(elements...), nil
+	case []interface{}:
+		res := make([]*string, 0, len(elements))
+		for i, element := range elements {
+			s, ok := element.(string)
+			if !ok {
+				return nil, fmt.Errorf("element %d is a %T, not a string", i, element)
+			}
+			res = append(res, String(s))
+		}
+		return res, nil
+	}
+	return nil, fmt.Errorf("unsupported type %T, expected a slice of strings", v)
+}
+
+// standardHTTPMethods is the set of HTTP methods defined by RFC 9110 and
+// RFC 5789 (PATCH).
+var standardHTTPMethods = map[string]struct{}{
+	http.MethodGet:     {},
+	http.MethodHead:    {},
+	http.MethodPost:    {},
+	http.MethodPut:     {},
+	http.MethodPatch:   {},
+	http.MethodDelete:  {},
+	http.MethodConnect: {},
+	http.MethodOptions: {},
+	http.MethodTrace:   {},
+}
+
+// ValidateHTTPMethods validates methods against the standard HTTP methods,
+// ignoring case and surrounding spaces. customMethods can be used to allow
+// additional, non-standard verbs such as "PURGE"; they are compared
+// case-insensitively. methods are not modified, see NormalizeHTTPMethods.
+func ValidateHTTPMethods(methods []*string, customMethods ...string) error {
+	for i, m := range methods {
+		if m == nil {
+			return fmt.Errorf("method at index %d cannot be nil", i)
+		}
+		method := strings.ToUpper(strings.TrimSpace(*m))
+		if _, ok := standardHTTPMethods[method]; !ok && !containsFold(customMethods, method) {
+			return fmt.Errorf("invalid HTTP method: '%s'", *m)
+		}
+	}
+	return nil
+}
+
+// NormalizeHTTPMethods returns a copy of methods, trimmed and uppercased
+// as Kong expects them. nil methods are kept as is.
+func NormalizeHTTPMethods(methods []*string) []*string {
+	if methods == nil {
+		return nil
+	}
+	res := make([]*string, len(methods))
+	for i, m := range methods {
+		if m != nil {
+			res[i] = String(strings.ToUpper(strings.TrimSpace(*m)))
+		}
+	}
+	return res
+}
+
+// validateEntityName validates name with ValidateEntityName if the client
+// was created with WithEntityNameValidation. Unset names are valid.
+func (c *Client) validateEntityName(name *string) error {
+	if !c.validateNames || name == nil {
+		return nil
+	}
+	return ValidateEntityName(*name)
+}
+
+// ValidateEntityName validates name against the rules Kong applies to the
+// names of entities such as Services and Routes: names must only contain
+// alphanumerics and '.', '-', '_' or '~' characters. As in Kong, non-ASCII
+// UTF-8 characters are accepted.
+func ValidateEntityName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if !utf8.ValidString(name) {
+		return fmt.Errorf("invalid name '%s': must be valid UTF-8", name)
+	}
+	for _, r := range name {
+		switch {
+		case r > unicode.MaxASCII,
+			'a' <= r && r <= 'z',
+			'A' <= r && r <= 'Z',
+			'0' <= r && r <= '9',
+			strings.ContainsRune(".-_~", r):
+			continue
+		}
+		return fmt.Errorf("invalid name '%s': it must only contain alphanumeric and '., -, _, ~' characters", name)
+	}
+	return nil
+}
+
+func containsFold(elements []string, s string) bool {
+	for _, e := range elements {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
+
 func stringArrayToString(arr []*string) string {
 	if arr == nil {
 		return "nil"
@@ -127,9 +242,18 @@ func HTTPClientWithHeaders(client *http.Client,
 	return res
 }
 
+// majorOnlyVersion matches versions truncated to their major version, as
+// reported by some proxies or forks.
+var majorOnlyVersion = regexp.MustCompile(`^(\d+)(-enterprise-edition)?$`)
+
 // ParseSemanticVersion creates a semantic version from the version
 // returned by Kong.
+// Truncated versions are completed with zeros: "3.4" is parsed as 3.4.0
+// and "3" as 3.0.0.
 func ParseSemanticVersion(v string) (Version, error) {
+	if m := majorOnlyVersion.FindStringSubmatch(v); m != nil {
+		v = m[1] + ".0" + m[2]
+	}
 	re := regexp.MustCompile(`((?:\d+\.\d+\.\d+)|(?:\d+\.\d+))(?:[\.-](\d+))?(?:\-?(.+)$|$)`)
 	m := re.FindStringSubmatch(v)
 	if len(m) != versionParts {
@@ -366,23 +490,32 @@ func fillConfigRecord(schema gjson.Result, config Configuration) Configuration {
 //		"hash_fallback": "none",
 //	 ...
 //	}
-func flattenDefaultsSchema(schema gjson.Result) Schema {
+//
+// If requiredOnly is set, only the fields marked as required are included.
+func flattenDefaultsSchema(schema gjson.Result, requiredOnly bool) Schema {
 	fields := schema.Get("fields")
 	if fields.Exists() {
-		return flattenLuaSchema(fields)
+		return flattenLuaSchema(fields, requiredOnly)
 	}
 	properties := schema.Get("properties")
 	if properties.Exists() {
-		return flattenJSONSchema(properties)
+		required := make(map[string]bool)
+		for _, name := range schema.Get("required").Array() {
+			required[name.String()] = true
+		}
+		return flattenJSONSchema(properties, required, requiredOnly)
 	}
 	return Schema{}
 }
 
-func flattenJSONSchema(value gjson.Result) Schema {
+func flattenJSONSchema(value gjson.Result, required map[string]bool, requiredOnly bool) Schema {
 	results := Schema{}
 
 	value.ForEach(func(key, value gjson.Result) bool {
 		name := key.String()
+		if requiredOnly && !required[name] {
+			return true
+		}
 
 		ftype := value.Get("type")
 		// when type==object and additionalProperties==false, the object
@@ -393,7 +526,7 @@ func flattenJSONSchema(value gjson.Result) Schema {
 			(!additionalProperties.Exists() ||
 				(additionalProperties.Exists() &&
 					additionalProperties.Bool())) {
-			newSubConfig := flattenDefaultsSchema(value)
+			newSubConfig := flattenDefaultsSchema(value, requiredOnly)
 			results[name] = newSubConfig
 			return true
 		}
@@ -409,7 +542,7 @@ func flattenJSONSchema(value gjson.Result) Schema {
 	return results
 }
 
-func flattenLuaSchema(value gjson.Result) Schema {
+func flattenLuaSchema(value gjson.Result, requiredOnly bool) Schema {
 	results := Schema{}
 
 	value.ForEach(func(key, value gjson.Result) bool {
@@ -420,10 +553,13 @@ func flattenLuaSchema(value gjson.Result) Schema {
 			fname = k
 			break
 		}
+		if requiredOnly && !value.Get(fname+".required").Bool() {
+			return true
+		}
 
 		ftype := value.Get(fname + ".type")
 		if ftype.String() == "record" {
-			newSubConfig := flattenDefaultsSchema(value.Get(fname))
+			newSubConfig := flattenDefaultsSchema(value.Get(fname), requiredOnly)
 			results[fname] = newSubConfig
 			return true
 		}
@@ -439,13 +575,13 @@ func flattenLuaSchema(value gjson.Result) Schema {
 	return results
 }
 
-func getDefaultsObj(schema Schema) ([]byte, error) {
+func getDefaultsObj(schema Schema, requiredOnly bool) ([]byte, error) {
 	jsonSchema, err := json.Marshal(&schema)
 	if err != nil {
 		return nil, err
 	}
 	gjsonSchema := gjson.ParseBytes((jsonSchema))
-	defaults := flattenDefaultsSchema(gjsonSchema)
+	defaults := flattenDefaultsSchema(gjsonSchema, requiredOnly)
 	jsonSchemaWithDefaults, err := json.Marshal(&defaults)
 	if err != nil {
 		return nil, err
@@ -469,8 +605,29 @@ func (t zeroValueTransformer) Transformer(typ reflect.Type) func(dst, src reflec
 	return nil
 }
 
+// FillDefaultsOpt configures how defaults are filled by
+// FillEntityDefaultsWithOpts.
+type FillDefaultsOpt struct {
+	// FillRequiredOnly only fills the fields which are required by the
+	// schema, to produce a minimal valid entity. Optional fields are
+	// left untouched.
+	FillRequiredOnly bool
+	// KongVersion is the version of Kong the entity is meant for. If set,
+	// the defaults of fields which depend on the Kong version are filled
+	// for this version, even if the schema doesn't declare them: Routes
+	// buffer requests and responses by default since Kong 2.3, and don't
+	// support buffering fields before.
+	KongVersion *Version
+}
+
 // FillEntityDefaults ingests entities' defaults from their schema.
 func FillEntityDefaults(entity interface{}, schema Schema) error {
+	return FillEntityDefaultsWithOpts(entity, schema, FillDefaultsOpt{})
+}
+
+// FillEntityDefaultsWithOpts ingests entities' defaults from their schema,
+// as configured by opts.
+func FillEntityDefaultsWithOpts(entity interface{}, schema Schema, opts FillDefaultsOpt) error {
 	if schema == nil {
 		return fmt.Errorf("filling defaults for '%T': provided schema is nil", entity)
 	}
@@ -489,7 +646,7 @@ func FillEntityDefaults(entity interface{}, schema Schema) error {
 	default:
 		return fmt.Errorf("unsupported entity: '%T'", entity)
 	}
-	defaults, err := getDefaultsObj(schema)
+	defaults, err := getDefaultsObj(schema, opts.FillRequiredOnly)
 	if err != nil {
 		return fmt.Errorf("parse schema for defaults: %w", err)
 	}
@@ -501,9 +658,27 @@ func FillEntityDefaults(entity interface{}, schema Schema) error {
 	); err != nil {
 		return fmt.Errorf("merge entity with its defaults: %w", err)
 	}
+	if route, ok := entity.(*Route); ok && opts.KongVersion != nil {
+		fillRouteBufferingDefaults(route, *opts.KongVersion)
+	}
 	return nil
 }
 
+// fillRouteBufferingDefaults fills the buffering fields of route as Kong
+// version does. Routes are left as is for versions without buffering:
+// explicit values are kept so that Kong reports them.
+func fillRouteBufferingDefaults(route *Route, version Version) {
+	if version.Compare(minBufferingVersion) < 0 {
+		return
+	}
+	if route.RequestBuffering == nil {
+		route.RequestBuffering = Bool(true)
+	}
+	if route.ResponseBuffering == nil {
+		route.ResponseBuffering = Bool(true)
+	}
+}
+
 // FillPluginsDefaults ingests plugin's defaults from its schema.
 // Takes in a plugin struct and mutate it in place.
 func FillPluginsDefaults(plugin *Plugin, schema Schema) error {
@@ -528,3 +703,88 @@ func FillPluginsDefaults(plugin *Plugin, schema Schema) error {
 	}
 	return nil
 }
+
+// FillVaultDefaults ingests vault's defaults from its schema, as returned
+// by the `/schemas/vaults/<name>` endpoint.
+// Takes in a vault struct and mutate it in place.
+func FillVaultDefaults(vault *Vault, schema Schema) error {
+	if vault == nil {
+		return fmt.Errorf("filling defaults for '%T': provided vault is nil", vault)
+	}
+	jsonb, err := json.Marshal(&schema)
+	if err != nil {
+		return err
+	}
+	gjsonSchema := gjson.ParseBytes((jsonb))
+	configSchema, err := getConfigSchema(gjsonSchema)
+	if err != nil {
+		return err
+	}
+	if vault.Config == nil {
+		vault.Config = make(Configuration)
+	}
+	vault.Config = fillConfigRecord(configSchema, vault.Config)
+	return nil
+}
+
+// secretFieldNames lists substrings of the names of fields holding secrets.
+var secretFieldNames = []string{
+	"password",
+	"secret",
+	"token",
+	"private_key",
+	"api_key",
+}
+
+const redactedValue = "[REDACTED]"
+
+// isSecretField returns true if the field name is likely to hold a secret,
+// such as a credential, a password or a private key.
+func isSecretField(name string) bool {
+	name = strings.ToLower(name)
+	if name == "key" {
+		return true
+	}
+	for _, s := range secretFieldNames {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// scrubSecrets returns a copy of the JSON document b in which the values
+// of fields likely to hold secrets are redacted.
+// Documents which aren't valid JSON are fully redacted.
+func scrubSecrets(b []byte) []byte {
+	if len(b) == 0 {
+		return b
+	}
+	var doc interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return []byte(redactedValue)
+	}
+	res, err := json.Marshal(scrubValue(doc))
+	if err != nil {
+		return []byte(redactedValue)
+	}
+	return res
+}
+
+func scrubValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, fv := range v {
+			if isSecretField(k) && fv != nil {
+				v[k] = redactedValue
+				continue
+			}
+			v[k] = scrubValue(fv)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = scrubValue(v[i])
+		}
+	}
+	return v
+}
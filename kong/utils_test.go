@@ -232,6 +232,66 @@ func TestStringArrayToString(t *testing.T) {
 	assert.Equal(stringArrayToString(nil), "nil")
 }
 
+func TestValidateHTTPMethods(t *testing.T) {
+	t.Run("valid methods", func(t *testing.T) {
+		methods := StringSlice("GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS", "CONNECT", "TRACE")
+		assert.NoError(t, ValidateHTTPMethods(methods))
+	})
+
+	t.Run("lowercase methods are valid and not modified", func(t *testing.T) {
+		methods := StringSlice("get", "Post", " delete ")
+		assert.NoError(t, ValidateHTTPMethods(methods))
+		assert.Equal(t, StringSlice("get", "Post", " delete "), methods)
+	})
+
+	t.Run("invalid method", func(t *testing.T) {
+		methods := StringSlice("GET", "FETCH")
+		err := ValidateHTTPMethods(methods)
+		assert.EqualError(t, err, "invalid HTTP method: 'FETCH'")
+	})
+
+	t.Run("custom methods can be allowed", func(t *testing.T) {
+		methods := StringSlice("purge", "GET")
+		assert.Error(t, ValidateHTTPMethods(methods))
+		assert.NoError(t, ValidateHTTPMethods(methods, "PURGE"))
+	})
+
+	t.Run("nil method", func(t *testing.T) {
+		assert.Error(t, ValidateHTTPMethods([]*string{nil}))
+	})
+}
+
+func TestNormalizeHTTPMethods(t *testing.T) {
+	methods := StringSlice("get", "Post", " delete ")
+	assert.Equal(t, StringSlice("GET", "POST", "DELETE"), NormalizeHTTPMethods(methods))
+	assert.Equal(t, StringSlice("get", "Post", " delete "), methods)
+	assert.Equal(t, []*string{nil, String("PURGE")}, NormalizeHTTPMethods([]*string{nil, String("purge")}))
+	assert.Nil(t, NormalizeHTTPMethods(nil))
+}
+
+func TestValidateEntityName(t *testing.T) {
+	for _, name := range []string{
+		"foo",
+		"Foo-Bar_baz.v1~2",
+		"123",
+		"ñandú",
+	} {
+		assert.NoError(t, ValidateEntityName(name), name)
+	}
+
+	for _, name := range []string{
+		"",
+		"foo bar",
+		"foo/bar",
+		"foo:bar",
+		"foo\tbar",
+		"foo@bar",
+		"\xff",
+	} {
+		assert.Error(t, ValidateEntityName(name), name)
+	}
+}
+
 func TestString(t *testing.T) {
 	assert := assert.New(t)
 
@@ -265,6 +325,55 @@ func TestStringSlice(t *testing.T) {
 	assert.Equal("bar", *arrp[1])
 }
 
+func TestStringSliceFromInterface(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		value    interface{}
+		expected []*string
+		err      string
+	}{
+		{
+			name:  "nil",
+			value: nil,
+		},
+		{
+			name:     "strings",
+			value:    []string{"/foo", "/bar"},
+			expected: StringSlice("/foo", "/bar"),
+		},
+		{
+			name:     "interfaces",
+			value:    []interface{}{"/foo", "/bar"},
+			expected: StringSlice("/foo", "/bar"),
+		},
+		{
+			name:     "empty interfaces",
+			value:    []interface{}{},
+			expected: []*string{},
+		},
+		{
+			name:  "non-string element",
+			value: []interface{}{"/foo", 42},
+			err:   "element 1 is a int, not a string",
+		},
+		{
+			name:  "not a slice",
+			value: "/foo",
+			err:   "unsupported type string, expected a slice of strings",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := StringSliceFromInterface(tc.value)
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
+
 func TestFixVersion(t *testing.T) {
 	tests := []struct {
 		version         string
@@ -316,6 +425,24 @@ func TestFixVersion(t *testing.T) {
 			expectedVersion: "3.0.0.0",
 			isEnterprise:    true,
 		},
+		{
+			version:         "3.4",
+			expectedVersion: "3.4.0",
+		},
+		{
+			version:         "3",
+			expectedVersion: "3.0.0",
+		},
+		{
+			version:         "3.4-enterprise-edition",
+			expectedVersion: "3.4.0",
+			isEnterprise:    true,
+		},
+		{
+			version:         "3-enterprise-edition",
+			expectedVersion: "3.0.0",
+			isEnterprise:    true,
+		},
 	}
 	for _, test := range tests {
 		v, err := ParseSemanticVersion(test.version)
@@ -330,6 +457,8 @@ func TestFixVersion(t *testing.T) {
 	invalidVersions := []string{
 		"",
 		"0-1-1",
+		"3.",
+		"enterprise-edition",
 	}
 	for _, inputVersion := range invalidVersions {
 		_, err := ParseSemanticVersion(inputVersion)
@@ -417,6 +546,10 @@ func TestFillRoutesDefaults(T *testing.T) {
 	client, err := NewTestClient(nil, nil)
 	assert.NoError(err)
 	assert.NotNil(client)
+	info, err := client.Root(defaultCtx)
+	assert.NoError(err)
+	version, err := ParseSemanticVersion(VersionFromInfo(info))
+	assert.NoError(err)
 
 	tests := []struct {
 		name     string
@@ -483,15 +616,16 @@ func TestFillRoutesDefaults(T *testing.T) {
 			fullSchema, err := client.Schemas.Get(defaultCtx, "routes")
 			assert.NoError(err)
 			assert.NotNil(fullSchema)
-			if err = FillEntityDefaults(r, fullSchema); err != nil {
+			if err = FillEntityDefaultsWithOpts(r, fullSchema, FillDefaultsOpt{KongVersion: &version}); err != nil {
 				t.Errorf(err.Error())
 			}
+			expected := tc.expected.DeepCopy()
+			if version.Compare(minBufferingVersion) >= 0 {
+				expected.RequestBuffering, expected.ResponseBuffering = Bool(true), Bool(true)
+			}
 			// Ignore fields to make tests pass despite small differences across releases.
-			opts := cmpopts.IgnoreFields(
-				Route{},
-				"RequestBuffering", "ResponseBuffering", "PathHandling",
-			)
-			if diff := cmp.Diff(r, tc.expected, opts); diff != "" {
+			opts := cmpopts.IgnoreFields(Route{}, "PathHandling")
+			if diff := cmp.Diff(r, expected, opts); diff != "" {
 				t.Errorf(diff)
 			}
 		})
@@ -1023,6 +1157,8 @@ func TestFillRoutesDefaultsFromJSONSchema(t *testing.T) {
 				RegexPriority:           Int(0),
 				StripPath:               Bool(true),
 				HTTPSRedirectStatusCode: Int(426),
+				RequestBuffering:        Bool(true),
+				ResponseBuffering:       Bool(true),
 			},
 		},
 		{
@@ -1040,6 +1176,8 @@ func TestFillRoutesDefaultsFromJSONSchema(t *testing.T) {
 				RegexPriority:           Int(0),
 				StripPath:               Bool(true),
 				HTTPSRedirectStatusCode: Int(426),
+				RequestBuffering:        Bool(true),
+				ResponseBuffering:       Bool(true),
 			},
 		},
 		{
@@ -1059,6 +1197,8 @@ func TestFillRoutesDefaultsFromJSONSchema(t *testing.T) {
 				RegexPriority:           Int(0),
 				StripPath:               Bool(false),
 				HTTPSRedirectStatusCode: Int(426),
+				RequestBuffering:        Bool(true),
+				ResponseBuffering:       Bool(true),
 			},
 		},
 	}
@@ -1068,10 +1208,7 @@ func TestFillRoutesDefaultsFromJSONSchema(t *testing.T) {
 			r := tc.route
 			require.NoError(t, FillEntityDefaults(r, schema))
 			// Ignore fields to make tests pass despite small differences across releases.
-			opts := cmpopts.IgnoreFields(
-				Route{},
-				"RequestBuffering", "ResponseBuffering", "PathHandling",
-			)
+			opts := cmpopts.IgnoreFields(Route{}, "PathHandling")
 			if diff := cmp.Diff(r, tc.expected, opts); diff != "" {
 				t.Errorf(diff)
 			}
@@ -1079,6 +1216,137 @@ func TestFillRoutesDefaultsFromJSONSchema(t *testing.T) {
 	}
 }
 
+func TestFillRoutesBufferingDefaults(t *testing.T) {
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(RouteSchema), &schema))
+	kong34 := MustNewVersion("3.4.0")
+	kong22 := MustNewVersion("2.2.1")
+	// withDefaults returns route with the defaults of RouteSchema.
+	withDefaults := func(route *Route) *Route {
+		route.PathHandling = String("v0")
+		route.PreserveHost = Bool(false)
+		route.Protocols = StringSlice("http", "https")
+		route.RegexPriority = Int(0)
+		route.StripPath = Bool(true)
+		route.HTTPSRedirectStatusCode = Int(426)
+		return route
+	}
+
+	tests := []struct {
+		name     string
+		version  *Version
+		route    *Route
+		expected *Route
+	}{
+		{
+			name:     "without version",
+			route:    &Route{Name: String("r1")},
+			expected: withDefaults(&Route{Name: String("r1")}),
+		},
+		{
+			name:    "buffering is enabled by default since 2.3",
+			version: &kong34,
+			route:   &Route{Name: String("r1"), ResponseBuffering: Bool(false)},
+			expected: withDefaults(&Route{
+				Name:              String("r1"),
+				RequestBuffering:  Bool(true),
+				ResponseBuffering: Bool(false),
+			}),
+		},
+		{
+			name:     "buffering is not filled before 2.3",
+			version:  &kong22,
+			route:    &Route{Name: String("r1")},
+			expected: withDefaults(&Route{Name: String("r1")}),
+		},
+		{
+			name:    "explicit buffering is kept before 2.3",
+			version: &kong22,
+			route:   &Route{Name: String("r1"), RequestBuffering: Bool(false)},
+			expected: withDefaults(&Route{
+				Name:             String("r1"),
+				RequestBuffering: Bool(false),
+			}),
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := tc.route
+			require.NoError(t, FillEntityDefaultsWithOpts(r, schema, FillDefaultsOpt{KongVersion: tc.version}))
+			if diff := cmp.Diff(tc.expected, r); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+}
+
+const RouteSchema = `{
+	"fields": [
+		{"id": {"type": "string", "uuid": true, "auto": true}},
+		{"name": {"type": "string"}},
+		{"protocols": {"type": "set", "required": true, "default": ["http", "https"],
+			"elements": {"type": "string", "one_of": ["grpc", "grpcs", "http", "https"]}}},
+		{"paths": {"type": "array", "elements": {"type": "string"}}},
+		{"https_redirect_status_code": {"type": "integer", "required": true, "default": 426,
+			"one_of": [426, 301, 302, 307, 308]}},
+		{"regex_priority": {"type": "integer", "default": 0}},
+		{"strip_path": {"type": "boolean", "required": true, "default": true}},
+		{"path_handling": {"type": "string", "default": "v0", "one_of": ["v0", "v1"]}},
+		{"preserve_host": {"type": "boolean", "required": true, "default": false}},
+		{"service": {"type": "foreign", "reference": "services"}}
+	]
+}`
+
+func TestFillEntityDefaultsWithOptsRequiredOnly(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   Schema
+		expected *Route
+	}{
+		{
+			name:   "lua schema",
+			schema: schemaFromJSON(t, RouteSchema),
+			expected: &Route{
+				Name:                    String("r1"),
+				Paths:                   []*string{String("/r1")},
+				PreserveHost:            Bool(false),
+				Protocols:               []*string{String("http"), String("https")},
+				StripPath:               Bool(true),
+				HTTPSRedirectStatusCode: Int(426),
+			},
+		},
+		{
+			name:   "json schema",
+			schema: getJSONSchemaFromFile(t, "testdata/routeJSONSchema.json"),
+			expected: &Route{
+				Name:      String("r1"),
+				Paths:     []*string{String("/r1")},
+				Protocols: []*string{String("http"), String("https")},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &Route{
+				Name:  String("r1"),
+				Paths: []*string{String("/r1")},
+			}
+			require.NoError(t, FillEntityDefaultsWithOpts(r, tc.schema, FillDefaultsOpt{FillRequiredOnly: true}))
+			if diff := cmp.Diff(r, tc.expected); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+
+	t.Run("all defaults are filled by default", func(t *testing.T) {
+		r := &Route{Name: String("r1")}
+		require.NoError(t, FillEntityDefaultsWithOpts(r, schemaFromJSON(t, RouteSchema), FillDefaultsOpt{}))
+		assert.Equal(t, Int(0), r.RegexPriority)
+		assert.Equal(t, String("v0"), r.PathHandling)
+	})
+}
+
 func TestFillTargetDefaultsFromJSONSchema(t *testing.T) {
 	// load route JSON schema from local file.
 	schema := getJSONSchemaFromFile(t, "testdata/targetJSONSchema.json")
@@ -1509,3 +1777,106 @@ func Test_FillPluginsDefaults_RequestTransformer(t *testing.T) {
 		})
 	}
 }
+
+const AWSVaultSchema = `{
+	"fields": [
+		{ "protocols": { "type": "set", "required": true, "default": ["http"] } },
+		{
+			"config": {
+				"type": "record",
+				"required": true,
+				"fields": [
+					{ "region": { "type": "string", "default": "us-east-1" } },
+					{ "endpoint_url": { "type": "string", "default": "https://secretsmanager.us-east-1.amazonaws.com" } },
+					{ "assume_role_arn": { "type": "string" } },
+					{ "ttl": { "type": "integer", "default": 0 } }
+				]
+			}
+		}
+	]
+}`
+
+func Test_FillVaultDefaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		vault    *Vault
+		expected *Vault
+	}{
+		{
+			name: "fills defaults of an empty config",
+			vault: &Vault{
+				Name:   String("aws"),
+				Prefix: String("aws-vault"),
+			},
+			expected: &Vault{
+				Name:   String("aws"),
+				Prefix: String("aws-vault"),
+				Config: Configuration{
+					"region":          "us-east-1",
+					"endpoint_url":    "https://secretsmanager.us-east-1.amazonaws.com",
+					"assume_role_arn": nil,
+					"ttl":             float64(0),
+				},
+			},
+		},
+		{
+			name: "does not override set fields",
+			vault: &Vault{
+				Name:   String("aws"),
+				Prefix: String("aws-vault"),
+				Config: Configuration{
+					"region": "eu-west-1",
+					"ttl":    float64(60),
+				},
+			},
+			expected: &Vault{
+				Name:   String("aws"),
+				Prefix: String("aws-vault"),
+				Config: Configuration{
+					"region":          "eu-west-1",
+					"endpoint_url":    "https://secretsmanager.us-east-1.amazonaws.com",
+					"assume_role_arn": nil,
+					"ttl":             float64(60),
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var schema Schema
+			require.NoError(t, json.Unmarshal([]byte(AWSVaultSchema), &schema))
+			require.NoError(t, FillVaultDefaults(tc.vault, schema))
+			if diff := cmp.Diff(tc.vault, tc.expected); diff != "" {
+				t.Errorf(diff)
+			}
+		})
+	}
+
+	t.Run("errors", func(t *testing.T) {
+		assert.Error(t, FillVaultDefaults(nil, Schema{}))
+		assert.Error(t, FillVaultDefaults(&Vault{}, Schema{}))
+	})
+}
+
+func Test_scrubSecrets(t *testing.T) {
+	b := scrubSecrets([]byte(`{
+		"name": "foo",
+		"key": "k",
+		"cert": "c",
+		"password": "p",
+		"config": {"client_secret": "s", "redis": {"token": null, "host": "h"}},
+		"keys": [{"private_key": "pk", "id": "1"}]
+	}`))
+	assert.JSONEq(t, `{
+		"name": "foo",
+		"key": "[REDACTED]",
+		"cert": "c",
+		"password": "[REDACTED]",
+		"config": {"client_secret": "[REDACTED]", "redis": {"token": null, "host": "h"}},
+		"keys": [{"private_key": "[REDACTED]", "id": "1"}]
+	}`, string(b))
+
+	assert.Equal(t, "[REDACTED]", string(scrubSecrets([]byte("password=foo"))))
+	assert.Nil(t, scrubSecrets(nil))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // AbstractVaultService handles Vaults in Kong.
@@ -29,10 +30,17 @@ type VaultService service
 // If an ID is specified, it will be used to
 // create a Vault in Kong, otherwise an ID
 // is auto-generated.
+// The backend of the Vault is checked against the backends loaded
+// by Kong, see Client.AvailableVaults, before the Vault is created. The
+// check is skipped when the backends are unknown, e.g. when the root
+// endpoint can't be read with the credentials of the client.
 func (s *VaultService) Create(ctx context.Context, vault *Vault) (*Vault, error) {
 	if vault == nil {
 		return nil, fmt.Errorf("cannot create a nil vault")
 	}
+	if err := s.checkBackend(ctx, vault.Name); err != nil {
+		return nil, err
+	}
 
 	endpoint := "/vaults"
 	method := "POST"
@@ -53,6 +61,26 @@ func (s *VaultService) Create(ctx context.Context, vault *Vault) (*Vault, error)
 	return &createdVault, nil
 }
 
+// checkBackend returns an error if Kong reports its vault backends and
+// backend isn't one of them. Errors reading the backends are ignored:
+// Kong validates the backend anyway when creating the Vault.
+func (s *VaultService) checkBackend(ctx context.Context, backend *string) error {
+	if isEmptyString(backend) {
+		return nil
+	}
+	available, err := s.client.AvailableVaults(ctx)
+	if err != nil || available == nil {
+		return nil
+	}
+	for _, name := range available {
+		if name == *backend {
+			return nil
+		}
+	}
+	return fmt.Errorf("vault backend '%s' is not available, available backends: [%s]",
+		*backend, strings.Join(available, ", "))
+}
+
 // Get fetches a Vault in Kong.
 func (s *VaultService) Get(ctx context.Context, prefixOrID *string) (*Vault, error) {
 	if isEmptyString(prefixOrID) {
@@ -1,6 +1,7 @@
 package kong
 
 import (
+	"net/http"
 	"testing"
 
 	"github.com/google/uuid"
@@ -221,3 +222,49 @@ func compareVaults(t *testing.T, expected, actual []*Vault) bool {
 
 	return compareSlices(expectedPrefixes, actualPrefixes)
 }
+
+func TestVaultsCreateUnavailableBackend(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"GET /": {body: `{"version": "3.1.0", "configuration": {
+			"loaded_vaults": {"env": true, "hcv": false}
+		}}`},
+		"POST /vaults": {status: http.StatusCreated, body: `{"id": "v1", "name": "env", "prefix": "my-env"}`},
+	})
+	client := newFakeKongClient(t, srv)
+
+	vaults, err := client.AvailableVaults(defaultCtx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"env"}, vaults)
+
+	_, err = client.Vaults.Create(defaultCtx, &Vault{Name: String("aws"), Prefix: String("my-aws")})
+	require.Error(t, err)
+	assert.Equal(t, "vault backend 'aws' is not available, available backends: [env]", err.Error())
+	assert.Zero(t, srv.count("POST /vaults"), "vault must not be created")
+
+	vault, err := client.Vaults.Create(defaultCtx, &Vault{Name: String("env"), Prefix: String("my-env")})
+	require.NoError(t, err)
+	assert.Equal(t, "v1", *vault.ID)
+	assert.Equal(t, 1, srv.count("POST /vaults"))
+}
+
+func TestVaultsCreateUnknownBackends(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		// the root endpoint has no configuration
+		"GET /":        {body: `{"version": "3.1.0"}`},
+		"POST /vaults": {status: http.StatusCreated, body: `{"id": "v1", "name": "aws", "prefix": "my-aws"}`},
+	})
+	client := newFakeKongClient(t, srv)
+
+	vaults, err := client.AvailableVaults(defaultCtx)
+	require.NoError(t, err)
+	assert.Nil(t, vaults)
+	_, err = client.Vaults.Create(defaultCtx, &Vault{Name: String("aws"), Prefix: String("my-aws")})
+	require.NoError(t, err)
+
+	// the root endpoint is forbidden by RBAC
+	srv.set("GET /", fakeResponse{status: http.StatusForbidden, body: `{"message": "forbidden"}`})
+	_, err = client.AvailableVaults(defaultCtx)
+	assert.Error(t, err)
+	_, err = client.Vaults.Create(defaultCtx, &Vault{Name: String("aws"), Prefix: String("my-aws")})
+	require.NoError(t, err)
+}
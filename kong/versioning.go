@@ -108,6 +108,28 @@ func (v Version) IsKongGatewayEnterprise() bool {
 	return v.version.Revision >= 0 || strings.Contains(v.str, "enterprise")
 }
 
+// Compare compares v to o and returns -1, 0 or 1 when v is respectively
+// lower than, equal to or greater than o.
+// Major, minor and patch digits are compared first; the revision of a four
+// digit version is only used as a tiebreaker when both versions have one, so
+// "1.3.0.0" and "1.3.0" compare as equal while "1.3.0.1" is greater than
+// "1.3.0.0".
+func (v Version) Compare(o Version) int {
+	return v.version.Compare(o.version)
+}
+
+// Satisfies reports whether v falls within the range described by
+// rangeStr. rangeStr accepts the same syntax as NewRange, which means that
+// a four digit enterprise version can be checked against a three digit
+// OSS constraint: "1.3.0.0" satisfies ">=1.3.0".
+func (v Version) Satisfies(rangeStr string) (bool, error) {
+	rng, err := NewRange(rangeStr)
+	if err != nil {
+		return false, err
+	}
+	return rng(v), nil
+}
+
 // NewRange creates an instance of a Range.
 // Valid ranges can consist of multiple comparisons and three/four digit versions:
 //   - "<1.0.0" || "<v1.0.0.0"
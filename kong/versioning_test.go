@@ -417,3 +417,53 @@ func TestVersioning_ForceNewRange(t *testing.T) {
 		_ = MustNewRange("<= invalid.range")
 	})
 }
+
+func TestVersioning_Compare(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "1.3.0.0", b: "1.3.0", expected: 0},
+		{a: "1.3.0", b: "1.3.0.0", expected: 0},
+		{a: "1.3.0.1", b: "1.3.0.0", expected: 1},
+		{a: "1.3.0.0", b: "1.3.0.1", expected: -1},
+		{a: "1.3.0.9", b: "1.3.1", expected: -1},
+		{a: "1.4.0.0", b: "1.3.9.9", expected: 1},
+		{a: "2.8.1.0", b: "2.8.1.0", expected: 0},
+	}
+	for _, test := range tests {
+		a := MustNewVersion(test.a)
+		b := MustNewVersion(test.b)
+		require.Equal(t, test.expected, a.Compare(b), "%s compared to %s", test.a, test.b)
+	}
+}
+
+func TestVersioning_Satisfies(t *testing.T) {
+	t.Run("four digit versions compare by their first three digits against three digit ranges", func(t *testing.T) {
+		tests := []struct {
+			versionStr string
+			rangeStr   string
+			expected   bool
+		}{
+			{versionStr: "1.3.0.0", rangeStr: ">=1.3.0", expected: true},
+			{versionStr: "1.3.0.0", rangeStr: "<=1.3.0", expected: true},
+			{versionStr: "1.3.0.0", rangeStr: ">1.3.0"},
+			{versionStr: "1.3.0.1", rangeStr: ">1.3.0"},
+			{versionStr: "1.3.0.1", rangeStr: ">=1.3.0 <1.4.0", expected: true},
+			{versionStr: "1.2.9.9", rangeStr: ">=1.3.0"},
+			{versionStr: "1.3.0.1", rangeStr: ">1.3.0.0", expected: true},
+			{versionStr: "1.3.0.0", rangeStr: ">1.3.0.0"},
+			{versionStr: "1.3.0.0", rangeStr: "<1.3.0.1", expected: true},
+		}
+		for _, test := range tests {
+			ok, err := MustNewVersion(test.versionStr).Satisfies(test.rangeStr)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, ok, "%s satisfies %s", test.versionStr, test.rangeStr)
+		}
+	})
+
+	t.Run("invalid range returns an error", func(t *testing.T) {
+		_, err := MustNewVersion("1.3.0.0").Satisfies("<= invalid.range")
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,81 @@
+package kong
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// warningHeaders are the response headers carrying the warnings collected
+// by Client, e.g. deprecation notices of Kong.
+var warningHeaders = []string{"Warning", "X-Kong-Deprecation"}
+
+// WarningHandler is called with the warnings sent by Kong in the response
+// to req.
+type WarningHandler func(req *http.Request, warnings []string)
+
+// LastWarnings returns the warnings sent by Kong in the Warning and
+// X-Kong-Deprecation headers of the last response received by the client,
+// or nil if there were none.
+// When the client is used concurrently, the last response is the last
+// one to arrive: use WithWarningHandler to get the warnings of every
+// request.
+func (c *Client) LastWarnings() []string {
+	c.warningsLock.RLock()
+	defer c.warningsLock.RUnlock()
+	return append([]string(nil), c.lastWarnings...)
+}
+
+// collectWarnings records the warnings of resp, the response to req.
+// In debug mode they are written to the logger as well: this is best
+// effort, write errors are ignored so that they don't fail the request.
+func (c *Client) collectWarnings(req *http.Request, resp *http.Response) {
+	warnings := responseWarnings(resp.Header)
+	c.warningsLock.Lock()
+	c.lastWarnings = warnings
+	c.warningsLock.Unlock()
+	if len(warnings) == 0 {
+		return
+	}
+
+	if c.warningHandler != nil {
+		c.warningHandler(req, append([]string(nil), warnings...))
+	}
+	if c.debug {
+		for _, warning := range warnings {
+			_, _ = fmt.Fprintf(c.logger, "warning: %s %s: %s\n",
+				req.Method, req.URL.Path, warning)
+		}
+	}
+}
+
+// responseWarnings returns the warnings of the headers listed in
+// warningHeaders. Only the text of Warning headers using the
+// `<code> <agent> "<text>"` format is kept.
+func responseWarnings(header http.Header) []string {
+	var warnings []string
+	for _, name := range warningHeaders {
+		for _, value := range header.Values(name) {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			if http.CanonicalHeaderKey(name) == "Warning" {
+				value = warningText(value)
+			}
+			warnings = append(warnings, value)
+		}
+	}
+	return warnings
+}
+
+// warningText returns the text of a Warning header value such as
+// `299 - "Deprecated API"`, or value itself if it doesn't use this format.
+func warningText(value string) string {
+	start := strings.Index(value, `"`)
+	end := strings.LastIndex(value, `"`)
+	if start < 0 || end <= start {
+		return value
+	}
+	return value[start+1 : end]
+}
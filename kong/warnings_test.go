@@ -0,0 +1,90 @@
+package kong
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarnings(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"/services/svc1": {
+			header: http.Header{
+				"Warning":            {`299 - "the 'foo' field is deprecated"`},
+				"X-Kong-Deprecation": {"use /services/{id}/routes instead"},
+			},
+			body: `{"id": "s1", "name": "svc1"}`,
+		},
+		"/services/svc2": {
+			status: http.StatusNotFound,
+			header: http.Header{"Warning": {"deprecated endpoint"}},
+			body:   `{"message": "Not found"}`,
+		},
+		"*": {body: `{"id": "s3", "name": "svc3"}`},
+	})
+
+	var handled []string
+	var logs bytes.Buffer
+	client, err := NewClientWithOptions(srv.URL, WithLogger(&logs),
+		WithWarningHandler(func(req *http.Request, warnings []string) {
+			for _, warning := range warnings {
+				handled = append(handled, req.URL.Path+": "+warning)
+			}
+		}))
+	require.NoError(t, err)
+	assert.Nil(t, client.LastWarnings())
+
+	_, err = client.Services.Get(defaultCtx, String("svc1"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"the 'foo' field is deprecated",
+		"use /services/{id}/routes instead",
+	}, client.LastWarnings())
+	assert.Empty(t, logs.String(), "warnings are only logged in debug mode")
+
+	client.SetDebugMode(true)
+	_, err = client.Services.Get(defaultCtx, String("svc2"))
+	assert.True(t, IsNotFoundErr(err))
+	assert.Equal(t, []string{"deprecated endpoint"}, client.LastWarnings())
+	assert.Contains(t, logs.String(), "warning: GET /services/svc2: deprecated endpoint\n")
+
+	_, err = client.Services.Get(defaultCtx, String("svc3"))
+	require.NoError(t, err)
+	assert.Nil(t, client.LastWarnings())
+
+	assert.Equal(t, []string{
+		"/services/svc1: the 'foo' field is deprecated",
+		"/services/svc1: use /services/{id}/routes instead",
+		"/services/svc2: deprecated endpoint",
+	}, handled)
+}
+
+// failingWarningWriter fails the writes of warnings, other debug logs are
+// discarded.
+type failingWarningWriter struct{}
+
+func (failingWarningWriter) Write(p []byte) (int, error) {
+	if bytes.HasPrefix(p, []byte("warning: ")) {
+		return 0, errors.New("disk full")
+	}
+	return len(p), nil
+}
+
+func TestWarningsLogErrorsAreIgnored(t *testing.T) {
+	srv := newFakeKong(t, map[string]fakeResponse{
+		"*": {header: http.Header{"Warning": {"deprecated endpoint"}}, body: `{"id": "s1", "name": "svc1"}`},
+	})
+
+	client, err := NewClientWithOptions(srv.URL, WithLogger(failingWarningWriter{}))
+	require.NoError(t, err)
+	client.SetDebugMode(true)
+
+	service, err := client.Services.Get(defaultCtx, String("svc1"))
+	require.NoError(t, err)
+	assert.Equal(t, "svc1", *service.Name)
+	assert.Equal(t, []string{"deprecated endpoint"}, client.LastWarnings())
+}
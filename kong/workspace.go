@@ -20,3 +20,11 @@ type WorkspaceEntity struct {
 	WorkspaceID      *string `json:"workspace_id,omitempty" yaml:"workspace_id,omitempty"`
 	WorkspaceName    *string `json:"workspace_name,omitempty" yaml:"workspace_name,omitempty"`
 }
+
+// WorkspaceMeta represents the metadata of a Workspace in Kong.
+// +k8s:deepcopy-gen=true
+type WorkspaceMeta struct {
+	// Counts holds the number of entities in the Workspace, per entity
+	// type, e.g. "services".
+	Counts map[string]int `json:"counts,omitempty" yaml:"counts,omitempty"`
+}
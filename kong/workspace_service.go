@@ -24,6 +24,11 @@ type AbstractWorkspaceService interface {
 	List(ctx context.Context, opt *ListOpt) ([]*Workspace, *ListOpt, error)
 	// ListAll fetches all workspaces in Kong.
 	ListAll(ctx context.Context) ([]*Workspace, error)
+	// Count returns the number of Workspaces in Kong.
+	Count(ctx context.Context) (int, error)
+	// Meta fetches the metadata of a Workspace in Kong, such as the
+	// number of entities it holds.
+	Meta(ctx context.Context, nameOrID *string) (*WorkspaceMeta, error)
 	// AddEntities adds entity ids given as a a comma delimited string
 	// to a given workspace in Kong. The response is a representation
 	// of the entity that was added to the workspace.
@@ -204,6 +209,47 @@ func (s *WorkspaceService) ListAll(ctx context.Context) ([]*Workspace, error) {
 	return workspaces, nil
 }
 
+// Count returns the number of Workspaces in Kong, paging through the
+// Workspaces without decoding them.
+func (s *WorkspaceService) Count(ctx context.Context) (int, error) {
+	var count int
+	opt := &ListOpt{Size: pageSize}
+	for opt != nil {
+		var data []json.RawMessage
+		var err error
+		data, opt, err = s.client.list(ctx, "/workspaces/", opt)
+		if err != nil {
+			return 0, err
+		}
+		count += len(data)
+	}
+	return count, nil
+}
+
+// Meta fetches the metadata of a Workspace in Kong, such as the number of
+// entities of each type it holds.
+// This endpoint is only available on Kong Gateway Enterprise.
+func (s *WorkspaceService) Meta(ctx context.Context,
+	nameOrID *string,
+) (*WorkspaceMeta, error) {
+	if isEmptyString(nameOrID) {
+		return nil, fmt.Errorf("nameOrID cannot be nil for Meta operation")
+	}
+
+	endpoint := fmt.Sprintf("/workspaces/%v/meta", *nameOrID)
+	req, err := s.client.NewRequest("GET", endpoint, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta WorkspaceMeta
+	_, err = s.client.Do(ctx, req, &meta)
+	if err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
 // AddEntities adds entity ids given as a a comma delimited string
 // to a given workspace in Kong. The response is a representation
 // of the entity that was added to the workspace.
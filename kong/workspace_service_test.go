@@ -212,3 +212,55 @@ func TestWorkspaceService_Entities(T *testing.T) {
 	err = client.Workspaces.Delete(defaultCtx, createdWorkspace.ID)
 	assert.NoError(err)
 }
+
+func TestWorkspaceServiceCount(T *testing.T) {
+	assert := assert.New(T)
+	require := require.New(T)
+
+	client, err := NewTestClient(nil, nil)
+	assert.NoError(err)
+	assert.NotNil(client)
+
+	createdWorkspaceA, err := client.Workspaces.Create(defaultCtx, &Workspace{Name: String("teamA")})
+	require.NoError(err)
+	createdWorkspaceB, err := client.Workspaces.Create(defaultCtx, &Workspace{Name: String("teamB")})
+	require.NoError(err)
+
+	count, err := client.Workspaces.Count(defaultCtx)
+	require.NoError(err)
+	// Counts default workspace
+	assert.Equal(3, count)
+
+	err = client.Workspaces.Delete(defaultCtx, createdWorkspaceA.ID)
+	require.NoError(err)
+	err = client.Workspaces.Delete(defaultCtx, createdWorkspaceB.ID)
+	require.NoError(err)
+}
+
+func TestWorkspaceServiceMeta(T *testing.T) {
+	assert := assert.New(T)
+	require := require.New(T)
+
+	client, err := NewTestClient(nil, nil)
+	assert.NoError(err)
+	assert.NotNil(client)
+
+	createdService, err := client.Services.Create(defaultCtx, &Service{
+		Name: String("foo"),
+		Host: String("upstream"),
+	})
+	require.NoError(err)
+
+	meta, err := client.Workspaces.Meta(defaultCtx, String("default"))
+	require.NoError(err)
+	require.NotNil(meta)
+	assert.Equal(1, meta.Counts["services"])
+
+	_, err = client.Workspaces.Meta(defaultCtx, String("unknown"))
+	assert.True(IsNotFoundErr(err))
+	_, err = client.Workspaces.Meta(defaultCtx, nil)
+	assert.Error(err)
+
+	err = client.Services.Delete(defaultCtx, createdService.ID)
+	require.NoError(err)
+}
@@ -200,6 +200,153 @@ func (in *Admin) DeepCopy() *Admin {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditObject) DeepCopyInto(out *AuditObject) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.RequestID != nil {
+		in, out := &in.RequestID, &out.RequestID
+		*out = new(string)
+		**out = **in
+	}
+	if in.RequestTimestamp != nil {
+		in, out := &in.RequestTimestamp, &out.RequestTimestamp
+		*out = new(int)
+		**out = **in
+	}
+	if in.DAOName != nil {
+		in, out := &in.DAOName, &out.DAOName
+		*out = new(string)
+		**out = **in
+	}
+	if in.Operation != nil {
+		in, out := &in.Operation, &out.Operation
+		*out = new(string)
+		**out = **in
+	}
+	if in.EntityKey != nil {
+		in, out := &in.EntityKey, &out.EntityKey
+		*out = new(string)
+		**out = **in
+	}
+	if in.Entity != nil {
+		in, out := &in.Entity, &out.Entity
+		*out = new(string)
+		**out = **in
+	}
+	if in.RBACUserID != nil {
+		in, out := &in.RBACUserID, &out.RBACUserID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Signature != nil {
+		in, out := &in.Signature, &out.Signature
+		*out = new(string)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditObject.
+func (in *AuditObject) DeepCopy() *AuditObject {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditObject)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AuditRequest) DeepCopyInto(out *AuditRequest) {
+	*out = *in
+	if in.RequestID != nil {
+		in, out := &in.RequestID, &out.RequestID
+		*out = new(string)
+		**out = **in
+	}
+	if in.RequestTimestamp != nil {
+		in, out := &in.RequestTimestamp, &out.RequestTimestamp
+		*out = new(int)
+		**out = **in
+	}
+	if in.ClientIP != nil {
+		in, out := &in.ClientIP, &out.ClientIP
+		*out = new(string)
+		**out = **in
+	}
+	if in.Method != nil {
+		in, out := &in.Method, &out.Method
+		*out = new(string)
+		**out = **in
+	}
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		*out = new(string)
+		**out = **in
+	}
+	if in.Payload != nil {
+		in, out := &in.Payload, &out.Payload
+		*out = new(string)
+		**out = **in
+	}
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(int)
+		**out = **in
+	}
+	if in.RBACUserID != nil {
+		in, out := &in.RBACUserID, &out.RBACUserID
+		*out = new(string)
+		**out = **in
+	}
+	if in.RBACUserName != nil {
+		in, out := &in.RBACUserName, &out.RBACUserName
+		*out = new(string)
+		**out = **in
+	}
+	if in.RequestSource != nil {
+		in, out := &in.RequestSource, &out.RequestSource
+		*out = new(string)
+		**out = **in
+	}
+	if in.Workspace != nil {
+		in, out := &in.Workspace, &out.Workspace
+		*out = new(string)
+		**out = **in
+	}
+	if in.Signature != nil {
+		in, out := &in.Signature, &out.Signature
+		*out = new(string)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AuditRequest.
+func (in *AuditRequest) DeepCopy() *AuditRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(AuditRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BasicAuth) DeepCopyInto(out *BasicAuth) {
 	*out = *in
@@ -440,6 +587,93 @@ func (in *Consumer) DeepCopy() *Consumer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsumerCredentials) DeepCopyInto(out *ConsumerCredentials) {
+	*out = *in
+	if in.KeyAuths != nil {
+		in, out := &in.KeyAuths, &out.KeyAuths
+		*out = make([]*KeyAuth, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(KeyAuth)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.BasicAuths != nil {
+		in, out := &in.BasicAuths, &out.BasicAuths
+		*out = make([]*BasicAuth, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(BasicAuth)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.HMACAuths != nil {
+		in, out := &in.HMACAuths, &out.HMACAuths
+		*out = make([]*HMACAuth, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(HMACAuth)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.JWTAuths != nil {
+		in, out := &in.JWTAuths, &out.JWTAuths
+		*out = make([]*JWTAuth, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(JWTAuth)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.ACLGroups != nil {
+		in, out := &in.ACLGroups, &out.ACLGroups
+		*out = make([]*ACLGroup, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ACLGroup)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.Oauth2Credentials != nil {
+		in, out := &in.Oauth2Credentials, &out.Oauth2Credentials
+		*out = make([]*Oauth2Credential, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(Oauth2Credential)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	if in.HashedSecrets != nil {
+		in, out := &in.HashedSecrets, &out.HashedSecrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsumerCredentials.
+func (in *ConsumerCredentials) DeepCopy() *ConsumerCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsumerCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConsumerGroup) DeepCopyInto(out *ConsumerGroup) {
 	*out = *in
@@ -620,6 +854,62 @@ func (in *ConsumerGroupRLA) DeepCopy() *ConsumerGroupRLA {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DataPlane) DeepCopyInto(out *DataPlane) {
+	*out = *in
+	if in.ID != nil {
+		in, out := &in.ID, &out.ID
+		*out = new(string)
+		**out = **in
+	}
+	if in.Hostname != nil {
+		in, out := &in.Hostname, &out.Hostname
+		*out = new(string)
+		**out = **in
+	}
+	if in.IP != nil {
+		in, out := &in.IP, &out.IP
+		*out = new(string)
+		**out = **in
+	}
+	if in.Version != nil {
+		in, out := &in.Version, &out.Version
+		*out = new(string)
+		**out = **in
+	}
+	if in.SyncStatus != nil {
+		in, out := &in.SyncStatus, &out.SyncStatus
+		*out = new(string)
+		**out = **in
+	}
+	if in.ConfigHash != nil {
+		in, out := &in.ConfigHash, &out.ConfigHash
+		*out = new(string)
+		**out = **in
+	}
+	if in.LastSeen != nil {
+		in, out := &in.LastSeen, &out.LastSeen
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataPlane.
+func (in *DataPlane) DeepCopy() *DataPlane {
+	if in == nil {
+		return nil
+	}
+	out := new(DataPlane)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DegraphqlRoute) DeepCopyInto(out *DegraphqlRoute) {
 	*out = *in
@@ -1555,6 +1845,11 @@ func (in *Plugin) DeepCopyInto(out *Plugin) {
 		*out = new(Consumer)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ConsumerGroup != nil {
+		in, out := &in.ConsumerGroup, &out.ConsumerGroup
+		*out = new(ConsumerGroup)
+		(*in).DeepCopyInto(*out)
+	}
 	out.Config = in.Config.DeepCopy()
 	if in.Enabled != nil {
 		in, out := &in.Enabled, &out.Enabled
@@ -2462,6 +2757,38 @@ func (in *Upstream) DeepCopy() *Upstream {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpstreamHealth) DeepCopyInto(out *UpstreamHealth) {
+	*out = *in
+	if in.Upstream != nil {
+		in, out := &in.Upstream, &out.Upstream
+		*out = new(Upstream)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]*UpstreamNodeHealth, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(UpstreamNodeHealth)
+				(*in).DeepCopyInto(*out)
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpstreamHealth.
+func (in *UpstreamHealth) DeepCopy() *UpstreamHealth {
+	if in == nil {
+		return nil
+	}
+	out := new(UpstreamHealth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpstreamNodeHealth) DeepCopyInto(out *UpstreamNodeHealth) {
 	*out = *in
@@ -2627,3 +2954,26 @@ func (in *WorkspaceEntity) DeepCopy() *WorkspaceEntity {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceMeta) DeepCopyInto(out *WorkspaceMeta) {
+	*out = *in
+	if in.Counts != nil {
+		in, out := &in.Counts, &out.Counts
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceMeta.
+func (in *WorkspaceMeta) DeepCopy() *WorkspaceMeta {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceMeta)
+	in.DeepCopyInto(out)
+	return out
+}